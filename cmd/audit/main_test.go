@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/auditchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	cw := auditchain.NewWriter(f, auditchain.State{}, []byte("key"), 2)
+	for range 3 {
+		_, err := cw.Write([]byte(`{"action":"follow"}`))
+		require.NoError(t, err)
+	}
+
+	// без Sync последняя запись не подписана.
+	unsigned := filepath.Join(t.TempDir(), "unsigned.log")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(unsigned, data, 0o600))
+
+	require.NoError(t, cw.Sync())
+	require.NoError(t, f.Close())
+
+	tests := []struct {
+		wantErr error
+		name    string
+		key     string
+		args    []string
+		every   int
+	}{
+		{name: "empty", wantErr: ErrUnknownCommand},
+		{name: "unknown", args: []string{"sign"}, wantErr: ErrUnknownCommand},
+		{name: "no files", args: []string{"verify"}, wantErr: ErrMissingFiles},
+		{name: "valid", key: "key", every: 2, args: []string{"verify", path}},
+		{name: "wrong key", key: "other", every: 2, args: []string{"verify", path}, wantErr: auditchain.ErrBadCheckpoint},
+		{name: "unsigned tail", key: "key", every: 2, args: []string{"verify", unsigned}, wantErr: auditchain.ErrMissingCheckpoint},
+		{name: "rare checkpoints", key: "key", every: 1, args: []string{"verify", path}, wantErr: auditchain.ErrMissingCheckpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := run(&out, []byte(tt.key), tt.every, tt.args)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "OK: 3 records, 2 checkpoints")
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package main provide cmd entree point for database migrations.
+//
+// Usage:
+//
+//	migrate [-d DATABASE_DSN] [-log_level LEVEL] <command> [args]
+//
+// Commands:
+//
+//	up           apply all pending migrations
+//	up-to N      apply pending migrations up to version N
+//	down         roll back the latest applied migration
+//	redo         roll back the latest applied migration and apply it again
+//	status       print state of all migrations
+//	version      print current database version
+//
+// Database password may be read from file named by DATABASE_PASSWORD_FILE env.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+
+	//nolint необходимо получать SIGTERM для остановки процесса.
+	"syscall"
+
+	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/config/dbconf"
+	"github.com/Pklerik/urlshortener/internal/logger"
+	dbrepo "github.com/Pklerik/urlshortener/internal/repository/db"
+	"github.com/Pklerik/urlshortener/migrations"
+	"github.com/pressly/goose/v3"
+)
+
+var (
+	// ErrUnknownCommand command is not supported.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrMissingVersion up-to command called without version.
+	ErrMissingVersion = errors.New("up-to requires target version")
+)
+
+var commands = map[string]struct{}{
+	"up": {}, "up-to": {}, "down": {}, "redo": {}, "status": {}, "version": {},
+}
+
+func main() {
+	dbConf := new(dbconf.Conf)
+	flag.Var(dbConf, "d", "Database login DNS string, default from DATABASE_DSN env")
+	logLevel := flag.String("log_level", "info", "Custom logging level. Default: INFO")
+	flag.Usage = usage
+	flag.Parse()
+
+	if err := logger.Initialize(*logLevel); err != nil {
+		log.Fatalf("Unable to setup logger: %v", err)
+	}
+
+	if dbConf.RawString == "" {
+		if err := dbConf.Set(os.Getenv("DATABASE_DSN")); err != nil {
+			log.Fatalf("Unable to parse database DSN: %v", err)
+		}
+	}
+
+	if path := os.Getenv("DATABASE_PASSWORD_FILE"); path != "" {
+		password, err := config.ReadSecretFile(path)
+		if err != nil {
+			log.Fatalf("Unable to read database password: %v", err)
+		}
+
+		dbConf.Password = password
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, dbConf, flag.Args()); err != nil {
+		stop()
+		log.Fatalf("migrate: %v", err)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] up|up-to N|down|redo|status|version\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func run(ctx context.Context, dbConf dbconf.DBConfigurer, args []string) error {
+	if err := validateArgs(args); err != nil {
+		return err
+	}
+
+	db, err := dbrepo.ConnectDB(dbConf)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close()
+
+	// status и version только читают таблицу версий и не ждут мигрирующий экземпляр.
+	switch args[0] {
+	case "status":
+		return printStatus(ctx, db, dbConf)
+	case "version":
+		version, _, err := migrations.Versions(ctx, db, dbConf)
+		if err != nil {
+			return fmt.Errorf("version: %w", err)
+		}
+
+		fmt.Fprintf(os.Stdout, "version: %d\n", version)
+
+		return nil
+	}
+
+	// команды выполняются под блокировкой миграций, поэтому down и up в redo идут без других мигрирующих экземпляров.
+	return migrations.Migrate(ctx, db, dbConf, func(ctx context.Context, provider *goose.Provider) error {
+		return runCommand(ctx, provider, args)
+	})
+}
+
+// runCommand runs changing migrate command with provider.
+func runCommand(ctx context.Context, provider *goose.Provider, args []string) error {
+	switch args[0] {
+	case "up":
+		results, err := provider.Up(ctx)
+		printResults(results)
+
+		return wrap("up", err)
+	case "up-to":
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("up-to version: %w", err)
+		}
+
+		results, err := provider.UpTo(ctx, version)
+		printResults(results)
+
+		return wrap("up-to", err)
+	case "down":
+		result, err := provider.Down(ctx)
+		if result != nil {
+			printResults([]*goose.MigrationResult{result})
+		}
+
+		return wrap("down", err)
+	case "redo":
+		results, err := migrations.Redo(ctx, provider)
+		printResults(results)
+
+		return wrap("redo", err)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+	}
+}
+
+func validateArgs(args []string) error {
+	if len(args) == 0 {
+		return ErrUnknownCommand
+	}
+
+	if _, ok := commands[args[0]]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+	}
+
+	if args[0] == "up-to" && len(args) < 2 {
+		return ErrMissingVersion
+	}
+
+	return nil
+}
+
+func wrap(command string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+
+	return nil
+}
+
+func printResults(results []*goose.MigrationResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stdout, "no migrations to apply")
+		return
+	}
+
+	for _, result := range results {
+		fmt.Fprintln(os.Stdout, result.String())
+	}
+}
+
+func printStatus(ctx context.Context, db *sql.DB, dbConf dbconf.DBConfigurer) error {
+	statuses, err := migrations.Status(ctx, db, dbConf)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	return nil
+}
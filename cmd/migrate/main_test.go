@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateArgs(t *testing.T) {
+	tests := []struct {
+		wantErr error
+		name    string
+		args    []string
+	}{
+		{name: "empty", args: nil, wantErr: ErrUnknownCommand},
+		{name: "unknown", args: []string{"sideways"}, wantErr: ErrUnknownCommand},
+		{name: "up-to without version", args: []string{"up-to"}, wantErr: ErrMissingVersion},
+		{name: "up-to", args: []string{"up-to", "20250924113912"}},
+		{name: "up", args: []string{"up"}},
+		{name: "redo", args: []string{"redo"}},
+		{name: "status", args: []string{"status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateArgs(tt.args)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+var (
+	// ErrUnknownCommand config command is not supported.
+	ErrUnknownCommand = errors.New("unknown command, use: config validate|print [-effective] [flags]")
+	// ErrNoConfigFile config file is required but not set.
+	ErrNoConfigFile = errors.New("config file is not set, use -c flag or CONFIG env")
+)
+
+// runConfig runs config command:
+//
+//	config validate [flags]           check config merged from flags, envs and config file.
+//	config print [-effective] [flags] print config file values, or merged values with their source.
+//
+// Secrets are masked on print.
+func runConfig(out io.Writer, args []string) error {
+	if len(args) == 0 || (args[0] != "validate" && args[0] != "print") {
+		return ErrUnknownCommand
+	}
+
+	fs := flag.NewFlagSet("config "+args[0], flag.ContinueOnError)
+	fs.SetOutput(out)
+
+	effective := false
+	if args[0] == "print" {
+		fs.BoolVar(&effective, "effective", false, "print values merged from flags, envs and config file with source of each value")
+	}
+
+	flagArgs, setFields, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return fmt.Errorf("runConfig: %w", err)
+	}
+
+	src := newArgSources(flagArgs, setFields)
+
+	switch {
+	case args[0] == "validate":
+		return validateConfig(out, src)
+	case effective:
+		return printEffective(out, src)
+	default:
+		return printFile(out, src.flags.FileConfig)
+	}
+}
+
+func validateConfig(out io.Writer, src *argSources) error {
+	parsedArgs, _, err := src.load()
+	if err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	errs := []error{parsedArgs.Valid()}
+	if parsedArgs.DBConf != nil && parsedArgs.DBConf.RawString != "" {
+		_, err := parsedArgs.GetDatabaseConf()
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	fmt.Fprintln(out, "OK: config is valid")
+
+	return nil
+}
+
+func printEffective(out io.Writer, src *argSources) error {
+	parsedArgs, sources, err := src.load()
+	if err != nil {
+		return fmt.Errorf("print: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+
+	for _, s := range parsedArgs.Settings() {
+		// значения аудита берутся из одного источника целиком.
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, sources[strings.Split(s.Key, ".")[0]])
+	}
+
+	return w.Flush()
+}
+
+func printFile(out io.Writer, fileConfig string) error {
+	if fileConfig == "" {
+		return ErrNoConfigFile
+	}
+
+	configArgs, err := readConfig(fileConfig)
+	if err != nil {
+		return fmt.Errorf("print: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE")
+
+	for _, s := range configArgs.Settings() {
+		if s.Value == "" || s.Value == "0" || s.Value == "false" {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s\n", s.Key, s.Value)
+	}
+
+	return w.Flush()
+}
@@ -2,101 +2,361 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"reflect" // nolint:depguard // used for dynamic field access
 	"strings"
 
-	"github.com/goccy/go-json"
-
 	"github.com/Pklerik/urlshortener/internal/config"
 	"github.com/Pklerik/urlshortener/internal/config/audit"
 	"github.com/Pklerik/urlshortener/internal/config/dbconf"
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
 	"github.com/Pklerik/urlshortener/internal/dictionary"
 	"github.com/caarlos0/env/v11"
 )
 
-func parseArgs() config.StartupFlagsParser {
-	parsedArgs := parseFlags()
-	envArgs := parseEnvs()
+// Sources of config values.
+const (
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+// argSources parsed flags and envs, config file is read on each load.
+type argSources struct {
+	flags *config.StartupFlags
+	envs  *config.StartupFlags
+	// setFields names of config fields set by flags passed explicitly.
+	setFields map[string]bool
+	// dbPassword from DATABASE_PASSWORD_FILE, replaces password of database DSN.
+	dbPassword string
+}
+
+// parseArgs returns config merged from flags, envs and config file, in order of priority.
+// load merges the same envs and flags with config file read again, it is used for config reload.
+func parseArgs() (parsedArgs *config.StartupFlags, load func() (*config.StartupFlags, error)) {
+	flagArgs, setFields, err := parseFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalf("parseArgs: %v", err)
+	}
+
+	src := newArgSources(flagArgs, setFields)
+
+	parsedArgs, _, err = src.load()
+	if err != nil {
+		log.Fatalf("parseArgs: %v", err)
+	}
+
+	if err := parsedArgs.Valid(); err != nil {
+		log.Fatalf("parseArgs: %v", err)
+	}
+
+	load = func() (*config.StartupFlags, error) {
+		sf, _, err := src.load()
+		return sf, err
+	}
+
+	return parsedArgs, load
+}
+
+func newArgSources(flagArgs *config.StartupFlags, setFields map[string]bool) *argSources {
+	envArgs, dbPassword := parseEnvs()
+
+	if envArgs.FileConfig != "" && !setFields["FileConfig"] {
+		flagArgs.FileConfig = envArgs.FileConfig
+	}
+
+	return &argSources{flags: flagArgs, setFields: setFields, envs: envArgs, dbPassword: dbPassword}
+}
+
+// load reads config file and merges it with flags and envs.
+// sources holds source of each value keyed by config file key.
+func (s *argSources) load() (parsedArgs *config.StartupFlags, sources map[string]string, err error) {
+	configArgs, err := readConfig(s.flags.FileConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsedArgs, sources = mergeArgs(s.flags, s.setFields, s.envs, configArgs)
 
-	if envArgs.FileConfig != "" {
-		parsedArgs.FileConfig = envArgs.FileConfig
+	// DBConf копируется: указатель общий с флагами, которые сливаются заново при перезагрузке.
+	if s.dbPassword != "" && parsedArgs.DBConf != nil && parsedArgs.DBConf.RawString != "" {
+		dbConf := *parsedArgs.DBConf
+		dbConf.Password = s.dbPassword
+		parsedArgs.DBConf = &dbConf
 	}
 
-	configArgs := parseConfig(parsedArgs.FileConfig)
+	return parsedArgs, sources, nil
+}
+
+// mergeArgs returns config where each field is taken from explicitly set flags, envs,
+// config file or flags defaults, in order of priority. setFields holds names of fields set by flags.
+func mergeArgs(flagArgs *config.StartupFlags, setFields map[string]bool, envArgs, configArgs *config.StartupFlags) (*config.StartupFlags, map[string]string) {
+	parsedArgs := *flagArgs
+	sources := make(map[string]string)
+
+	t := reflect.TypeOf(envArgs).Elem()
+	for field := range t.NumField() {
+		dst := reflect.ValueOf(&parsedArgs).Elem().Field(field)
+		key := t.Field(field).Tag.Get("json")
+
+		// флаг, заданный явно, перекрывает всё, даже если равен значению по умолчанию.
+		if setFields[t.Field(field).Name] {
+			sources[key] = sourceFlag
+			continue
+		}
 
-	for field := range reflect.TypeOf(envArgs).Elem().NumField() {
 		// check if env variable is set
-		v := reflect.ValueOf(envArgs).Elem().Field(field)
-		if !v.IsZero() {
+		if v := reflect.ValueOf(envArgs).Elem().Field(field); !v.IsZero() {
 			// replaces parsedArgs field with env variable
-			reflect.ValueOf(parsedArgs).Elem().Field(field).Set(v)
-		} else {
-			// check if  parsedArgs variable is set
-			v := reflect.ValueOf(parsedArgs).Elem().Field(field)
-			if v.IsZero() {
-				// replaces parsedArgs field with configArgs variable
-				v.Set(reflect.ValueOf(configArgs).Elem().Field(field))
+			dst.Set(v)
+
+			sources[key] = sourceEnv
+
+			continue
+		}
+
+		sources[key] = sourceDefault
+
+		if v := reflect.ValueOf(configArgs).Elem().Field(field); !v.IsZero() {
+			// replaces parsedArgs field with configArgs variable
+			dst.Set(v)
+
+			sources[key] = sourceFile
+		}
+	}
+
+	delete(sources, "")
+
+	return &parsedArgs, sources
+}
+
+// parseFlags returns args parsed with fs and names of config fields set by flags passed in args.
+func parseFlags(fs *flag.FlagSet, args []string) (parsedArgs *config.StartupFlags, setFields map[string]bool, err error) {
+	parsedArgs = defineFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("parseFlags: %w", err)
+	}
+
+	fields := flagFields(fs, parsedArgs)
+	setFields = make(map[string]bool)
+
+	fs.Visit(func(f *flag.Flag) {
+		if field, ok := fields[f.Name]; ok {
+			setFields[field] = true
+		}
+	})
+
+	return parsedArgs, setFields, nil
+}
+
+// flagFields returns name of parsedArgs field for each flag of fs, found by address flag value points to.
+// Flag of nested config (audit, tls) is mapped to field of the whole config, it is merged from one source.
+func flagFields(fs *flag.FlagSet, parsedArgs *config.StartupFlags) map[string]string {
+	addrs := make(map[uintptr]string)
+
+	v := reflect.ValueOf(parsedArgs).Elem()
+	for i := range v.NumField() {
+		field, name := v.Field(i), v.Type().Field(i).Name
+		addrs[field.Addr().Pointer()] = name
+
+		if field.Kind() != reflect.Pointer || field.IsNil() {
+			continue
+		}
+
+		addrs[field.Pointer()] = name
+
+		if elem := field.Elem(); elem.Kind() == reflect.Struct {
+			for j := range elem.NumField() {
+				addrs[elem.Field(j).Addr().Pointer()] = name
 			}
 		}
 	}
 
-	return parsedArgs
+	fields := make(map[string]string)
+
+	fs.VisitAll(func(f *flag.Flag) {
+		if v := reflect.ValueOf(f.Value); v.Kind() == reflect.Pointer {
+			if name, ok := addrs[v.Pointer()]; ok {
+				fields[f.Name] = name
+			}
+		}
+	})
+
+	return fields
 }
 
-func parseFlags() *config.StartupFlags {
+// defineFlags defines all flags in fs, returned config holds defaults until fs is parsed.
+func defineFlags(fs *flag.FlagSet) *config.StartupFlags {
 	parsedArgs := new(config.StartupFlags)
 	parsedArgs.ServerAddress = new(config.Address)
 	parsedArgs.DBConf = new(dbconf.Conf)
 	parsedArgs.Audit = new(audit.Audit)
-	flag.Var(parsedArgs.ServerAddress, "a", "address and port to run server")
-	flag.StringVar(&parsedArgs.BaseURL, "b", "http://localhost:8080", "protocol://address:port for shortened urls")
-	flag.Float64Var(&parsedArgs.Timeout, "timeout", 600, "Custom timeout. Example: --timeout 635.456 sets timeout to 635.456 seconds. Default: 600s")
-	flag.StringVar(&parsedArgs.LogLevel, "log_level", "info", "Custom logging level. Default: INFO")
-	flag.StringVar(&parsedArgs.LocalStorage, "f", "local_storage.json", "Custom local file location for data storage")
-	flag.Var(parsedArgs.DBConf, "d", "Database login DNS string")
-	flag.StringVar(&parsedArgs.SecretKey, "secret_key", "secret_key", "Secret key for crypto")
-	flag.StringVar(&parsedArgs.Audit.LogFilePath, "audit_file", "", "File path for audit log")
-	flag.StringVar(&parsedArgs.Audit.LogURLPath, "audit_url", "", "URL path for audit log")
-	flag.BoolVar(&parsedArgs.TLS, "s", false, "use tls Listener ")
-	flag.StringVar(&parsedArgs.FileConfig, "c", "", "path to config json file")
-	flag.Parse()
+	parsedArgs.TLSConf = new(tlsconf.Conf)
+	fs.Var(parsedArgs.ServerAddress, "a", "host:port to run server, unix:///path for Unix socket or systemd://[name] for socket passed by systemd")
+	fs.StringVar(&parsedArgs.SocketMode, "socket_mode", "", "Octal permissions of created Unix sockets. Default: 0660")
+	fs.StringVar(&parsedArgs.BaseURL, "b", "http://localhost:8080", "protocol://address:port for shortened urls")
+	fs.Float64Var(&parsedArgs.Timeout, "timeout", 600, "Custom timeout. Example: --timeout 635.456 sets timeout to 635.456 seconds. Default: 600s")
+	fs.StringVar(&parsedArgs.LogLevel, "log_level", "info", "Custom logging level. Default: INFO")
+	fs.StringVar(&parsedArgs.LocalStorage, "f", "local_storage.json", "Custom local file location for data storage")
+	fs.Var(parsedArgs.DBConf, "d", "Database login DNS string")
+	fs.Var(&parsedArgs.DBReplicas, "dr", "Comma separated read only database replicas DNS strings")
+	fs.Float64Var(&parsedArgs.ReadYourWrites, "read_your_writes", 0, "Seconds after user writes when user reads go to primary database. Default: 0 (disabled)")
+	fs.StringVar(&parsedArgs.SecretKey, "secret_key", config.DefaultSecretKey, "Secret key for crypto, default is refused in production. Prefer SECRET_KEY_FILE env")
+	fs.StringVar(&parsedArgs.Environment, "environment", config.EnvDevelopment, "Environment: development or production")
+	fs.StringVar(&parsedArgs.Audit.LogFilePath, "audit_file", "", "File path for audit log")
+	fs.StringVar(&parsedArgs.Audit.LogURLPath, "audit_url", "", "URL path for audit log")
+	fs.StringVar(&parsedArgs.Audit.SpillFilePath, "audit_spill_file", "", "File for audit entries which can't be delivered to audit URL, empty drops them")
+	fs.IntVar(&parsedArgs.Audit.SpillMaxSizeMB, "audit_spill_max_size", 0, "Size of not delivered audit entries in spill file in megabytes, negative disables limit. Default: 100")
+	fs.IntVar(&parsedArgs.Audit.QueueSize, "audit_queue_size", 0, "Size of queue of every remote audit sink: http, syslog, unix. Default: 4096")
+	fs.IntVar(&parsedArgs.Audit.BatchSize, "audit_batch_size", 0, "Max audit entries in one request to audit URL. Default: 100")
+	fs.IntVar(&parsedArgs.Audit.MaxRetries, "audit_max_retries", 0, "Retries of failed request to audit URL, negative disables retries. Default: 3")
+	fs.Float64Var(&parsedArgs.Audit.FlushInterval, "audit_flush_interval", 0, "Max seconds audit entry waits before sending to audit URL. Default: 1s")
+	fs.BoolVar(&parsedArgs.Audit.HashChain, "audit_hash_chain", false, "Add sequence numbers and previous record hashes to audit file records")
+	fs.StringVar(&parsedArgs.Audit.ChainKey, "audit_chain_key", "", "Key for signed checkpoints of audit hash chain, empty disables checkpoints")
+	fs.IntVar(&parsedArgs.Audit.CheckpointEvery, "audit_checkpoint_every", 0, "Audit records between signed checkpoints. Default: 100")
+	fs.IntVar(&parsedArgs.Audit.MaxSizeMB, "audit_max_size", 0, "Audit file size in megabytes after which it is rotated, negative disables. Default: 100")
+	fs.Float64Var(&parsedArgs.Audit.RotateInterval, "audit_rotate_interval", 0, "Seconds after which audit file is rotated, 0 disables")
+	fs.IntVar(&parsedArgs.Audit.MaxBackups, "audit_max_backups", 0, "Count of rotated audit files kept, 0 keeps all")
+	fs.Float64Var(&parsedArgs.Audit.MaxAge, "audit_max_age", 0, "Seconds rotated audit files are kept, 0 keeps all")
+	fs.BoolVar(&parsedArgs.Audit.Compress, "audit_compress", true, "Gzip rotated audit files")
+	fs.BoolVar(&parsedArgs.TLS, "s", false, "use tls Listener ")
+	fs.StringVar(&parsedArgs.TLSConf.CertFile, "tls_cert", "", "TLS certificate file, reloaded on change. Self-signed certificate is generated if empty")
+	fs.StringVar(&parsedArgs.TLSConf.KeyFile, "tls_key", "", "TLS private key file, reloaded on change")
+	fs.StringVar(&parsedArgs.TLSConf.MinVersion, "tls_min_version", "", "Lowest accepted TLS version: 1.2 or 1.3. Default: 1.2")
+	fs.Var((*listFlag)(&parsedArgs.TLSConf.CipherSuites), "tls_cipher_suites", "Comma separated TLS 1.2 cipher suites, Go defaults if empty")
+	fs.StringVar(&parsedArgs.TLSConf.ClientCAFile, "tls_client_ca", "", "CA bundle for client certificates, enables mTLS for /api/admin and /debug")
+	fs.Var((*listFlag)(&parsedArgs.TLSConf.SelfSignedHosts), "tls_self_signed_hosts", "Comma separated DNS names and IPs of self-signed certificate. Default: localhost,127.0.0.1,::1")
+	fs.StringVar(&parsedArgs.TLSConf.HTTPRedirectAddress, "http_redirect_address", "", "Address of plaintext listener redirecting to HTTPS, empty disables it. Example: :80")
+	fs.IntVar(&parsedArgs.TLSConf.HSTSMaxAge, "hsts_max_age", 0, "Seconds of Strict-Transport-Security header, negative disables it. Default: 31536000")
+	fs.BoolVar(&parsedArgs.TLSConf.HSTSPreload, "hsts_preload", false, "Add includeSubDomains and preload to Strict-Transport-Security header")
+	fs.StringVar(&parsedArgs.FileConfig, "c", "", "path to config file: .json, .yaml, .yml or .toml")
+	fs.Float64Var(&parsedArgs.RestoreWindow, "restore_window", 86400, "Seconds after deletion when owner can restore link. Default: 86400s")
+	fs.Float64Var(&parsedArgs.PurgeRetention, "purge_retention", 2592000, "Seconds after deletion when link is purged permanently. Default: 2592000s")
+	fs.Float64Var(&parsedArgs.PurgeInterval, "purge_interval", 3600, "Seconds between purge job runs, 0 disables purge job. Default: 3600s")
+	fs.StringVar(&parsedArgs.AdminToken, "admin_token", "", "Bearer token for admin endpoints, empty disables them")
+	fs.StringVar(&parsedArgs.AdminAddress, "admin_address", "", "Address of admin listener serving /metrics, health probes, /api/admin and /debug, empty serves admin API on server address. Example: localhost:9090, unix:///run/shortener-admin.sock")
+	fs.StringVar(&parsedArgs.MetricsAddress, "metrics_address", "", "Address of listener serving only /metrics, empty disables it. Example: localhost:9091, systemd://metrics")
+	fs.Float64Var(&parsedArgs.ShutdownTime, "shutdown_timeout", 30, "Seconds given to background tasks on graceful shutdown. Default: 30s")
+	fs.Float64Var(&parsedArgs.ShutdownDelay, "shutdown_delay", 5, "Seconds /readyz fails before listeners are closed on graceful shutdown, so load balancer stops sending requests. Default: 5s")
+	fs.Float64Var(&parsedArgs.ClickSample, "webhook_click_sample_rate", 0.1, "Share of redirects sent to webhooks as link.clicked events, from 0 to 1. Default: 0.1")
+	fs.IntVar(&parsedArgs.JobWorkers, "job_workers", 4, "Number of background job workers. Default: 4")
+	fs.IntVar(&parsedArgs.JobMaxAttempts, "job_max_attempts", 5, "Attempts before background job is marked as failed. Default: 5")
+	fs.StringVar(&parsedArgs.TraceExporter, "trace_exporter", "", "Trace exporter: otlp, stdout or file, empty disables tracing")
+	fs.StringVar(&parsedArgs.TraceEndpoint, "trace_endpoint", "", "OTLP/HTTP traces url for otlp exporter, file path for file exporter. Default: http://localhost:4318/v1/traces")
+	fs.Float64Var(&parsedArgs.TraceSample, "trace_sample_ratio", 1, "Share of new traces which are exported, from 0 to 1. Default: 1")
+	fs.Float64Var(&parsedArgs.RedirectLog, "redirect_log_sample_rate", 1, "Share of redirect responses written to access log, from 0 to 1. Default: 1")
+	fs.StringVar(&parsedArgs.DataDir, "data_dir", "", "Directory for relative audit file paths, working directory if empty")
+	fs.StringVar(&parsedArgs.JobsFile, "jobs_file", "", "Custom local file location for background jobs, derived from -f if empty")
+	fs.BoolVar(&parsedArgs.SkipMigrations, "skip_migrations", false, "do not apply database migrations on startup, use cmd/migrate instead")
 
 	return parsedArgs
 }
 
-func parseEnvs() *config.StartupFlags {
-	envArgs := new(config.StartupFlags)
+// listFlag comma separated flag value.
+type listFlag []string
+
+// String returns comma separated items.
+func (l *listFlag) String() string {
+	if l == nil {
+		return ""
+	}
+
+	return strings.Join(*l, ",")
+}
+
+// Set replaces items with comma separated value.
+func (l *listFlag) Set(value string) error {
+	*l = nil
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+
+	return nil
+}
+
+// parseEnvs returns config from envs and database password from DATABASE_PASSWORD_FILE.
+func parseEnvs() (envArgs *config.StartupFlags, dbPassword string) {
+	envArgs = new(config.StartupFlags)
 	envArgs.Audit = new(audit.Audit)
+	envArgs.TLSConf = new(tlsconf.Conf)
 
 	err := env.Parse(envArgs)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	return envArgs
-}
+	dbPassword, err = readSecretFiles(envArgs)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-func parseConfig(fileConfig string) *config.StartupFlags {
-	configArgs := new(config.StartupFlags)
+	// без AUDIT_* переменных пустой конфиг аудита не должен заменять флаги.
+	if reflect.ValueOf(*envArgs.Audit).IsZero() {
+		envArgs.Audit = nil
+	}
 
-	fullPath := fileConfig
-	if !strings.HasPrefix(fileConfig, "/") {
-		fullPath = filepath.Clean(filepath.Join(dictionary.BasePath, fileConfig))
+	if reflect.ValueOf(*envArgs.TLSConf).IsZero() {
+		envArgs.TLSConf = nil
 	}
 
-	if fileConfig != "" {
-		data, err := os.ReadFile(filepath.Clean(fullPath))
-		if err != nil {
-			return configArgs
+	return envArgs, dbPassword
+}
+
+// readSecretFiles sets secrets from files named by *_FILE envs, as Docker and Kubernetes secrets are mounted.
+// Secret set both by env and by file is an error.
+func readSecretFiles(envArgs *config.StartupFlags) (dbPassword string, err error) {
+	for _, secret := range []struct {
+		dst *string
+		env string
+	}{
+		{env: "SECRET_KEY_FILE", dst: &envArgs.SecretKey},
+		{env: "ADMIN_TOKEN_FILE", dst: &envArgs.AdminToken},
+		{env: "DATABASE_PASSWORD_FILE", dst: &dbPassword},
+		{env: "AUDIT_CHAIN_KEY_FILE", dst: &envArgs.Audit.ChainKey},
+	} {
+		path := os.Getenv(secret.env)
+		if path == "" {
+			continue
+		}
+
+		if *secret.dst != "" {
+			return "", fmt.Errorf("readSecretFiles %s: %w", secret.env, config.ErrSecretConflict)
 		}
 
-		if err := json.Unmarshal(data, configArgs); err != nil {
-			return configArgs
+		if *secret.dst, err = config.ReadSecretFile(path); err != nil {
+			return "", fmt.Errorf("readSecretFiles %s: %w", secret.env, err)
 		}
 	}
 
-	return configArgs
+	return dbPassword, nil
+}
+
+// readConfig reads config file. Empty fileConfig gives empty config.
+func readConfig(fileConfig string) (*config.StartupFlags, error) {
+	if fileConfig == "" {
+		return new(config.StartupFlags), nil
+	}
+
+	configArgs, err := config.ReadFile(configPath(fileConfig))
+	if err != nil {
+		return nil, fmt.Errorf("readConfig: %w", err)
+	}
+
+	return configArgs, nil
+}
+
+// configPath resolves config file path relative to dictionary.BasePath.
+func configPath(fileConfig string) string {
+	if fileConfig == "" || strings.HasPrefix(fileConfig, "/") {
+		return filepath.Clean(fileConfig)
+	}
+
+	return filepath.Clean(filepath.Join(dictionary.BasePath, fileConfig))
 }
@@ -3,9 +3,12 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/Pklerik/urlshortener/internal/app"
+	"github.com/Pklerik/urlshortener/internal/config"
 	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/metrics"
 )
 
 const na = "N/A"
@@ -15,7 +18,15 @@ var buildDate string
 var buildCommit string
 
 func main() {
-	parsedArgs := parseArgs()
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfig(os.Stdout, os.Args[2:]); err != nil {
+			log.Fatalf("config: %v", err)
+		}
+
+		return
+	}
+
+	parsedArgs, load := parseArgs()
 
 	err := logger.Initialize(parsedArgs.GetLogLevel())
 	if err != nil {
@@ -38,5 +49,12 @@ func main() {
 	logger.Sugar.Infof("Build date: <%s>", buildDate)
 	logger.Sugar.Infof("Build commit: <%s>", buildCommit)
 
-	app.StartApp(parsedArgs)
+	metrics.SetBuildInfo(buildVersion, buildCommit)
+
+	path := ""
+	if parsedArgs.FileConfig != "" {
+		path = configPath(parsedArgs.FileConfig)
+	}
+
+	app.StartApp(config.NewLive(parsedArgs, path, load))
 }
@@ -3,23 +3,44 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	//nolint необходимо получать SIGTERM для остановки процесса.
 	"syscall"
 
 	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
 	"github.com/Pklerik/urlshortener/internal/criptography"
+	"github.com/Pklerik/urlshortener/internal/lifecycle"
+	"github.com/Pklerik/urlshortener/internal/listener"
 	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/metrics"
+	"github.com/Pklerik/urlshortener/internal/middleware"
 	"github.com/Pklerik/urlshortener/internal/router"
+	"github.com/Pklerik/urlshortener/internal/tracing"
 	"golang.org/x/sync/errgroup"
 )
 
+const (
+	// adminReadHeaderTimeout limits slow clients of admin listener.
+	adminReadHeaderTimeout = 5 * time.Second
+	// certPollInterval period of TLS certificate files change check.
+	certPollInterval = 5 * time.Second
+	// httpsPort default HTTPS port, omitted in redirect location.
+	httpsPort = 443
+)
+
 // StartApp - starts server app function.
 func StartApp(parsedArgs config.StartupFlagsParser) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -32,37 +53,87 @@ func StartApp(parsedArgs config.StartupFlagsParser) {
 		cancel()
 	}()
 
-	argPort := ":" + strconv.Itoa(parsedArgs.GetServerAddress().Port)
-	logger.Sugar.Infof("Setup server with args: port: %s", argPort)
+	listenAddr := parsedArgs.GetServerAddress().ListenAddress()
+	logger.Sugar.Infof("Setup server with args: address: %s", listenAddr)
+
+	lc := lifecycle.New()
+	live, _ := parsedArgs.(*config.Live)
+	if live != nil {
+		live.OnReload(applyConfig)
+	}
 
-	routerHandler, err := router.ConfigureRouter(ctx, parsedArgs)
+	lc.GoLoop("config reload", func(ctx context.Context) { handleHUP(ctx, live) })
+	setupTracing(parsedArgs, lc)
+
+	routerHandler, adminHandler, err := router.ConfigureRouter(ctx, parsedArgs, lc)
 	if err != nil {
-		logger.Sugar.Errorf("Unable to start server: %w", err)
+		logger.Sugar.Errorf("Unable to start server: %v", err)
+		releaseResources(lc)
+
 		return
 	}
 
 	httpServer := &http.Server{
-		Addr:         argPort,
-		Handler:      routerHandler,
+		Addr: listenAddr,
+		// таймауты сервера задают только значения до чтения запроса, дальше действует перечитываемый timeout.
+		Handler:      middleware.Deadlines(parsedArgs.GetTimeout)(routerHandler),
 		ReadTimeout:  parsedArgs.GetTimeout(),
 		WriteTimeout: parsedArgs.GetTimeout(),
 	}
 
+	// порядок задаёт очередность остановки: admin и metrics останавливаются последними,
+	// чтобы было видно, как завершились фоновые задачи.
+	auxServers := make([]*auxServer, 0)
+	for _, aux := range []*auxServer{
+		{name: "HTTPS redirect server", srv: newRedirectServer(parsedArgs, routerHandler)},
+		{name: "admin server", srv: newAdminServer(parsedArgs.GetAdminAddress(), routerHandler, adminHandler)},
+		{name: "metrics server", srv: newMetricsServer(parsedArgs.GetMetricsAddress())},
+	} {
+		if aux.srv != nil {
+			auxServers = append(auxServers, aux)
+		}
+	}
+
+	httpListener, err := openListeners(httpServer, auxServers, parsedArgs.GetSocketMode())
+	if err != nil {
+		logger.Sugar.Errorf("Unable to start server: %v", err)
+		releaseResources(lc)
+
+		return
+	}
+
 	g, gCtx := errgroup.WithContext(ctx)
+	for _, aux := range auxServers {
+		g.Go(aux.serve)
+	}
+
 	g.Go(func() error {
 		if parsedArgs.GetTLS() {
-			return runTLSListener(httpServer)
+			err := runTLSListener(httpServer, httpListener, parsedArgs.GetTLSConf(), lc)
+			// ServeTLS закрывает listener сам, при ошибке настройки TLS он остаётся открытым.
+			httpListener.Close()
+
+			return err
 		}
 
-		logger.Sugar.Infof("Starting server")
+		logger.Sugar.Infof("Starting server on <%s>", httpListener.Addr())
 
-		return httpServer.ListenAndServe()
+		return httpServer.Serve(httpListener)
 	})
 	g.Go(func() error {
 		<-gCtx.Done()
+		drainReadiness(lc, parsedArgs.GetShutdownDelay())
 		logger.Sugar.Infof("Stopped serving new connections.")
 
-		return httpServer.Shutdown(context.Background())
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), parsedArgs.GetShutdownTimeout())
+		defer cancelShutdown()
+
+		err := errors.Join(httpServer.Shutdown(shutdownCtx), lc.Shutdown(shutdownCtx))
+		for _, aux := range auxServers {
+			err = errors.Join(err, aux.srv.Shutdown(shutdownCtx))
+		}
+
+		return err
 	})
 
 	if err := g.Wait(); err != nil {
@@ -70,22 +141,246 @@ func StartApp(parsedArgs config.StartupFlagsParser) {
 	}
 }
 
-func runTLSListener(httpServer *http.Server) error {
-	// Сохраняем сертификат и приватный ключ в файлы ../../../cert/cert.pem и ../../../cert/private.pem
-	certPath, err := os.Executable()
+// drainReadiness fails readiness probes and waits delay before listeners are closed,
+// so load balancer notices it and stops sending new requests.
+func drainReadiness(lc *lifecycle.Manager, delay time.Duration) {
+	lc.BeginShutdown()
+
+	if delay > 0 {
+		logger.Sugar.Infof("Readiness is off, waiting %s before closing listeners", delay)
+		time.Sleep(delay)
+	}
+}
+
+// auxServer additional server running next to app server.
+type auxServer struct {
+	srv  *http.Server
+	ln   net.Listener
+	name string
+}
+
+// serve serves aux server until it is shut down.
+func (a *auxServer) serve() error {
+	logger.Sugar.Infof("Starting %s on <%s>", a.name, a.ln.Addr())
+
+	if err := a.srv.Serve(a.ln); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", a.name, err)
+	}
+
+	return nil
+}
+
+// openListeners opens listeners of all servers by their Addr before serving starts,
+// so wrong address stops the app at once. Not used systemd sockets are closed.
+func openListeners(httpServer *http.Server, auxServers []*auxServer, mode fs.FileMode) (net.Listener, error) {
+	httpListener, err := listener.Open(httpServer.Addr, mode)
 	if err != nil {
-		logger.Sugar.Errorf("unable o start server %v", err)
-		return fmt.Errorf("unable o start server %w", err)
+		return nil, fmt.Errorf("openListeners: %w", err)
+	}
+
+	for i, aux := range auxServers {
+		aux.ln, err = listener.Open(aux.srv.Addr, mode)
+		if err != nil {
+			httpListener.Close()
+
+			for _, opened := range auxServers[:i] {
+				opened.ln.Close()
+			}
+
+			return nil, fmt.Errorf("openListeners %s: %w", aux.name, err)
+		}
+	}
+
+	if names := listener.CloseUnused(); len(names) > 0 {
+		logger.Sugar.Warnf("Closed systemd sockets without listener: %v", names)
+	}
+
+	return httpListener, nil
+}
+
+// releaseResources stops background tasks when app fails to start.
+func releaseResources(lc *lifecycle.Manager) {
+	if err := lc.Shutdown(context.Background()); err != nil {
+		logger.Sugar.Errorf("Unable to release resources: %v", err)
 	}
+}
 
-	certPath = filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(certPath))), "cert")
+// setupTracing sets global tracer if trace exporter is configured.
+// Invalid exporter only disables tracing, the app keeps working.
+func setupTracing(parsedArgs config.StartupFlagsParser, lc *lifecycle.Manager) {
+	kind := parsedArgs.GetTraceExporter()
+	if kind == tracing.ExporterNone {
+		return
+	}
 
-	keys, err := criptography.GetSertKey(certPath)
+	exporter, err := tracing.NewExporter(context.Background(), kind, parsedArgs.GetTraceEndpoint())
 	if err != nil {
-		logger.Sugar.Errorf("unable to generate cert sequence err: %v", err)
+		logger.Sugar.Errorf("Tracing is disabled: %v", err)
+		return
 	}
 
-	logger.Sugar.Infof("Starting server with TLS")
+	tracing.SetErrorHandler(func(err error) { logger.Sugar.Warnf("tracing export: %v", err) })
 
-	return fmt.Errorf("unable to start server with TLS: %w", httpServer.ListenAndServeTLS(keys.CertPEMFile, keys.PrivateKeyPEMFile))
+	tp := tracing.NewProvider(exporter, parsedArgs.GetTraceSampleRatio())
+	tracing.SetGlobal(tp)
+	lc.OnShutdown("tracing", tp.Shutdown)
+
+	logger.Sugar.Infof("Tracing with %s exporter", kind)
+}
+
+// newAdminServer provide server for operational endpoints, nil if addr is empty.
+// Health probes are served by app router, admin listener keeps answering them until shutdown ends.
+// adminHandler serves admin API and profiler, which are not mounted on app router then.
+func newAdminServer(addr string, appHandler, adminHandler http.Handler) *http.Server {
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	if adminHandler != nil {
+		mux.Handle("/", adminHandler)
+	}
+
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.Handle("GET /healthz", appHandler)
+	mux.Handle("GET /readyz", appHandler)
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: adminReadHeaderTimeout,
+	}
+}
+
+// newMetricsServer provide server for metrics only, nil if addr is empty.
+func newMetricsServer(addr string) *http.Server {
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: adminReadHeaderTimeout,
+	}
+}
+
+// newRedirectServer provide plaintext server redirecting to HTTPS listener,
+// nil if TLS or redirect address is not set. Health probes are served by app router without redirect.
+func newRedirectServer(parsedArgs config.StartupFlagsParser, appHandler http.Handler) *http.Server {
+	if !parsedArgs.GetTLS() || parsedArgs.GetTLSConf().GetHTTPRedirectAddress() == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", appHandler)
+	mux.Handle("GET /readyz", appHandler)
+	mux.Handle("/", httpsRedirect(parsedArgs.GetAddressShortURL, parsedArgs.GetServerAddress().Port))
+
+	return &http.Server{
+		Addr:              parsedArgs.GetTLSConf().GetHTTPRedirectAddress(),
+		Handler:           mux,
+		ReadHeaderTimeout: adminReadHeaderTimeout,
+	}
+}
+
+// httpsRedirect permanently redirects request to the same path on host of public base url.
+// Request Host is client controlled, so it is used only without base url: then request goes
+// to the same host on HTTPS listener port, port is omitted when it is unknown, as for Unix socket behind proxy.
+// 308 keeps method and body, so API clients are redirected too.
+func httpsRedirect(baseURL func() string, tlsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := url.URL{Scheme: "https", Path: r.URL.Path, RawPath: r.URL.RawPath, RawQuery: r.URL.RawQuery}
+
+		if base, err := url.Parse(baseURL()); err == nil && base.Host != "" {
+			target.Host = base.Host
+			http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+
+			return
+		}
+
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if tlsPort != 0 && tlsPort != httpsPort {
+			host = net.JoinHostPort(host, strconv.Itoa(tlsPort))
+		} else if strings.Contains(host, ":") {
+			// IPv6 адрес без порта должен быть в квадратных скобках.
+			host = "[" + host + "]"
+		}
+
+		target.Host = host
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
+}
+
+// runTLSListener serves httpServer with configured certificate, which is reloaded when its files change.
+// Without configured certificate self-signed one is generated, it is meant for development only.
+func runTLSListener(httpServer *http.Server, ln net.Listener, conf *tlsconf.Conf, lc *lifecycle.Manager) error {
+	var certFile, keyFile string
+
+	if conf.SelfSigned() {
+		// Сохраняем сертификат и приватный ключ в файлы ../../../cert/cert.pem и ../../../cert/private.pem
+		certPath, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("unable to start server with TLS: %w", err)
+		}
+
+		certPath = filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(certPath))), "cert")
+
+		keys, err := criptography.GetSertKey(certPath, conf.GetSelfSignedHosts())
+		if err != nil {
+			return fmt.Errorf("unable to generate cert sequence: %w", err)
+		}
+
+		logger.Sugar.Warnf("Using self-signed certificate for %v, set TLS cert and key files outside development", conf.GetSelfSignedHosts())
+
+		certFile, keyFile = keys.CertPEMFile, keys.PrivateKeyPEMFile
+	} else {
+		certFile, keyFile = conf.CertFile, conf.KeyFile
+	}
+
+	reloader, err := criptography.NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to start server with TLS: %w", err)
+	}
+
+	httpServer.TLSConfig, err = criptography.NewServerTLSConfig(conf, reloader.GetCertificate)
+	if err != nil {
+		return fmt.Errorf("unable to start server with TLS: %w", err)
+	}
+
+	lc.GoLoop("tls certificate reload", func(ctx context.Context) { watchCert(ctx, reloader) })
+
+	logger.Sugar.Infof("Starting server with TLS on <%s>", ln.Addr())
+
+	return fmt.Errorf("unable to start server with TLS: %w", httpServer.ServeTLS(ln, "", ""))
+}
+
+// watchCert reloads certificate when its files change, until ctx is done.
+func watchCert(ctx context.Context, reloader *criptography.CertReloader) {
+	ticker := time.NewTicker(certPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := reloader.Reload()
+			if err != nil {
+				logger.Sugar.Errorf("TLS certificate reload failed, current certificate is kept: %v", err)
+				continue
+			}
+
+			if reloaded {
+				logger.Sugar.Infof("TLS certificate reloaded")
+			}
+		}
+	}
 }
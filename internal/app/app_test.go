@@ -2,15 +2,23 @@ package app
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/Pklerik/urlshortener/internal/config"
 	"github.com/Pklerik/urlshortener/internal/config/mocks"
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
+	"github.com/Pklerik/urlshortener/internal/lifecycle"
+	"github.com/Pklerik/urlshortener/internal/listener"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -28,6 +36,20 @@ func TestStartApp_SuccessfulStartup(t *testing.T) {
 	mockParser.EXPECT().GetTimeout().Return(5 * time.Second).AnyTimes()
 	mockParser.EXPECT().GetSecretKey().Return("secret").AnyTimes()
 	mockParser.EXPECT().GetTLS().Return(false).AnyTimes()
+	mockParser.EXPECT().GetRestoreWindow().Return(time.Hour).AnyTimes()
+	mockParser.EXPECT().GetPurgeInterval().Return(time.Duration(0)).AnyTimes()
+	mockParser.EXPECT().GetAdminToken().Return("").AnyTimes()
+	mockParser.EXPECT().GetJobWorkers().Return(1).AnyTimes()
+	mockParser.EXPECT().GetJobMaxAttempts().Return(5).AnyTimes()
+	mockParser.EXPECT().GetJobsFile().Return("").AnyTimes()
+	mockParser.EXPECT().GetShutdownTimeout().Return(time.Second).AnyTimes()
+	mockParser.EXPECT().GetShutdownDelay().Return(time.Duration(0)).AnyTimes()
+	mockParser.EXPECT().GetClickSampleRate().Return(0.0).AnyTimes()
+	mockParser.EXPECT().GetRedirectLogSampleRate().Return(1.0).AnyTimes()
+	mockParser.EXPECT().GetAdminAddress().Return("").AnyTimes()
+	mockParser.EXPECT().GetMetricsAddress().Return("").AnyTimes()
+	mockParser.EXPECT().GetSocketMode().Return(listener.DefaultSocketMode).AnyTimes()
+	mockParser.EXPECT().GetTraceExporter().Return("").AnyTimes()
 
 	go func() {
 		time.Sleep(100 * time.Millisecond)
@@ -47,6 +69,128 @@ func TestStartApp_InvalidPort(t *testing.T) {
 	mockParser.EXPECT().GetTimeout().Return(5 * time.Second).AnyTimes()
 	mockParser.EXPECT().GetSecretKey().Return("secret").AnyTimes()
 	mockParser.EXPECT().GetTLS().Return(false).AnyTimes()
+	mockParser.EXPECT().GetRestoreWindow().Return(time.Hour).AnyTimes()
+	mockParser.EXPECT().GetPurgeInterval().Return(time.Duration(0)).AnyTimes()
+	mockParser.EXPECT().GetAdminToken().Return("").AnyTimes()
+	mockParser.EXPECT().GetJobWorkers().Return(1).AnyTimes()
+	mockParser.EXPECT().GetJobMaxAttempts().Return(5).AnyTimes()
+	mockParser.EXPECT().GetJobsFile().Return("").AnyTimes()
+	mockParser.EXPECT().GetShutdownTimeout().Return(time.Second).AnyTimes()
+	mockParser.EXPECT().GetShutdownDelay().Return(time.Duration(0)).AnyTimes()
+	mockParser.EXPECT().GetClickSampleRate().Return(0.0).AnyTimes()
+	mockParser.EXPECT().GetRedirectLogSampleRate().Return(1.0).AnyTimes()
+	mockParser.EXPECT().GetAdminAddress().Return("").AnyTimes()
+	mockParser.EXPECT().GetMetricsAddress().Return("").AnyTimes()
+	mockParser.EXPECT().GetSocketMode().Return(listener.DefaultSocketMode).AnyTimes()
+	mockParser.EXPECT().GetTraceExporter().Return("").AnyTimes()
 
 	StartApp(mockParser)
 }
+
+func Test_httpsRedirect(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		method  string
+		want    string
+		baseURL string
+		tlsPort int
+	}{
+		{name: "default_port", method: http.MethodGet, target: "http://short.example/abc?x=1", tlsPort: 443, want: "https://short.example/abc?x=1"},
+		{name: "custom_port", method: http.MethodGet, target: "http://short.example:8080/abc", tlsPort: 8443, want: "https://short.example:8443/abc"},
+		{name: "post", method: http.MethodPost, target: "http://short.example/api/shorten", tlsPort: 443, want: "https://short.example/api/shorten"},
+		{name: "unknown_port", method: http.MethodGet, target: "http://short.example/abc", tlsPort: 0, want: "https://short.example/abc"},
+		{name: "ipv6", method: http.MethodGet, target: "http://[::1]:80/abc", tlsPort: 443, want: "https://[::1]/abc"},
+		{name: "base_url", method: http.MethodGet, target: "http://evil.example/abc?x=1", baseURL: "https://short.example", tlsPort: 8443, want: "https://short.example/abc?x=1"},
+		{name: "base_url_port", method: http.MethodGet, target: "http://10.0.0.5:8080/abc", baseURL: "https://short.example:8443", tlsPort: 443, want: "https://short.example:8443/abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			baseURL := func() string { return tt.baseURL }
+			httpsRedirect(baseURL, tt.tlsPort).ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Location"))
+		})
+	}
+}
+
+func Test_newRedirectServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockParser := mocks.NewMockStartupFlagsParser(ctrl)
+	mockParser.EXPECT().GetTLS().Return(true).AnyTimes()
+	mockParser.EXPECT().GetTLSConf().Return(&tlsconf.Conf{HTTPRedirectAddress: ":8080"}).AnyTimes()
+	mockParser.EXPECT().GetServerAddress().Return(config.Address{Port: 8443}).AnyTimes()
+	mockParser.EXPECT().GetAddressShortURL().Return("https://short.example:8443").AnyTimes()
+
+	appHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	srv := newRedirectServer(mockParser, appHandler)
+	require.NotNil(t, srv)
+	assert.Equal(t, ":8080", srv.Addr)
+
+	for path, want := range map[string]int{"/healthz": http.StatusOK, "/readyz": http.StatusOK, "/abc": http.StatusPermanentRedirect} {
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://short.example:8080"+path, nil))
+		assert.Equal(t, want, w.Code, path)
+
+		if want == http.StatusPermanentRedirect {
+			assert.Equal(t, "https://short.example:8443"+path, w.Header().Get("Location"))
+		}
+	}
+}
+
+func Test_openListeners(t *testing.T) {
+	dir, err := os.MkdirTemp("", "app")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	httpServer := &http.Server{Addr: listener.SchemeUnix + filepath.Join(dir, "public.sock")}
+	auxServers := []*auxServer{
+		{name: "admin server", srv: newAdminServer(listener.SchemeUnix+filepath.Join(dir, "admin.sock"), http.NotFoundHandler(), nil)},
+		{name: "metrics server", srv: newMetricsServer("127.0.0.1:0")},
+	}
+
+	ln, err := openListeners(httpServer, auxServers, 0o600)
+	require.NoError(t, err)
+
+	assert.Equal(t, "unix", ln.Addr().Network())
+	assert.Equal(t, "unix", auxServers[0].ln.Addr().Network())
+	assert.Equal(t, "tcp", auxServers[1].ln.Addr().Network())
+
+	ln.Close()
+	auxServers[0].ln.Close()
+
+	// занятый адрес закрывает уже открытые listeners.
+	busy := auxServers[1].ln
+	defer busy.Close()
+
+	_, err = openListeners(httpServer, []*auxServer{
+		{name: "admin server", srv: newAdminServer(listener.SchemeUnix+filepath.Join(dir, "admin.sock"), http.NotFoundHandler(), nil)},
+		{name: "metrics server", srv: newMetricsServer(busy.Addr().String())},
+	}, 0o600)
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "public.sock"))
+	assert.NoFileExists(t, filepath.Join(dir, "admin.sock"))
+}
+
+func Test_drainReadiness(t *testing.T) {
+	lc := lifecycle.New()
+	done := make(chan struct{})
+
+	go func() {
+		drainReadiness(lc, 100*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, lc.ShuttingDown, time.Second, time.Millisecond, "readiness fails at once")
+
+	select {
+	case <-done:
+		t.Fatal("listeners are closed before delay")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-done
+}
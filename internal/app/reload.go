@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	//nolint необходимо получать SIGHUP для перечитывания конфигурации.
+	"syscall"
+
+	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/logger"
+)
+
+const (
+	// configPollInterval period of config file change checks.
+	configPollInterval = 2 * time.Second
+	// auditReloadTimeout limits flushing of replaced audit sinks.
+	auditReloadTimeout = 5 * time.Second
+)
+
+// fileStamp detects config file changes.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statConfig(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// handleHUP reloads config and reopens audit files on SIGHUP, so external logrotate can move them.
+// Config is also reloaded when its file changes. live may be nil, then only audit files are reopened.
+func handleHUP(ctx context.Context, live *config.Live) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+
+	defer signal.Stop(c)
+
+	var (
+		poll  <-chan time.Time
+		stamp fileStamp
+	)
+
+	if live != nil && live.Path() != "" {
+		ticker := time.NewTicker(configPollInterval)
+		defer ticker.Stop()
+
+		poll = ticker.C
+		stamp = statConfig(live.Path())
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c:
+			reloadConfig(live)
+
+			if err := logger.ReopenAudit(); err != nil {
+				logger.Sugar.Errorf("Unable to reopen audit file: %v", err)
+				continue
+			}
+
+			logger.Sugar.Infof("Audit file reopened")
+		case <-poll:
+			if s := statConfig(live.Path()); s != stamp {
+				stamp = s
+
+				reloadConfig(live)
+			}
+		}
+	}
+}
+
+// reloadConfig applies config file changes, invalid config is logged and ignored.
+func reloadConfig(live *config.Live) {
+	if live == nil {
+		return
+	}
+
+	res, err := live.Reload()
+	if err != nil {
+		logger.Sugar.Errorf("Config reload rejected, current config is kept: %v", err)
+		return
+	}
+
+	if len(res.Changed) > 0 {
+		logger.Sugar.Infof("Config reloaded: %s", strings.Join(res.Changed, ", "))
+	}
+
+	if len(res.RestartRequired) > 0 {
+		logger.Sugar.Warnf("Config changes of %s are applied only after restart", strings.Join(res.RestartRequired, ", "))
+	}
+}
+
+// applyConfig applies reloaded settings which are not read on every use.
+func applyConfig(prev, cur *config.StartupFlags) {
+	if prev.GetLogLevel() != cur.GetLogLevel() {
+		if err := logger.SetLevel(cur.GetLogLevel()); err != nil {
+			logger.Sugar.Errorf("Unable to change log level: %v", err)
+		}
+	}
+
+	if prev.GetAudit() != cur.GetAudit() {
+		ctx, cancel := context.WithTimeout(context.Background(), auditReloadTimeout)
+		defer cancel()
+
+		if err := logger.ReloadAudit(ctx, cur.GetAudit(), cur.GetDataDir()); err != nil {
+			logger.Sugar.Errorf("Unable to close replaced audit sinks: %v", err)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// Package auditchain provide tamper-evident hash chain for audit log records.
+//
+// Every record gets "seq" and "prev_hash" fields, where prev_hash is sha256 of previous record line.
+// Periodic checkpoint records contain HMAC-SHA256 signature of chain head made with configured key,
+// so records can't be rewritten together with the whole chain tail without the key.
+package auditchain
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	// ErrMalformedRecord record is not a JSON object or has no chain fields.
+	ErrMalformedRecord = errors.New("malformed audit record")
+	// ErrBrokenChain record sequence or previous hash doesn't match previous record.
+	ErrBrokenChain = errors.New("audit chain is broken")
+	// ErrBadCheckpoint checkpoint doesn't match chain head or its signature is invalid.
+	ErrBadCheckpoint = errors.New("invalid audit checkpoint")
+	// ErrMissingCheckpoint records are not covered by signed checkpoint.
+	ErrMissingCheckpoint = errors.New("signed audit checkpoint is missing")
+)
+
+// maxLineSize max size of single audit record accepted by verifier.
+const maxLineSize = 1 << 20
+
+// State position of the chain head.
+type State struct {
+	// Hash hex sha256 of the last record line, empty before first record.
+	Hash string
+	Seq  uint64
+}
+
+// record chain fields of audit record.
+type record struct {
+	PrevHash      string `json:"prev_hash"`
+	ChainHash     string `json:"chain_hash"`
+	Sig           string `json:"sig"`
+	Seq           uint64 `json:"seq"`
+	CheckpointSeq uint64 `json:"checkpoint_seq"`
+	Checkpoint    bool   `json:"checkpoint"`
+}
+
+// Writer adds chain fields to JSON records and writes them as lines to underlying writer.
+// Every Write call must contain exactly one JSON object, as zap cores do.
+type Writer struct {
+	w               io.Writer
+	key             []byte
+	state           State
+	every           int
+	sinceCheckpoint int
+	mu              sync.Mutex
+}
+
+// NewWriter creates Writer continuing chain from state.
+// Checkpoints are written after every `every` records if key is not empty.
+func NewWriter(w io.Writer, state State, key []byte, every int) *Writer {
+	return &Writer{w: w, state: state, key: key, every: every}
+}
+
+// Write implement io.Writer interface.
+func (cw *Writer) Write(p []byte) (int, error) {
+	body := bytes.TrimSpace(p)
+	if len(body) < 2 || body[0] != '{' || body[len(body)-1] != '}' {
+		return 0, fmt.Errorf("(cw *Writer) Write: %w", ErrMalformedRecord)
+	}
+
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	if err := cw.append(body); err != nil {
+		return 0, err
+	}
+
+	cw.sinceCheckpoint++
+	if len(cw.key) > 0 && cw.every > 0 && cw.sinceCheckpoint >= cw.every {
+		if err := cw.checkpoint(); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Sync implement zapcore.WriteSyncer interface.
+func (cw *Writer) Sync() error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	if cw.sinceCheckpoint > 0 && len(cw.key) > 0 && cw.every > 0 {
+		if err := cw.checkpoint(); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := cw.w.(interface{ Sync() error }); ok {
+		if err := s.Sync(); err != nil {
+			return fmt.Errorf("(cw *Writer) Sync: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// State provide current chain head.
+func (cw *Writer) State() State {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	return cw.state
+}
+
+func (cw *Writer) append(body []byte) error {
+	seq := cw.state.Seq + 1
+
+	line := fmt.Appendf(make([]byte, 0, len(body)+128), `{"seq":%d,"prev_hash":"%s"`, seq, cw.state.Hash)
+	if len(bytes.TrimSpace(body[1:len(body)-1])) > 0 {
+		line = append(line, ',')
+	}
+
+	line = append(line, body[1:]...)
+
+	if _, err := cw.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("(cw *Writer) append: %w", err)
+	}
+
+	cw.state = State{Seq: seq, Hash: hashLine(line)}
+
+	return nil
+}
+
+func (cw *Writer) checkpoint() error {
+	cw.sinceCheckpoint = 0
+
+	body := fmt.Appendf(nil, `{"checkpoint":true,"checkpoint_seq":%d,"chain_hash":"%s","sig":"%s"}`,
+		cw.state.Seq, cw.state.Hash, Sign(cw.key, cw.state))
+
+	return cw.append(body)
+}
+
+// Sign provide hex HMAC-SHA256 signature of chain head.
+func Sign(key []byte, state State) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%d:%s", state.Seq, state.Hash)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func hashLine(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// LastState provide chain head of existing audit file, zero State if file is absent or empty.
+// Gzip compressed files must have .gz extension.
+func LastState(path string) (State, error) {
+	f, err := openFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, nil
+	}
+
+	if err != nil {
+		return State{}, fmt.Errorf("LastState: %w", err)
+	}
+	defer f.Close()
+
+	var last []byte
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			last = append(last[:0], line...)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return State{}, fmt.Errorf("LastState: %w", err)
+	}
+
+	if last == nil {
+		return State{}, nil
+	}
+
+	var rec record
+	if err := json.Unmarshal(last, &rec); err != nil || rec.Seq == 0 {
+		return State{}, fmt.Errorf("LastState: %w", ErrMalformedRecord)
+	}
+
+	return State{Seq: rec.Seq, Hash: hashLine(last)}, nil
+}
+
+// BreakError describes first broken link of the chain.
+type BreakError struct {
+	Err  error
+	File string
+	Line int
+	Seq  uint64
+}
+
+// Error implement error interface.
+func (be *BreakError) Error() string {
+	return fmt.Sprintf("%s:%d (seq %d): %v", be.File, be.Line, be.Seq, be.Err)
+}
+
+// Unwrap provide reason of the break.
+func (be *BreakError) Unwrap() error {
+	return be.Err
+}
+
+// Verifier walks audit files in chain order. Rotated files must be verified oldest first
+// with the same Verifier so chain is checked across file boundaries.
+type Verifier struct {
+	key         []byte
+	state       State
+	lastFile    string
+	Records     int
+	Checkpoints int
+	// AnchorSeq seq of the first verified record if chain start (seq 1) wasn't seen, e.g. old files were removed by retention.
+	AnchorSeq       uint64
+	every           int
+	sinceCheckpoint int
+	lastLine        int
+	started         bool
+}
+
+// NewVerifier creates Verifier. Checkpoint signatures are checked only if key is not empty,
+// then signed checkpoint is also required after at most `every` records, every <= 0 disables this check.
+func NewVerifier(key []byte, every int) *Verifier {
+	return &Verifier{key: key, every: every}
+}
+
+// State provide chain head after verified records.
+func (v *Verifier) State() State {
+	return v.state
+}
+
+// VerifyFile verifies file, gzip compressed files must have .gz extension.
+func (v *Verifier) VerifyFile(path string) error {
+	f, err := openFile(path)
+	if err != nil {
+		return fmt.Errorf("(v *Verifier) VerifyFile: %w", err)
+	}
+	defer f.Close()
+
+	return v.Verify(f, path)
+}
+
+// gzipFile closes both decompressor and file.
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+// Close implement io.Closer interface.
+func (gf gzipFile) Close() error {
+	return errors.Join(gf.Reader.Close(), gf.file.Close())
+}
+
+// openFile opens audit file, decompressing .gz files.
+func openFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("openFile: %w", err)
+	}
+
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("openFile: %w", err), f.Close())
+	}
+
+	return gzipFile{Reader: gz, file: f}, nil
+}
+
+// Verify checks records from r and returns *BreakError for the first broken link.
+func (v *Verifier) Verify(r io.Reader, name string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		seq, err := v.check(line)
+		if err != nil {
+			return &BreakError{File: name, Line: lineNum, Seq: seq, Err: err}
+		}
+
+		v.lastFile, v.lastLine = name, lineNum
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("(v *Verifier) Verify: %w", err)
+	}
+
+	return nil
+}
+
+// Finish checks that chain ends with signed checkpoint, must be called after the last file.
+// Without it records written after the last checkpoint, or whole chain anchored after
+// removed files, could be rewritten without the key.
+func (v *Verifier) Finish() error {
+	if len(v.key) == 0 || v.sinceCheckpoint == 0 {
+		return nil
+	}
+
+	return &BreakError{
+		File: v.lastFile,
+		Line: v.lastLine,
+		Seq:  v.state.Seq,
+		Err:  fmt.Errorf("%w: %d records after the last checkpoint", ErrMissingCheckpoint, v.sinceCheckpoint),
+	}
+}
+
+func (v *Verifier) check(line []byte) (uint64, error) {
+	var rec record
+	if err := json.Unmarshal(line, &rec); err != nil || rec.Seq == 0 {
+		return 0, ErrMalformedRecord
+	}
+
+	switch {
+	case v.started && rec.Seq != v.state.Seq+1:
+		return rec.Seq, fmt.Errorf("%w: expected seq %d", ErrBrokenChain, v.state.Seq+1)
+	case v.started && rec.PrevHash != v.state.Hash:
+		return rec.Seq, fmt.Errorf("%w: previous record hash mismatch", ErrBrokenChain)
+	case !v.started && rec.Seq == 1 && rec.PrevHash != "":
+		return rec.Seq, fmt.Errorf("%w: first record has previous hash", ErrBrokenChain)
+	case !v.started && rec.Seq != 1:
+		v.AnchorSeq = rec.Seq
+	}
+
+	if rec.Checkpoint {
+		if rec.CheckpointSeq != rec.Seq-1 || rec.ChainHash != rec.PrevHash {
+			return rec.Seq, fmt.Errorf("%w: checkpoint doesn't match chain head", ErrBadCheckpoint)
+		}
+
+		if len(v.key) > 0 && !hmac.Equal([]byte(rec.Sig), []byte(Sign(v.key, State{Seq: rec.CheckpointSeq, Hash: rec.ChainHash}))) {
+			return rec.Seq, fmt.Errorf("%w: signature mismatch", ErrBadCheckpoint)
+		}
+
+		v.sinceCheckpoint = 0
+		v.Checkpoints++
+	} else {
+		if len(v.key) > 0 && v.every > 0 && v.sinceCheckpoint >= v.every {
+			return rec.Seq, fmt.Errorf("%w: more than %d records without checkpoint", ErrMissingCheckpoint, v.every)
+		}
+
+		v.sinceCheckpoint++
+		v.Records++
+	}
+
+	v.started = true
+	v.state = State{Seq: rec.Seq, Hash: hashLine(line)}
+
+	return rec.Seq, nil
+}
@@ -0,0 +1,179 @@
+package auditchain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRecords(t *testing.T, cw *Writer, n int) {
+	t.Helper()
+
+	for i := range n {
+		_, err := cw.Write(fmt.Appendf(nil, `{"action":"follow","n":%d}`+"\n", i))
+		require.NoError(t, err)
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	key := []byte("chain-key")
+
+	var buf bytes.Buffer
+
+	writeRecords(t, NewWriter(&buf, State{}, key, 3), 7)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 9, "7 records and 2 checkpoints")
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		key      []byte
+		tamper   func(lines [][]byte) [][]byte
+		wantLine int
+	}{
+		{name: "valid", key: key},
+		{name: "valid without key", key: nil},
+		{
+			name: "edited record", key: key, wantErr: ErrBrokenChain, wantLine: 3,
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte("follow"), []byte("delete"), 1)
+				return lines
+			},
+		},
+		{
+			name: "removed record", key: key, wantErr: ErrBrokenChain, wantLine: 5,
+			tamper: func(lines [][]byte) [][]byte {
+				return append(lines[:4:4], lines[5:]...)
+			},
+		},
+		{name: "wrong key", key: []byte("other"), wantErr: ErrBadCheckpoint, wantLine: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([][]byte, 0, len(lines))
+			for _, line := range lines {
+				data = append(data, bytes.Clone(line))
+			}
+
+			if tt.tamper != nil {
+				data = tt.tamper(data)
+			}
+
+			v := NewVerifier(tt.key, 3)
+			err := v.Verify(bytes.NewReader(bytes.Join(data, []byte("\n"))), "audit.log")
+
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, 7, v.Records)
+				assert.Equal(t, 2, v.Checkpoints)
+
+				return
+			}
+
+			var be *BreakError
+			require.ErrorAs(t, err, &be)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantLine, be.Line)
+		})
+	}
+}
+
+func TestVerifier_Finish(t *testing.T) {
+	key := []byte("chain-key")
+
+	var buf bytes.Buffer
+
+	cw := NewWriter(&buf, State{}, key, 3)
+	writeRecords(t, cw, 7)
+
+	// seq 1-3 records, 4 checkpoint, 5-7 records, 8 checkpoint, 9 record.
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+
+	var synced bytes.Buffer
+
+	cw = NewWriter(&synced, State{}, key, 3)
+	writeRecords(t, cw, 7)
+	require.NoError(t, cw.Sync())
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		key      []byte
+		data     [][]byte
+		every    int
+		wantLine int
+	}{
+		{name: "synced", key: key, every: 3, data: bytes.Split(bytes.TrimSpace(synced.Bytes()), []byte("\n"))},
+		{name: "unsigned tail", key: key, every: 3, data: lines, wantErr: ErrMissingCheckpoint, wantLine: 9},
+		{name: "unsigned tail without key", every: 3, data: lines},
+		{name: "anchored without checkpoint", key: key, every: 3, data: lines[4:7], wantErr: ErrMissingCheckpoint, wantLine: 3},
+		{name: "anchored by checkpoint", key: key, every: 3, data: lines[4:8]},
+		{name: "checkpoints too rare", key: key, every: 2, data: lines, wantErr: ErrMissingCheckpoint, wantLine: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(tt.key, tt.every)
+
+			err := v.Verify(bytes.NewReader(bytes.Join(tt.data, []byte("\n"))), "audit.log")
+			if err == nil {
+				err = v.Finish()
+			}
+
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var be *BreakError
+			require.ErrorAs(t, err, &be)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantLine, be.Line)
+		})
+	}
+}
+
+func TestWriter_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	rotated := filepath.Join(dir, "audit-1.log.gz")
+	current := filepath.Join(dir, "audit.log")
+
+	var plain bytes.Buffer
+
+	cw := NewWriter(&plain, State{}, nil, 0)
+	writeRecords(t, cw, 3)
+
+	var compressed bytes.Buffer
+
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write(plain.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(rotated, compressed.Bytes(), 0600))
+
+	f, err := os.Create(current)
+	require.NoError(t, err)
+
+	cw = NewWriter(f, cw.State(), nil, 0)
+	writeRecords(t, cw, 2)
+	require.NoError(t, f.Close())
+
+	state, err := LastState(current)
+	require.NoError(t, err)
+	assert.Equal(t, cw.State(), state, "chain head restored from file")
+
+	v := NewVerifier(nil, 0)
+	require.NoError(t, v.VerifyFile(rotated))
+	require.NoError(t, v.VerifyFile(current))
+	assert.Equal(t, uint64(5), v.State().Seq)
+
+	v = NewVerifier(nil, 0)
+	require.NoError(t, v.VerifyFile(current), "rotated files may be removed by retention")
+	assert.Equal(t, uint64(4), v.AnchorSeq)
+}
@@ -0,0 +1,70 @@
+// Package auditstore provide asynchronous saving of audit events to searchable storage.
+package auditstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/batcher"
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+const (
+	queueSize     = 4096
+	batchSize     = 100
+	flushInterval = time.Second
+	insertTimeout = 5 * time.Second
+)
+
+// Recorder saves audit events to repository in batches, so storage latency doesn't slow down requests.
+type Recorder struct {
+	repo    repository.AuditRepository
+	batcher *batcher.Batcher[model.AuditEvent]
+}
+
+// NewRecorder creates Recorder and starts its saving loop.
+func NewRecorder(repo repository.AuditRepository) *Recorder {
+	rec := &Recorder{repo: repo}
+	rec.batcher = batcher.New(queueSize, batchSize, flushInterval, rec.save)
+
+	return rec
+}
+
+// Record queues event for saving. Never blocks: event is dropped if queue is full.
+func (rec *Recorder) Record(ev model.AuditEvent) {
+	if !rec.batcher.Add(ev) {
+		logger.Sugar.Warnf("Audit store queue is full, event %s of user %s dropped", ev.Action, ev.UserID)
+	}
+}
+
+// QueueLen returns count of events waiting for saving.
+func (rec *Recorder) QueueLen() int {
+	return rec.batcher.Len()
+}
+
+// Flush saves all queued events.
+func (rec *Recorder) Flush() {
+	rec.batcher.Flush()
+}
+
+// Close saves queued events and stops saving loop.
+func (rec *Recorder) Close(ctx context.Context) error {
+	if err := rec.batcher.Close(ctx); err != nil {
+		return fmt.Errorf("(rec *Recorder) Close: %w", err)
+	}
+
+	return nil
+}
+
+// save inserts batch. Failed batch is dropped, events are still written to audit sinks.
+func (rec *Recorder) save(batch []model.AuditEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), insertTimeout)
+	defer cancel()
+
+	if err := rec.repo.InsertAuditEvents(ctx, batch); err != nil {
+		logger.Sugar.Errorf("Error saving %d audit events: %v", len(batch), err)
+	}
+}
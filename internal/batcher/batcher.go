@@ -0,0 +1,172 @@
+// Package batcher provide bounded asynchronous queue which hands items to consumer in batches.
+//
+// Add never blocks, so slow consumer (network endpoint, database) doesn't slow down callers:
+// item which doesn't fit into the queue is reported back and caller decides to drop or spill it.
+package batcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultInterval flush interval used if not positive one is given.
+const defaultInterval = time.Second
+
+// Option provide optional Batcher settings.
+type Option func(c *config)
+
+type config struct {
+	onTick func()
+}
+
+// WithTick sets function called in consumer goroutine after every periodic flush.
+func WithTick(fn func()) Option {
+	return func(c *config) {
+		c.onTick = fn
+	}
+}
+
+// Batcher collects items and passes them to flush function when batch is full,
+// on every flush interval, on Flush and on Close. Flush function is called from single goroutine
+// and must not keep batch slice after return: it is reused for the next batch.
+type Batcher[T any] struct {
+	flush     func(batch []T)
+	onTick    func()
+	queue     chan T
+	flushReq  chan chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	batchSize int
+	interval  time.Duration
+	closeOnce sync.Once
+}
+
+// New creates Batcher with queue of queueSize items and starts its loop.
+func New[T any](queueSize, batchSize int, interval time.Duration, flush func(batch []T), opts ...Option) *Batcher[T] {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	b := &Batcher[T]{
+		flush:     flush,
+		onTick:    c.onTick,
+		queue:     make(chan T, queueSize),
+		flushReq:  make(chan chan struct{}),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		batchSize: max(batchSize, 1),
+		interval:  interval,
+	}
+
+	go b.run()
+
+	return b
+}
+
+// Add queues item and returns false if queue is full.
+func (b *Batcher[T]) Add(item T) bool {
+	select {
+	case b.queue <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+// Len returns count of queued items.
+func (b *Batcher[T]) Len() int {
+	return len(b.queue)
+}
+
+// Flush passes all queued items to flush function and waits for it.
+func (b *Batcher[T]) Flush() {
+	done := make(chan struct{})
+
+	select {
+	case b.flushReq <- done:
+		<-done
+	case <-b.done:
+	}
+}
+
+// Close flushes queued items and stops loop. If ctx expires first, ctx error is returned
+// and loop keeps flushing in background, Done reports its end.
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() { close(b.stop) })
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("(b *Batcher) Close: %w", ctx.Err())
+	}
+}
+
+// Done is closed when loop is stopped.
+func (b *Batcher[T]) Done() <-chan struct{} {
+	return b.done
+}
+
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, b.batchSize)
+
+	for {
+		select {
+		case item := <-b.queue:
+			batch = b.add(batch, item)
+		case <-ticker.C:
+			batch = b.send(batch)
+
+			if b.onTick != nil {
+				b.onTick()
+			}
+		case done := <-b.flushReq:
+			batch = b.send(b.drain(batch))
+			close(done)
+		case <-b.stop:
+			b.send(b.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves all queued items to batch, sending full batches.
+func (b *Batcher[T]) drain(batch []T) []T {
+	for {
+		select {
+		case item := <-b.queue:
+			batch = b.add(batch, item)
+		default:
+			return batch
+		}
+	}
+}
+
+func (b *Batcher[T]) add(batch []T, item T) []T {
+	if batch = append(batch, item); len(batch) >= b.batchSize {
+		return b.send(batch)
+	}
+
+	return batch
+}
+
+// send passes not empty batch to flush function and returns emptied batch.
+func (b *Batcher[T]) send(batch []T) []T {
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+
+	return batch[:0]
+}
@@ -0,0 +1,96 @@
+package batcher
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collector records batches passed to flush function.
+type collector struct {
+	batches [][]int
+	mu      sync.Mutex
+}
+
+func (c *collector) flush(batch []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.batches = append(c.batches, slices.Clone(batch))
+}
+
+func (c *collector) items() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Concat(c.batches...)
+}
+
+func TestBatcher(t *testing.T) {
+	c := &collector{}
+	b := New(10, 2, time.Hour, c.flush)
+
+	for i := range 3 {
+		require.True(t, b.Add(i))
+	}
+
+	assert.Eventually(t, func() bool { return len(c.items()) == 2 }, time.Second, time.Millisecond,
+		"full batch is flushed without waiting for interval")
+
+	b.Flush()
+	assert.Equal(t, []int{0, 1, 2}, c.items(), "flush sends partial batch")
+
+	require.True(t, b.Add(3))
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, []int{0, 1, 2, 3}, c.items(), "close sends queued items")
+
+	// после остановки Flush не блокируется.
+	b.Flush()
+}
+
+func TestBatcher_Tick(t *testing.T) {
+	c := &collector{}
+	ticks := make(chan struct{}, 1)
+	b := New(10, 100, 5*time.Millisecond, c.flush, WithTick(func() {
+		select {
+		case ticks <- struct{}{}:
+		default:
+		}
+	}))
+
+	defer b.Close(context.Background())
+
+	require.True(t, b.Add(1))
+
+	select {
+	case <-ticks:
+	case <-time.After(time.Second):
+		t.Fatal("tick function wasn't called")
+	}
+
+	assert.Equal(t, []int{1}, c.items(), "partial batch is flushed on interval")
+}
+
+func TestBatcher_Full(t *testing.T) {
+	release := make(chan struct{})
+	b := New(1, 1, time.Hour, func([]int) { <-release })
+
+	// первый элемент занимает обработчик, второй очередь.
+	require.True(t, b.Add(1))
+	assert.Eventually(t, func() bool { return b.Len() == 0 }, time.Second, time.Millisecond)
+	require.True(t, b.Add(2))
+	assert.False(t, b.Add(3), "add never blocks")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+
+	close(release)
+	<-b.Done()
+}
@@ -3,19 +3,60 @@ package audit
 
 import (
 	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
+// Defaults for remote audit delivery.
+const (
+	DefaultQueueSize     = 4096
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+	DefaultMaxRetries    = 3
+	// DefaultMaxSizeMB size of audit file in megabytes after which it is rotated.
+	DefaultMaxSizeMB = 100
+	// DefaultCheckpointEvery records between signed checkpoints of audit hash chain.
+	DefaultCheckpointEvery = 100
+	// DefaultSpillMaxSizeMB size of not delivered part of spill file in megabytes, entries over it are dropped.
+	DefaultSpillMaxSizeMB = 100
+)
+
 var (
 	onceURL sync.Once
 	client  *resty.Client
 )
 
 // Audit provide configuration for audit logging.
+// LogFilePath and LogURLPath are shortcuts for json file and http sinks.
 type Audit struct {
-	LogFilePath string `env:"AUDIT_FILE"`
-	LogURLPath  string `env:"AUDIT_URL"`
+	LogFilePath string `json:"file" yaml:"file" toml:"file" env:"AUDIT_FILE"`
+	LogURLPath  string `json:"url" yaml:"url" toml:"url" env:"AUDIT_URL"`
+	// Sinks additional audit destinations, configured by config file.
+	Sinks []Sink `json:"sinks" yaml:"sinks" toml:"sinks"`
+	// SpillFilePath file for audit entries which can't be queued or delivered, empty disables spillover.
+	SpillFilePath string  `json:"spill_file" yaml:"spill_file" toml:"spill_file" env:"AUDIT_SPILL_FILE"`
+	QueueSize     int     `json:"queue_size" yaml:"queue_size" toml:"queue_size" env:"AUDIT_QUEUE_SIZE"`
+	BatchSize     int     `json:"batch_size" yaml:"batch_size" toml:"batch_size" env:"AUDIT_BATCH_SIZE"`
+	MaxRetries    int     `json:"max_retries" yaml:"max_retries" toml:"max_retries" env:"AUDIT_MAX_RETRIES"`
+	FlushInterval float64 `json:"flush_interval" yaml:"flush_interval" toml:"flush_interval" env:"AUDIT_FLUSH_INTERVAL"`
+	// ChainKey key for signed checkpoints of audit hash chain, empty disables checkpoints.
+	ChainKey        string `json:"chain_key" yaml:"chain_key" toml:"chain_key" env:"AUDIT_CHAIN_KEY"`
+	CheckpointEvery int    `json:"checkpoint_every" yaml:"checkpoint_every" toml:"checkpoint_every" env:"AUDIT_CHECKPOINT_EVERY"`
+	// HashChain enables tamper-evident hash chain in audit file.
+	HashChain bool `json:"hash_chain" yaml:"hash_chain" toml:"hash_chain" env:"AUDIT_HASH_CHAIN"`
+	// MaxSizeMB audit file size for rotation, negative disables size rotation.
+	MaxSizeMB int `json:"max_size_mb" yaml:"max_size_mb" toml:"max_size_mb" env:"AUDIT_MAX_SIZE_MB"`
+	// SpillMaxSizeMB limit of not delivered entries in spill file, negative disables the limit.
+	SpillMaxSizeMB int `json:"spill_max_size_mb" yaml:"spill_max_size_mb" toml:"spill_max_size_mb" env:"AUDIT_SPILL_MAX_SIZE_MB"`
+	// MaxBackups count of rotated audit files kept, 0 keeps all.
+	MaxBackups int `json:"max_backups" yaml:"max_backups" toml:"max_backups" env:"AUDIT_MAX_BACKUPS"`
+	// RotateInterval seconds after which audit file is rotated, 0 disables time rotation.
+	RotateInterval float64 `json:"rotate_interval" yaml:"rotate_interval" toml:"rotate_interval" env:"AUDIT_ROTATE_INTERVAL"`
+	// MaxAge seconds rotated audit files are kept, 0 keeps all.
+	MaxAge float64 `json:"max_age" yaml:"max_age" toml:"max_age" env:"AUDIT_MAX_AGE"`
+	// Compress gzip rotated audit files.
+	Compress bool `json:"compress" yaml:"compress" toml:"compress" env:"AUDIT_COMPRESS"`
 }
 
 // GetLogURLPath provide URL path for audit logging.
@@ -28,6 +69,134 @@ func (a *Audit) GetLogFilePath() string {
 	return a.LogFilePath
 }
 
+// GetSinks provide all audit destinations.
+func (a *Audit) GetSinks() []Sink {
+	sinks := make([]Sink, 0, len(a.Sinks)+2)
+
+	if a.LogFilePath != "" {
+		sinks = append(sinks, Sink{Type: SinkFile, Address: a.LogFilePath})
+	}
+
+	if a.LogURLPath != "" {
+		sinks = append(sinks, Sink{Type: SinkHTTP, Address: a.LogURLPath})
+	}
+
+	return append(sinks, a.Sinks...)
+}
+
+// Enabled returns true if at least one audit destination is configured.
+func (a *Audit) Enabled() bool {
+	return a != nil && (a.LogFilePath != "" || a.LogURLPath != "" || len(a.Sinks) > 0)
+}
+
+// GetSpillFilePath provide file path for undelivered audit entries.
+func (a *Audit) GetSpillFilePath() string {
+	return a.SpillFilePath
+}
+
+// GetSpillMaxSize provide limit of not delivered entries in spill file in bytes, 0 if disabled.
+func (a *Audit) GetSpillMaxSize() int64 {
+	switch {
+	case a.SpillMaxSizeMB < 0:
+		return 0
+	case a.SpillMaxSizeMB == 0:
+		return DefaultSpillMaxSizeMB << 20
+	default:
+		return int64(a.SpillMaxSizeMB) << 20
+	}
+}
+
+// GetQueueSize provide capacity of in-memory queue of every remote audit sink (http, syslog, unix).
+func (a *Audit) GetQueueSize() int {
+	if a.QueueSize <= 0 {
+		return DefaultQueueSize
+	}
+
+	return a.QueueSize
+}
+
+// GetBatchSize provide max count of audit entries sent in one request.
+func (a *Audit) GetBatchSize() int {
+	if a.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+
+	return a.BatchSize
+}
+
+// GetMaxRetries provide count of retries for failed audit batch.
+func (a *Audit) GetMaxRetries() int {
+	if a.MaxRetries < 0 {
+		return 0
+	}
+
+	if a.MaxRetries == 0 {
+		return DefaultMaxRetries
+	}
+
+	return a.MaxRetries
+}
+
+// GetFlushInterval provide max time audit entry waits in queue before sending.
+func (a *Audit) GetFlushInterval() time.Duration {
+	if a.FlushInterval <= 0 {
+		return DefaultFlushInterval
+	}
+
+	return time.Duration(a.FlushInterval * float64(time.Second))
+}
+
+// GetHashChain provide true if audit file records must be hash chained.
+func (a *Audit) GetHashChain() bool {
+	return a.HashChain
+}
+
+// GetChainKey provide key for signed checkpoints of audit hash chain.
+func (a *Audit) GetChainKey() []byte {
+	return []byte(a.ChainKey)
+}
+
+// GetCheckpointEvery provide count of records between signed checkpoints.
+func (a *Audit) GetCheckpointEvery() int {
+	if a.CheckpointEvery <= 0 {
+		return DefaultCheckpointEvery
+	}
+
+	return a.CheckpointEvery
+}
+
+// GetMaxSize provide audit file size in bytes after which it is rotated, 0 if disabled.
+func (a *Audit) GetMaxSize() int64 {
+	switch {
+	case a.MaxSizeMB < 0:
+		return 0
+	case a.MaxSizeMB == 0:
+		return DefaultMaxSizeMB << 20
+	default:
+		return int64(a.MaxSizeMB) << 20
+	}
+}
+
+// GetRotateInterval provide period after which audit file is rotated.
+func (a *Audit) GetRotateInterval() time.Duration {
+	return time.Duration(a.RotateInterval * float64(time.Second))
+}
+
+// GetMaxBackups provide count of rotated audit files kept.
+func (a *Audit) GetMaxBackups() int {
+	return a.MaxBackups
+}
+
+// GetMaxAge provide age of rotated audit files kept.
+func (a *Audit) GetMaxAge() time.Duration {
+	return time.Duration(a.MaxAge * float64(time.Second))
+}
+
+// GetCompress provide true if rotated audit files must be compressed.
+func (a *Audit) GetCompress() bool {
+	return a.Compress
+}
+
 // GetURLWriter provide resty client for audit logging.
 func (a *Audit) GetURLWriter() *resty.Client {
 	onceURL.Do( // функция ниже выполнится только один раз
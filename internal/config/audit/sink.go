@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Sink types.
+const (
+	SinkFile   = "file"
+	SinkHTTP   = "http"
+	SinkSyslog = "syslog"
+	SinkUnix   = "unix"
+	SinkStdout = "stdout"
+)
+
+// Record formats.
+const (
+	FormatJSON   = "json"
+	FormatCEF    = "cef"
+	FormatLogfmt = "logfmt"
+)
+
+// Syslog defaults.
+const (
+	DefaultSyslogNetwork  = "unixgram"
+	DefaultSyslogAddress  = "/dev/log"
+	DefaultSyslogFacility = "local0"
+)
+
+// ErrInvalidSink sink settings are not supported.
+var ErrInvalidSink = errors.New("invalid audit sink")
+
+// Sink settings of single audit destination.
+//
+// Address is a file path for file, URL for http, socket path or host:port for syslog
+// and socket path for unix sinks. Level is the lowest level of written events:
+// events of failed requests are written with warn (4xx) and error (5xx) levels.
+type Sink struct {
+	Type    string `json:"type" yaml:"type" toml:"type"`
+	Address string `json:"address" yaml:"address" toml:"address"`
+	// Network of syslog sink: unixgram or udp.
+	Network  string `json:"network" yaml:"network" toml:"network"`
+	Format   string `json:"format" yaml:"format" toml:"format"`
+	Level    string `json:"level" yaml:"level" toml:"level"`
+	Facility string `json:"facility" yaml:"facility" toml:"facility"`
+}
+
+// GetFormat provide record format, json by default.
+func (s Sink) GetFormat() string {
+	if s.Format == "" {
+		return FormatJSON
+	}
+
+	return s.Format
+}
+
+// GetLevel provide lowest level of written events, info by default.
+func (s Sink) GetLevel() string {
+	if s.Level == "" {
+		return "info"
+	}
+
+	return s.Level
+}
+
+// GetNetwork provide syslog network.
+func (s Sink) GetNetwork() string {
+	if s.Network == "" && s.Type == SinkSyslog {
+		return DefaultSyslogNetwork
+	}
+
+	return s.Network
+}
+
+// GetAddress provide sink address, local syslog socket for syslog by default.
+func (s Sink) GetAddress() string {
+	if s.Address == "" && s.Type == SinkSyslog && s.GetNetwork() == DefaultSyslogNetwork {
+		return DefaultSyslogAddress
+	}
+
+	return s.Address
+}
+
+// GetFacility provide syslog facility name.
+func (s Sink) GetFacility() string {
+	if s.Facility == "" {
+		return DefaultSyslogFacility
+	}
+
+	return s.Facility
+}
+
+// Valid returns ErrInvalidSink if settings can't be used.
+func (s Sink) Valid() error {
+	if !slices.Contains([]string{FormatJSON, FormatCEF, FormatLogfmt}, s.GetFormat()) {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidSink, s.Format)
+	}
+
+	switch s.Type {
+	case SinkStdout:
+		return nil
+	case SinkFile, SinkUnix:
+	case SinkHTTP:
+		// удалённый endpoint принимает батчи в виде JSON массива.
+		if s.GetFormat() != FormatJSON {
+			return fmt.Errorf("%w: http sink supports only json format", ErrInvalidSink)
+		}
+	case SinkSyslog:
+		if !slices.Contains([]string{"unixgram", "udp"}, s.GetNetwork()) {
+			return fmt.Errorf("%w: unsupported syslog network %q", ErrInvalidSink, s.Network)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidSink, s.Type)
+	}
+
+	if s.GetAddress() == "" {
+		return fmt.Errorf("%w: %s sink requires address", ErrInvalidSink, s.Type)
+	}
+
+	return nil
+}
@@ -2,19 +2,23 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"reflect" // nolint:depguard // used for config merging
-
-	"github.com/goccy/go-json"
-
+	"io/fs"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Pklerik/urlshortener/internal/config/audit"
 	"github.com/Pklerik/urlshortener/internal/config/dbconf"
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
+	"github.com/Pklerik/urlshortener/internal/dictionary"
+	"github.com/Pklerik/urlshortener/internal/listener"
 )
 
+// ErrAddressPort address has no port.
+var ErrAddressPort = errors.New("address must be host:port")
+
 // StartupFlagsParser provide interface for app flags.
 type StartupFlagsParser interface {
 	GetServerAddress() Address
@@ -23,23 +27,68 @@ type StartupFlagsParser interface {
 	GetLogLevel() string
 	GetLocalStorage() string
 	GetDatabaseConf() (dbconf.DBConfigurer, error)
+	GetDatabaseReplicaConfs() []dbconf.DBConfigurer
+	GetReadYourWritesWindow() time.Duration
+	GetSkipMigrations() bool
 	GetSecretKey() string
 	GetAudit() *audit.Audit
 	GetTLS() bool
+	GetTLSConf() *tlsconf.Conf
+	GetRestoreWindow() time.Duration
+	GetPurgeRetention() time.Duration
+	GetPurgeInterval() time.Duration
+	GetAdminToken() string
+	GetJobWorkers() int
+	GetJobMaxAttempts() int
+	GetJobsFile() string
+	GetShutdownTimeout() time.Duration
+	GetShutdownDelay() time.Duration
+	GetClickSampleRate() float64
+	GetDataDir() string
+	GetAdminAddress() string
+	GetMetricsAddress() string
+	GetSocketMode() fs.FileMode
+	GetTraceExporter() string
+	GetTraceEndpoint() string
+	GetTraceSampleRatio() float64
+	GetRedirectLogSampleRate() float64
 }
 
 // StartupFlags app startup flags.
 type StartupFlags struct {
-	ServerAddress *Address     `json:"server_address" env:"SERVER_ADDRESS"`
-	DBConf        *dbconf.Conf `json:"database_dsn" env:"DATABASE_DSN"`
-	Audit         *audit.Audit `json:"audit" env:"AUDIT"`
-	BaseURL       string       `json:"base_url" env:"BASE_URL"`
-	LogLevel      string       `json:"log_level" env:"LOG_LEVEL"`
-	LocalStorage  string       `json:"local_storage_path" env:"FILE_STORAGE_PATH"`
-	SecretKey     string       `json:"secret_key" env:"SECRET_KEY"`
-	FileConfig    string       `env:"CONFIG"`
-	Timeout       float64      `json:"timeout" env:"SERVER_TIMEOUT"`
-	TLS           bool         `json:"enable_https" env:"ENABLE_HTTPS"`
+	ServerAddress  *Address        `json:"server_address" env:"SERVER_ADDRESS"`
+	DBConf         *dbconf.Conf    `json:"database_dsn" env:"DATABASE_DSN"`
+	Audit          *audit.Audit    `json:"audit" env:"AUDIT"`
+	TLSConf        *tlsconf.Conf   `json:"tls" env:"TLS"`
+	DBReplicas     dbconf.Replicas `json:"database_replica_dsn" env:"DATABASE_REPLICA_DSN"`
+	BaseURL        string          `json:"base_url" env:"BASE_URL"`
+	LogLevel       string          `json:"log_level" env:"LOG_LEVEL"`
+	Environment    string          `json:"environment" env:"APP_ENV"`
+	LocalStorage   string          `json:"file_storage_path" env:"FILE_STORAGE_PATH"`
+	SecretKey      string          `json:"secret_key" env:"SECRET_KEY"`
+	AdminToken     string          `json:"admin_token" env:"ADMIN_TOKEN"`
+	JobsFile       string          `json:"jobs_file_path" env:"JOBS_FILE_PATH"`
+	DataDir        string          `json:"data_dir" env:"DATA_DIR"`
+	AdminAddress   string          `json:"admin_address" env:"ADMIN_ADDRESS"`
+	MetricsAddress string          `json:"metrics_address" env:"METRICS_ADDRESS"`
+	SocketMode     string          `json:"socket_mode" env:"SOCKET_MODE"`
+	TraceExporter  string          `json:"trace_exporter" env:"TRACE_EXPORTER"`
+	TraceEndpoint  string          `json:"trace_endpoint" env:"TRACE_ENDPOINT"`
+	FileConfig     string          `env:"CONFIG"`
+	Timeout        float64         `json:"timeout" env:"SERVER_TIMEOUT"`
+	ReadYourWrites float64         `json:"read_your_writes" env:"READ_YOUR_WRITES"`
+	RestoreWindow  float64         `json:"restore_window" env:"RESTORE_WINDOW"`
+	PurgeRetention float64         `json:"purge_retention" env:"PURGE_RETENTION"`
+	PurgeInterval  float64         `json:"purge_interval" env:"PURGE_INTERVAL"`
+	ShutdownTime   float64         `json:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	ShutdownDelay  float64         `json:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	ClickSample    float64         `json:"webhook_click_sample_rate" env:"WEBHOOK_CLICK_SAMPLE_RATE"`
+	TraceSample    float64         `json:"trace_sample_ratio" env:"TRACE_SAMPLE_RATIO"`
+	RedirectLog    float64         `json:"redirect_log_sample_rate" env:"REDIRECT_LOG_SAMPLE_RATE"`
+	JobWorkers     int             `json:"job_workers" env:"JOB_WORKERS"`
+	JobMaxAttempts int             `json:"job_max_attempts" env:"JOB_MAX_ATTEMPTS"`
+	TLS            bool            `json:"enable_https" env:"ENABLE_HTTPS"`
+	SkipMigrations bool            `json:"skip_migrations" env:"SKIP_MIGRATIONS"`
 }
 
 // GetServerAddress returns ServerAddress.
@@ -47,9 +96,17 @@ func (sf *StartupFlags) GetServerAddress() Address {
 	return *sf.ServerAddress
 }
 
-// GetAddressShortURL returns AddressShortURL.
+// GetAddressShortURL returns base url of short links without trailing slash.
+// With TLS listener http scheme is replaced by https, so links don't depend on redirect.
 func (sf *StartupFlags) GetAddressShortURL() string {
-	return sf.BaseURL
+	baseURL := strings.TrimSuffix(sf.BaseURL, "/")
+	if sf.TLS {
+		if rest, ok := strings.CutPrefix(baseURL, "http://"); ok {
+			return "https://" + rest
+		}
+	}
+
+	return baseURL
 }
 
 // GetTimeout returns GetTimeout.
@@ -85,6 +142,21 @@ func (sf *StartupFlags) GetDatabaseConf() (dbconf.DBConfigurer, error) {
 	return sf.DBConf, nil
 }
 
+// GetDatabaseReplicaConfs returns configurations of read only replicas.
+func (sf *StartupFlags) GetDatabaseReplicaConfs() []dbconf.DBConfigurer {
+	return sf.DBReplicas.Configurers()
+}
+
+// GetReadYourWritesWindow returns period after user writes when reads go to primary.
+func (sf *StartupFlags) GetReadYourWritesWindow() time.Duration {
+	return time.Duration(sf.ReadYourWrites * float64(time.Second))
+}
+
+// GetSkipMigrations returns true if migrations must not be applied on startup.
+func (sf *StartupFlags) GetSkipMigrations() bool {
+	return sf.SkipMigrations
+}
+
 // GetAudit returns Audit config.
 func (sf *StartupFlags) GetAudit() *audit.Audit {
 	return sf.Audit
@@ -95,13 +167,150 @@ func (sf *StartupFlags) GetTLS() bool {
 	return sf.TLS
 }
 
-// Address base struct.
+// GetTLSConf returns TLS listener config, nil gives self-signed certificate.
+func (sf *StartupFlags) GetTLSConf() *tlsconf.Conf {
+	return sf.TLSConf
+}
+
+// GetRestoreWindow returns period after deletion when owner can restore link.
+func (sf *StartupFlags) GetRestoreWindow() time.Duration {
+	return time.Duration(sf.RestoreWindow * float64(time.Second))
+}
+
+// GetPurgeRetention returns period after deletion when link is purged by background job.
+func (sf *StartupFlags) GetPurgeRetention() time.Duration {
+	return time.Duration(sf.PurgeRetention * float64(time.Second))
+}
+
+// GetPurgeInterval returns period between background purge runs. Zero disables purge job.
+func (sf *StartupFlags) GetPurgeInterval() time.Duration {
+	return time.Duration(sf.PurgeInterval * float64(time.Second))
+}
+
+// GetAdminToken returns bearer token for admin endpoints. Empty token disables them.
+func (sf *StartupFlags) GetAdminToken() string {
+	return sf.AdminToken
+}
+
+// GetJobWorkers returns number of background job workers.
+func (sf *StartupFlags) GetJobWorkers() int {
+	return sf.JobWorkers
+}
+
+// GetJobMaxAttempts returns number of attempts before job is marked as failed.
+func (sf *StartupFlags) GetJobMaxAttempts() int {
+	return sf.JobMaxAttempts
+}
+
+// GetJobsFile returns path of local jobs storage file.
+func (sf *StartupFlags) GetJobsFile() string {
+	return sf.JobsFile
+}
+
+// GetDataDir returns directory for relative data file paths, working directory by default.
+func (sf *StartupFlags) GetDataDir() string {
+	if sf.DataDir == "" {
+		return dictionary.BasePath
+	}
+
+	return sf.DataDir
+}
+
+// GetAdminAddress returns address of admin listener serving metrics and health probes. Empty disables it.
+func (sf *StartupFlags) GetAdminAddress() string {
+	return sf.AdminAddress
+}
+
+// GetMetricsAddress returns address of listener serving only metrics. Empty disables it.
+func (sf *StartupFlags) GetMetricsAddress() string {
+	return sf.MetricsAddress
+}
+
+// GetSocketMode returns permissions of created Unix sockets, listener.DefaultSocketMode if not set or invalid.
+func (sf *StartupFlags) GetSocketMode() fs.FileMode {
+	mode, err := parseSocketMode(sf.SocketMode)
+	if err != nil {
+		return listener.DefaultSocketMode
+	}
+
+	return mode
+}
+
+// parseSocketMode parses octal permissions like 0660.
+func parseSocketMode(s string) (fs.FileMode, error) {
+	if s == "" {
+		return listener.DefaultSocketMode, nil
+	}
+
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > uint64(fs.ModePerm) {
+		return 0, fmt.Errorf("parseSocketMode: %q is not octal permissions", s)
+	}
+
+	return fs.FileMode(mode), nil
+}
+
+// GetTraceExporter returns trace exporter: otlp, stdout or file. Empty disables tracing.
+func (sf *StartupFlags) GetTraceExporter() string {
+	return sf.TraceExporter
+}
+
+// GetTraceEndpoint returns OTLP/HTTP traces url for otlp exporter or file path for file exporter.
+func (sf *StartupFlags) GetTraceEndpoint() string {
+	return sf.TraceEndpoint
+}
+
+// GetTraceSampleRatio returns share of new traces which are exported, from 0 to 1.
+func (sf *StartupFlags) GetTraceSampleRatio() float64 {
+	return sf.TraceSample
+}
+
+// GetRedirectLogSampleRate returns share of redirect responses written to access log, from 0 to 1.
+func (sf *StartupFlags) GetRedirectLogSampleRate() float64 {
+	return sf.RedirectLog
+}
+
+// GetShutdownTimeout returns deadline for graceful shutdown of background tasks.
+func (sf *StartupFlags) GetShutdownTimeout() time.Duration {
+	return time.Duration(sf.ShutdownTime * float64(time.Second))
+}
+
+// GetShutdownDelay returns pause between failing readiness and closing listeners on graceful shutdown.
+func (sf *StartupFlags) GetShutdownDelay() time.Duration {
+	return time.Duration(sf.ShutdownDelay * float64(time.Second))
+}
+
+// GetClickSampleRate returns share of redirects published as link.clicked events.
+func (sf *StartupFlags) GetClickSampleRate() float64 {
+	return sf.ClickSample
+}
+
+// Address protocols of not TCP listeners.
+const (
+	ProtocolUnix    = "unix"
+	ProtocolSystemd = "systemd"
+)
+
+// Address base struct. Unix socket keeps its path in Path, systemd socket keeps its name in Host.
 type Address struct {
 	Protocol string
 	Host     string
+	Path     string
 	Port     int
 }
 
+// ListenAddress returns address for listener.Open: :port, unix:///path or systemd://name.
+func (a Address) ListenAddress() string {
+	switch a.Protocol {
+	case ProtocolUnix:
+		return listener.SchemeUnix + a.Path
+	case ProtocolSystemd:
+		return listener.SchemeSystemd + a.Host
+	}
+
+	return ":" + strconv.Itoa(a.Port)
+}
+
 // UnmarshalText provide text unmarshaling for Address string.
 func (a *Address) UnmarshalText(text []byte) error {
 	err := a.Set(string(text))
@@ -114,6 +323,10 @@ func (a *Address) UnmarshalText(text []byte) error {
 
 // String provide string representation of Address.
 func (a *Address) String() string {
+	if a.Protocol == ProtocolUnix || a.Protocol == ProtocolSystemd {
+		return a.ListenAddress()
+	}
+
 	if a.Protocol == "" {
 		a.Protocol = "http"
 	}
@@ -131,6 +344,21 @@ func (a *Address) String() string {
 
 // Set parse Address from string.
 func (a *Address) Set(flagValue string) error {
+	if path, ok := strings.CutPrefix(flagValue, listener.SchemeUnix); ok {
+		if path == "" {
+			return fmt.Errorf("can't set Address for %s: %w", flagValue, listener.ErrEmptySocketPath)
+		}
+
+		*a = Address{Protocol: ProtocolUnix, Path: path}
+
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(flagValue, listener.SchemeSystemd); ok {
+		*a = Address{Protocol: ProtocolSystemd, Host: name}
+		return nil
+	}
+
 	flagValueMod := flagValue
 	if strings.Contains(flagValue, "http") {
 		a.Protocol = strings.Split(flagValue, ":")[0]
@@ -144,9 +372,14 @@ func (a *Address) Set(flagValue string) error {
 		flagValueMod = strings.Split(flagValueMod, "/")[0]
 	}
 
-	a.Host = strings.Split(flagValueMod, ":")[0]
+	hostPort := strings.Split(flagValueMod, ":")
+	if len(hostPort) != 2 {
+		return fmt.Errorf("can't set Address for %s: %w", flagValue, ErrAddressPort)
+	}
+
+	a.Host = hostPort[0]
 
-	port, err := strconv.Atoi(strings.Split(flagValueMod, ":")[1])
+	port, err := strconv.Atoi(hostPort[1])
 	if err != nil {
 		return fmt.Errorf("can't set Address Port for %s: %w", flagValue, err)
 	}
@@ -166,83 +399,3 @@ func (a *Address) Set(flagValue string) error {
 
 	return nil
 }
-
-// UnmarshalJSON provide custom unmarshaling for StartupFlags.
-func (sf *StartupFlags) UnmarshalJSON(data []byte) error {
-	unmarshalMap := make(map[string]interface{}, reflect.TypeOf(*sf).NumField())
-
-	err := json.Unmarshal(data, &unmarshalMap)
-	if err != nil {
-		return fmt.Errorf("UnmarshalJSON: %w", err)
-	}
-
-	for key, value := range unmarshalMap {
-		switch key {
-		case "timeout":
-			if v, ok := value.(float64); ok {
-				sf.Timeout = v
-			}
-		case "server_address":
-			if v, ok := value.(string); ok {
-				adr := Address{}
-				if err := adr.UnmarshalText([]byte(v)); err != nil {
-					return fmt.Errorf("UnmarshalJSON server_address: %w", err)
-				}
-
-				if sf.ServerAddress == nil {
-					sf.ServerAddress = new(Address)
-				}
-
-				*(sf.ServerAddress) = adr
-			}
-		case "db_conf":
-			if v, ok := value.(string); ok {
-				dbConf := &dbconf.Conf{}
-
-				err := json.Unmarshal([]byte(v), dbConf)
-				if err != nil {
-					return fmt.Errorf("UnmarshalJSON db_conf: %w", err)
-				}
-
-				if sf.DBConf == nil {
-					sf.DBConf = new(dbconf.Conf)
-				}
-
-				(*sf.DBConf) = (*dbConf)
-			}
-		case "base_url":
-			if v, ok := value.(string); ok {
-				sf.BaseURL = v
-			}
-		case "log_level":
-			if v, ok := value.(string); ok {
-				sf.LogLevel = v
-			}
-		case "file_storage_path":
-			if v, ok := value.(string); ok {
-				sf.LocalStorage = v
-			}
-		case "secret_key":
-			if v, ok := value.(string); ok {
-				sf.SecretKey = v
-			}
-		case "audit":
-			if v, ok := value.(string); ok {
-				auditConf := &audit.Audit{}
-
-				err := json.Unmarshal([]byte(v), auditConf)
-				if err != nil {
-					return fmt.Errorf("UnmarshalJSON audit: %w", err)
-				}
-
-				sf.Audit = auditConf
-			}
-		case "enable_https":
-			if v, ok := value.(bool); ok {
-				sf.TLS = v
-			}
-		}
-	}
-
-	return nil
-}
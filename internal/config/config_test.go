@@ -1,9 +1,11 @@
 package config
 
 import (
+	"io/fs"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddress_Set(t *testing.T) {
@@ -37,6 +39,12 @@ func TestAddress_Set(t *testing.T) {
 				Host:     "localhost",
 				Port:     8080,
 			}}},
+		{name: "unix socket", args: args{flagValue: "unix:///run/shortener.sock"}, wantErr: false,
+			want: want{address: &Address{Protocol: ProtocolUnix, Path: "/run/shortener.sock"}}},
+		{name: "empty unix socket", args: args{flagValue: "unix://"}, wantErr: true,
+			want: want{address: &Address{}}},
+		{name: "systemd", args: args{flagValue: "systemd://public"}, wantErr: false,
+			want: want{address: &Address{Protocol: ProtocolSystemd, Host: "public"}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -52,3 +60,62 @@ func TestAddress_Set(t *testing.T) {
 		})
 	}
 }
+
+func TestStartupFlags_GetAddressShortURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		tls     bool
+	}{
+		{name: "http", baseURL: "http://localhost:8080", want: "http://localhost:8080"},
+		{name: "trailing_slash", baseURL: "https://short.example/", want: "https://short.example"},
+		{name: "tls_upgrades_scheme", baseURL: "http://short.example:8443", tls: true, want: "https://short.example:8443"},
+		{name: "tls_keeps_https", baseURL: "https://short.example", tls: true, want: "https://short.example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &StartupFlags{BaseURL: tt.baseURL, TLS: tt.tls}
+			assert.Equal(t, tt.want, sf.GetAddressShortURL())
+		})
+	}
+}
+
+func TestAddress_ListenAddress(t *testing.T) {
+	for _, addr := range []string{"unix:///run/shortener.sock", "systemd://public", "systemd://"} {
+		a := new(Address)
+		require.NoError(t, a.Set(addr))
+		assert.Equal(t, addr, a.ListenAddress())
+		assert.Equal(t, addr, a.String())
+	}
+
+	a := new(Address)
+	require.NoError(t, a.Set("http://localhost:8081"))
+	assert.Equal(t, ":8081", a.ListenAddress())
+}
+
+func TestStartupFlags_GetSocketMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    fs.FileMode
+		wantErr bool
+	}{
+		{name: "default", want: 0o660},
+		{name: "octal", mode: "0600", want: 0o600},
+		{name: "not_octal", mode: "rw", want: 0o660, wantErr: true},
+		{name: "too_big", mode: "7777", want: 0o660, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &StartupFlags{SocketMode: tt.mode, Timeout: 1, BaseURL: "http://localhost:8080"}
+			assert.Equal(t, tt.want, sf.GetSocketMode())
+
+			if tt.wantErr {
+				assert.ErrorIs(t, sf.Valid(), ErrInvalidConfig)
+			} else {
+				assert.NoError(t, sf.Valid())
+			}
+		})
+	}
+}
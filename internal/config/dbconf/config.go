@@ -21,6 +21,12 @@ var (
 	ErrSetDefault = errors.New("unable to set defaults")
 )
 
+// redactedPassword replaces passwords in printed DSN.
+const redactedPassword = "xxxxx"
+
+// secretOptions connection options holding passwords.
+var secretOptions = []string{"password", "sslpassword"}
+
 var (
 	// DefaultGooseDrier - default driver if not presented.
 	DefaultGooseDrier = "postgres"
@@ -71,9 +77,25 @@ func (dbc *Conf) UnmarshalText(text []byte) error {
 	return nil
 }
 
-// String provide string representation of Conf.
+// String provide string representation of Conf without passwords.
 func (dbc *Conf) String() string {
-	return fmt.Sprintf("DSN: %s", dbc.RawString)
+	return fmt.Sprintf("DSN: %s", dbc.Redacted())
+}
+
+// Redacted returns RawString with password and password options replaced by xxxxx.
+func (dbc *Conf) Redacted() string {
+	redacted := dbc.RawString
+	if dbc.Password != "" {
+		redacted = strings.Replace(redacted, ":"+dbc.Password+"@", ":"+redactedPassword+"@", 1)
+	}
+
+	for _, name := range secretOptions {
+		if value, ok := dbc.Options[name]; ok && value != "" {
+			redacted = strings.ReplaceAll(redacted, name+"="+value, name+"="+redactedPassword)
+		}
+	}
+
+	return redacted
 }
 
 // Set parse Conf from string.
@@ -134,16 +156,24 @@ func getCredentials(s string) (struct {
 	pass string
 }, error) {
 	credentialsExp := regexp.MustCompile(`:\/\/.*@`)
-	credentials := strings.Trim(credentialsExp.FindString(s), ":/@")
+	credentials := strings.TrimSuffix(strings.TrimPrefix(credentialsExp.FindString(s), "://"), "@")
 
-	credentialsSl := strings.Split(credentials, ":")
-	if len(credentialsSl) < 2 {
+	// пароль может не входить в DSN, если он задан через DATABASE_PASSWORD_FILE.
+	credentialsSl := strings.SplitN(credentials, ":", 2)
+	if credentialsSl[0] == "" {
 		return struct {
 			user string
 			pass string
 		}{}, ErrIncorrectDatabaseURL
 	}
 
+	if len(credentialsSl) == 1 {
+		return struct {
+			user string
+			pass string
+		}{user: credentialsSl[0]}, nil
+	}
+
 	return struct {
 		user string
 		pass string
@@ -221,7 +251,11 @@ func (dbc *Conf) SetDefault() error {
 
 // GetConnString provide coonection string for db.
 func (dbc *Conf) GetConnString() string {
-	ps := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s", dbc.User, dbc.Password, dbc.Database, dbc.Host, dbc.Port)
+	ps := fmt.Sprintf("user=%s dbname=%s host=%s port=%s", dbc.User, dbc.Database, dbc.Host, dbc.Port)
+	if dbc.Password != "" {
+		ps += fmt.Sprintf(" password=%s", dbc.Password)
+	}
+
 	if dbc.Options == nil {
 		return ps
 	}
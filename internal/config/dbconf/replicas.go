@@ -0,0 +1,61 @@
+package dbconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Replicas contain configurations of read only database replicas.
+type Replicas []*Conf
+
+// UnmarshalText provide text unmarshaling for comma separated replica DSNs.
+func (rs *Replicas) UnmarshalText(text []byte) error {
+	if err := rs.Set(string(text)); err != nil {
+		return fmt.Errorf("UnmarshalText: %w", err)
+	}
+
+	return nil
+}
+
+// String provide string representation of Replicas.
+func (rs *Replicas) String() string {
+	if rs == nil {
+		return ""
+	}
+
+	dsns := make([]string, 0, len(*rs))
+	for _, replica := range *rs {
+		dsns = append(dsns, replica.String())
+	}
+
+	return strings.Join(dsns, ", ")
+}
+
+// Set parse one or several comma separated replica DSNs and appends them to Replicas.
+func (rs *Replicas) Set(s string) error {
+	for _, dsn := range strings.Split(s, ",") {
+		dsn = strings.TrimSpace(dsn)
+		if dsn == "" {
+			continue
+		}
+
+		replica := new(Conf)
+		if err := replica.Set(dsn); err != nil {
+			return fmt.Errorf("replica %d: %w", len(*rs), err)
+		}
+
+		*rs = append(*rs, replica)
+	}
+
+	return nil
+}
+
+// Configurers returns Replicas as slice of DBConfigurer.
+func (rs Replicas) Configurers() []DBConfigurer {
+	confs := make([]DBConfigurer, 0, len(rs))
+	for _, replica := range rs {
+		confs = append(confs, replica)
+	}
+
+	return confs
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect" // nolint:depguard // used to detect empty sections and expand references
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/Pklerik/urlshortener/internal/config/dbconf"
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
+)
+
+// ErrUnknownKeys config file contains keys which are not supported.
+var ErrUnknownKeys = errors.New("unknown config keys")
+
+// fileConfig layout of config file, same for JSON, YAML and TOML.
+type fileConfig struct {
+	Audit          *audit.Audit `json:"audit" yaml:"audit" toml:"audit"`
+	Database       fileDatabase `json:"database" yaml:"database" toml:"database"`
+	TLS            fileTLS      `json:"tls" yaml:"tls" toml:"tls"`
+	ServerAddress  string       `json:"server_address" yaml:"server_address" toml:"server_address"`
+	BaseURL        string       `json:"base_url" yaml:"base_url" toml:"base_url"`
+	LogLevel       string       `json:"log_level" yaml:"log_level" toml:"log_level"`
+	Environment    string       `json:"environment" yaml:"environment" toml:"environment"`
+	LocalStorage   string       `json:"file_storage_path" yaml:"file_storage_path" toml:"file_storage_path"`
+	SecretKey      string       `json:"secret_key" yaml:"secret_key" toml:"secret_key"`
+	AdminToken     string       `json:"admin_token" yaml:"admin_token" toml:"admin_token"`
+	JobsFile       string       `json:"jobs_file_path" yaml:"jobs_file_path" toml:"jobs_file_path"`
+	DataDir        string       `json:"data_dir" yaml:"data_dir" toml:"data_dir"`
+	AdminAddress   string       `json:"admin_address" yaml:"admin_address" toml:"admin_address"`
+	MetricsAddress string       `json:"metrics_address" yaml:"metrics_address" toml:"metrics_address"`
+	SocketMode     string       `json:"socket_mode" yaml:"socket_mode" toml:"socket_mode"`
+	TraceExporter  string       `json:"trace_exporter" yaml:"trace_exporter" toml:"trace_exporter"`
+	TraceEndpoint  string       `json:"trace_endpoint" yaml:"trace_endpoint" toml:"trace_endpoint"`
+	Timeout        float64      `json:"timeout" yaml:"timeout" toml:"timeout"`
+	RestoreWindow  float64      `json:"restore_window" yaml:"restore_window" toml:"restore_window"`
+	PurgeRetention float64      `json:"purge_retention" yaml:"purge_retention" toml:"purge_retention"`
+	PurgeInterval  float64      `json:"purge_interval" yaml:"purge_interval" toml:"purge_interval"`
+	ShutdownTime   float64      `json:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	ShutdownDelay  float64      `json:"shutdown_delay" yaml:"shutdown_delay" toml:"shutdown_delay"`
+	ClickSample    float64      `json:"webhook_click_sample_rate" yaml:"webhook_click_sample_rate" toml:"webhook_click_sample_rate"`
+	TraceSample    float64      `json:"trace_sample_ratio" yaml:"trace_sample_ratio" toml:"trace_sample_ratio"`
+	RedirectLog    float64      `json:"redirect_log_sample_rate" yaml:"redirect_log_sample_rate" toml:"redirect_log_sample_rate"`
+	JobWorkers     int          `json:"job_workers" yaml:"job_workers" toml:"job_workers"`
+	JobMaxAttempts int          `json:"job_max_attempts" yaml:"job_max_attempts" toml:"job_max_attempts"`
+
+	// плоские ключи БД и TLS поддерживаются для совместимости со старыми JSON файлами.
+	DatabaseDSN    string  `json:"database_dsn" yaml:"database_dsn" toml:"database_dsn"`
+	ReplicaDSN     string  `json:"database_replica_dsn" yaml:"database_replica_dsn" toml:"database_replica_dsn"`
+	ReadYourWrites float64 `json:"read_your_writes" yaml:"read_your_writes" toml:"read_your_writes"`
+	SkipMigrations bool    `json:"skip_migrations" yaml:"skip_migrations" toml:"skip_migrations"`
+	EnableHTTPS    bool    `json:"enable_https" yaml:"enable_https" toml:"enable_https"`
+}
+
+// fileDatabase database section of config file.
+type fileDatabase struct {
+	DSN            string   `json:"dsn" yaml:"dsn" toml:"dsn"`
+	Replicas       []string `json:"replicas" yaml:"replicas" toml:"replicas"`
+	ReadYourWrites float64  `json:"read_your_writes" yaml:"read_your_writes" toml:"read_your_writes"`
+	SkipMigrations bool     `json:"skip_migrations" yaml:"skip_migrations" toml:"skip_migrations"`
+}
+
+// fileTLS tls section of config file.
+type fileTLS struct {
+	tlsconf.Conf `yaml:",inline"`
+	Enabled      bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+}
+
+// ReadFile reads config file. Format is chosen by extension: .yaml and .yml for YAML,
+// .toml for TOML, JSON otherwise. Unknown keys are rejected.
+// ${env:NAME} and ${file:/path} references in values are replaced by env value and file content.
+func ReadFile(path string) (*StartupFlags, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ReadFile: %w", err)
+	}
+
+	fc := new(fileConfig)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = decodeYAML(data, fc)
+	case ".toml":
+		err = decodeTOML(data, fc)
+	default:
+		err = decodeJSON(data, fc)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("ReadFile %s: %w", path, err)
+	}
+
+	if err := expandRefs(reflect.ValueOf(fc), filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("ReadFile %s: %w", path, err)
+	}
+
+	sf, err := fc.startupFlags()
+	if err != nil {
+		return nil, fmt.Errorf("ReadFile %s: %w", path, err)
+	}
+
+	return sf, nil
+}
+
+func decodeJSON(data []byte, fc *fileConfig) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(fc); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decodeJSON: %w", err)
+	}
+
+	return nil
+}
+
+func decodeYAML(data []byte, fc *fileConfig) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	// пустой файл даёт io.EOF и считается пустой конфигурацией.
+	if err := dec.Decode(fc); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decodeYAML: %w", err)
+	}
+
+	return nil
+}
+
+func decodeTOML(data []byte, fc *fileConfig) error {
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(fc)
+	if err != nil {
+		return fmt.Errorf("decodeTOML: %w", err)
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+
+		return fmt.Errorf("decodeTOML: %w: %s", ErrUnknownKeys, strings.Join(keys, ", "))
+	}
+
+	return nil
+}
+
+// startupFlags converts file layout to StartupFlags. Nested sections take priority over flat keys.
+func (fc *fileConfig) startupFlags() (*StartupFlags, error) {
+	sf := &StartupFlags{
+		BaseURL:        fc.BaseURL,
+		LogLevel:       fc.LogLevel,
+		Environment:    fc.Environment,
+		LocalStorage:   fc.LocalStorage,
+		SecretKey:      fc.SecretKey,
+		AdminToken:     fc.AdminToken,
+		JobsFile:       fc.JobsFile,
+		DataDir:        fc.DataDir,
+		AdminAddress:   fc.AdminAddress,
+		MetricsAddress: fc.MetricsAddress,
+		SocketMode:     fc.SocketMode,
+		TraceExporter:  fc.TraceExporter,
+		TraceEndpoint:  fc.TraceEndpoint,
+		Timeout:        fc.Timeout,
+		ReadYourWrites: cmp.Or(fc.Database.ReadYourWrites, fc.ReadYourWrites),
+		RestoreWindow:  fc.RestoreWindow,
+		PurgeRetention: fc.PurgeRetention,
+		PurgeInterval:  fc.PurgeInterval,
+		ShutdownTime:   fc.ShutdownTime,
+		ShutdownDelay:  fc.ShutdownDelay,
+		ClickSample:    fc.ClickSample,
+		TraceSample:    fc.TraceSample,
+		RedirectLog:    fc.RedirectLog,
+		JobWorkers:     fc.JobWorkers,
+		JobMaxAttempts: fc.JobMaxAttempts,
+		TLS:            fc.TLS.Enabled || fc.EnableHTTPS,
+		SkipMigrations: fc.Database.SkipMigrations || fc.SkipMigrations,
+	}
+
+	if fc.ServerAddress != "" {
+		sf.ServerAddress = new(Address)
+		if err := sf.ServerAddress.Set(fc.ServerAddress); err != nil {
+			return nil, fmt.Errorf("server_address: %w", err)
+		}
+	}
+
+	if dsn := cmp.Or(fc.Database.DSN, fc.DatabaseDSN); dsn != "" {
+		sf.DBConf = new(dbconf.Conf)
+		if err := sf.DBConf.Set(dsn); err != nil {
+			return nil, fmt.Errorf("database dsn: %w", err)
+		}
+	}
+
+	replicas := fc.ReplicaDSN
+	if len(fc.Database.Replicas) > 0 {
+		replicas = strings.Join(fc.Database.Replicas, ",")
+	}
+
+	if err := sf.DBReplicas.Set(replicas); err != nil {
+		return nil, fmt.Errorf("database replicas: %w", err)
+	}
+
+	// пустые секции аудита и TLS не должны перекрывать флаги.
+	if fc.Audit != nil && !reflect.ValueOf(*fc.Audit).IsZero() {
+		sf.Audit = fc.Audit
+	}
+
+	if !reflect.ValueOf(fc.TLS.Conf).IsZero() {
+		sf.TLSConf = &fc.TLS.Conf
+	}
+
+	return sf, nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"reflect" // nolint:depguard // used for config diff
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+)
+
+// Reloadable JSON keys of settings applied without restart.
+// Service has no blocklists and rate limits, so they are not reloaded.
+var Reloadable = []string{"log_level", "audit", "timeout", "base_url"}
+
+// ReloadResult describes applied config reload.
+type ReloadResult struct {
+	// Changed reloadable settings as `key: old -> new`.
+	Changed []string
+	// RestartRequired keys of changed settings which are kept until restart.
+	RestartRequired []string
+}
+
+// Live provide config which reloadable settings can be replaced while app is running.
+// Other settings keep startup values.
+type Live struct {
+	*StartupFlags
+	cur   atomic.Pointer[StartupFlags]
+	load  func() (*StartupFlags, error)
+	path  string
+	hooks []func(prev, cur *StartupFlags)
+	mu    sync.Mutex
+}
+
+// NewLive - provide instance of Live. load builds config from all sources again,
+// path is config file watched for changes, may be empty.
+func NewLive(initial *StartupFlags, path string, load func() (*StartupFlags, error)) *Live {
+	l := &Live{StartupFlags: initial, load: load, path: path}
+	l.cur.Store(initial)
+
+	return l
+}
+
+// Path returns watched config file.
+func (l *Live) Path() string {
+	return l.path
+}
+
+// Current returns config with the latest reloaded settings.
+func (l *Live) Current() *StartupFlags {
+	return l.cur.Load()
+}
+
+// GetAddressShortURL returns current base url.
+func (l *Live) GetAddressShortURL() string {
+	return l.Current().GetAddressShortURL()
+}
+
+// GetTimeout returns current request timeout.
+func (l *Live) GetTimeout() time.Duration {
+	return l.Current().GetTimeout()
+}
+
+// GetLogLevel returns current log level.
+func (l *Live) GetLogLevel() string {
+	return l.Current().GetLogLevel()
+}
+
+// GetAudit returns current audit config.
+func (l *Live) GetAudit() *audit.Audit {
+	return l.Current().GetAudit()
+}
+
+// OnReload registers fn called after changed settings are applied.
+func (l *Live) OnReload(fn func(prev, cur *StartupFlags)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, fn)
+}
+
+// Reload loads config and applies changed reloadable settings.
+// Invalid config is rejected as a whole, current config is kept.
+func (l *Live) Reload() (ReloadResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	next, err := l.load()
+	if err != nil {
+		return ReloadResult{}, fmt.Errorf("Reload: %w", err)
+	}
+
+	if err := next.Valid(); err != nil {
+		return ReloadResult{}, fmt.Errorf("Reload: %w", err)
+	}
+
+	prev := l.Current()
+	res := ReloadResult{RestartRequired: restartRequired(prev, next)}
+
+	cur := *prev
+	cur.LogLevel = next.LogLevel
+	cur.Timeout = next.Timeout
+	cur.BaseURL = next.BaseURL
+
+	if prev.LogLevel != cur.LogLevel {
+		res.Changed = append(res.Changed, fmt.Sprintf("log_level: %s -> %s", prev.LogLevel, cur.LogLevel))
+	}
+
+	if prev.Timeout != cur.Timeout {
+		res.Changed = append(res.Changed, fmt.Sprintf("timeout: %v -> %v", prev.Timeout, cur.Timeout))
+	}
+
+	if prev.BaseURL != cur.BaseURL {
+		res.Changed = append(res.Changed, fmt.Sprintf("base_url: %s -> %s", redactURL(prev.BaseURL), redactURL(cur.BaseURL)))
+	}
+
+	// указатель сохраняется, если аудит не изменился: по нему подписчики понимают, что приёмники пересоздавать не нужно.
+	// адреса аудита могут содержать токены, поэтому значения не выводятся.
+	if !reflect.DeepEqual(prev.Audit, next.Audit) {
+		cur.Audit = next.Audit
+		res.Changed = append(res.Changed, "audit: changed")
+	}
+
+	if len(res.Changed) == 0 {
+		return res, nil
+	}
+
+	l.cur.Store(&cur)
+
+	for _, hook := range l.hooks {
+		hook(prev, &cur)
+	}
+
+	return res, nil
+}
+
+// restartRequired returns JSON keys of not reloadable settings which differ.
+func restartRequired(prev, next *StartupFlags) []string {
+	keys := make([]string, 0)
+
+	prevValue, nextValue := reflect.ValueOf(prev).Elem(), reflect.ValueOf(next).Elem()
+	t := prevValue.Type()
+
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("json")
+		if key == "" || slices.Contains(Reloadable, key) {
+			continue
+		}
+
+		if !reflect.DeepEqual(prevValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLive_Reload(t *testing.T) {
+	base := func() *StartupFlags {
+		return &StartupFlags{
+			LogLevel:     "info",
+			Timeout:      600,
+			BaseURL:      "http://localhost:8080",
+			LocalStorage: "local_storage.json",
+			Audit:        &audit.Audit{LogFilePath: "audit.json"},
+		}
+	}
+
+	tests := []struct {
+		next            func() (*StartupFlags, error)
+		want            *StartupFlags
+		name            string
+		wantChanged     []string
+		wantRestart     []string
+		wantErr         error
+		wantHookCalls   int
+		wantAuditReused bool
+	}{
+		{
+			name: "reloadable_changed",
+			next: func() (*StartupFlags, error) {
+				sf := base()
+				sf.LogLevel, sf.Timeout, sf.BaseURL = "debug", 30, "https://short.example"
+				return sf, nil
+			},
+			want: &StartupFlags{
+				LogLevel: "debug", Timeout: 30, BaseURL: "https://short.example",
+				LocalStorage: "local_storage.json", Audit: &audit.Audit{LogFilePath: "audit.json"},
+			},
+			wantChanged:     []string{"log_level: info -> debug", "timeout: 600 -> 30", "base_url: http://localhost:8080 -> https://short.example"},
+			wantRestart:     []string{},
+			wantHookCalls:   1,
+			wantAuditReused: true,
+		},
+		{
+			name: "restart_required_kept",
+			next: func() (*StartupFlags, error) {
+				sf := base()
+				sf.LocalStorage = "other.json"
+				sf.Audit = &audit.Audit{LogURLPath: "http://audit.local/events"}
+				return sf, nil
+			},
+			want: &StartupFlags{
+				LogLevel: "info", Timeout: 600, BaseURL: "http://localhost:8080",
+				LocalStorage: "local_storage.json", Audit: &audit.Audit{LogURLPath: "http://audit.local/events"},
+			},
+			wantChanged:   []string{"audit: changed"},
+			wantRestart:   []string{"file_storage_path"},
+			wantHookCalls: 1,
+		},
+		{
+			name: "invalid_rejected",
+			next: func() (*StartupFlags, error) {
+				sf := base()
+				sf.LogLevel, sf.Timeout = "verbose", 0
+				return sf, nil
+			},
+			want:            base(),
+			wantErr:         ErrInvalidConfig,
+			wantAuditReused: true,
+		},
+		{
+			name:            "unreadable_file",
+			next:            func() (*StartupFlags, error) { return nil, errors.New("unexpected end of JSON input") },
+			want:            base(),
+			wantAuditReused: true,
+		},
+		{
+			name:            "nothing_changed",
+			next:            func() (*StartupFlags, error) { return base(), nil },
+			want:            base(),
+			wantRestart:     []string{},
+			wantAuditReused: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initial := base()
+			live := NewLive(initial, "", tt.next)
+
+			hookCalls := 0
+			live.OnReload(func(prev, cur *StartupFlags) {
+				hookCalls++
+
+				assert.Same(t, initial, prev)
+				assert.Same(t, live.Current(), cur)
+			})
+
+			res, err := live.Reload()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+
+			if tt.name == "unreadable_file" {
+				assert.Error(t, err)
+			}
+
+			if err == nil {
+				assert.Equal(t, tt.wantChanged, res.Changed)
+				assert.Equal(t, tt.wantRestart, res.RestartRequired)
+			}
+
+			assert.Equal(t, tt.want, live.Current())
+			assert.Equal(t, tt.wantHookCalls, hookCalls)
+			assert.Equal(t, tt.wantAuditReused, live.GetAudit() == initial.Audit)
+
+			require.Same(t, initial, live.StartupFlags, "not reloadable settings keep startup values")
+			assert.Equal(t, tt.want.GetTimeout(), live.GetTimeout())
+			assert.Equal(t, tt.want.BaseURL, live.GetAddressShortURL())
+		})
+	}
+}
@@ -5,12 +5,14 @@
 package mocks
 
 import (
+	fs "io/fs"
 	reflect "reflect"
 	time "time"
 
 	config "github.com/Pklerik/urlshortener/internal/config"
 	audit "github.com/Pklerik/urlshortener/internal/config/audit"
 	dbconf "github.com/Pklerik/urlshortener/internal/config/dbconf"
+	tlsconf "github.com/Pklerik/urlshortener/internal/config/tlsconf"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -51,6 +53,34 @@ func (mr *MockStartupFlagsParserMockRecorder) GetAddressShortURL() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressShortURL", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetAddressShortURL))
 }
 
+// GetAdminAddress mocks base method.
+func (m *MockStartupFlagsParser) GetAdminAddress() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminAddress")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetAdminAddress indicates an expected call of GetAdminAddress.
+func (mr *MockStartupFlagsParserMockRecorder) GetAdminAddress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminAddress", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetAdminAddress))
+}
+
+// GetAdminToken mocks base method.
+func (m *MockStartupFlagsParser) GetAdminToken() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminToken")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetAdminToken indicates an expected call of GetAdminToken.
+func (mr *MockStartupFlagsParserMockRecorder) GetAdminToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminToken", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetAdminToken))
+}
+
 // GetAudit mocks base method.
 func (m *MockStartupFlagsParser) GetAudit() *audit.Audit {
 	m.ctrl.T.Helper()
@@ -65,6 +95,34 @@ func (mr *MockStartupFlagsParserMockRecorder) GetAudit() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudit", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetAudit))
 }
 
+// GetClickSampleRate mocks base method.
+func (m *MockStartupFlagsParser) GetClickSampleRate() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickSampleRate")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetClickSampleRate indicates an expected call of GetClickSampleRate.
+func (mr *MockStartupFlagsParserMockRecorder) GetClickSampleRate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickSampleRate", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetClickSampleRate))
+}
+
+// GetDataDir mocks base method.
+func (m *MockStartupFlagsParser) GetDataDir() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataDir")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetDataDir indicates an expected call of GetDataDir.
+func (mr *MockStartupFlagsParserMockRecorder) GetDataDir() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataDir", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetDataDir))
+}
+
 // GetDatabaseConf mocks base method.
 func (m *MockStartupFlagsParser) GetDatabaseConf() (dbconf.DBConfigurer, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +138,62 @@ func (mr *MockStartupFlagsParserMockRecorder) GetDatabaseConf() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDatabaseConf", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetDatabaseConf))
 }
 
+// GetDatabaseReplicaConfs mocks base method.
+func (m *MockStartupFlagsParser) GetDatabaseReplicaConfs() []dbconf.DBConfigurer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDatabaseReplicaConfs")
+	ret0, _ := ret[0].([]dbconf.DBConfigurer)
+	return ret0
+}
+
+// GetDatabaseReplicaConfs indicates an expected call of GetDatabaseReplicaConfs.
+func (mr *MockStartupFlagsParserMockRecorder) GetDatabaseReplicaConfs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDatabaseReplicaConfs", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetDatabaseReplicaConfs))
+}
+
+// GetJobMaxAttempts mocks base method.
+func (m *MockStartupFlagsParser) GetJobMaxAttempts() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobMaxAttempts")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetJobMaxAttempts indicates an expected call of GetJobMaxAttempts.
+func (mr *MockStartupFlagsParserMockRecorder) GetJobMaxAttempts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobMaxAttempts", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetJobMaxAttempts))
+}
+
+// GetJobWorkers mocks base method.
+func (m *MockStartupFlagsParser) GetJobWorkers() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobWorkers")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetJobWorkers indicates an expected call of GetJobWorkers.
+func (mr *MockStartupFlagsParserMockRecorder) GetJobWorkers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobWorkers", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetJobWorkers))
+}
+
+// GetJobsFile mocks base method.
+func (m *MockStartupFlagsParser) GetJobsFile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobsFile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetJobsFile indicates an expected call of GetJobsFile.
+func (mr *MockStartupFlagsParserMockRecorder) GetJobsFile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobsFile", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetJobsFile))
+}
+
 // GetLocalStorage mocks base method.
 func (m *MockStartupFlagsParser) GetLocalStorage() string {
 	m.ctrl.T.Helper()
@@ -108,6 +222,90 @@ func (mr *MockStartupFlagsParserMockRecorder) GetLogLevel() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogLevel", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetLogLevel))
 }
 
+// GetMetricsAddress mocks base method.
+func (m *MockStartupFlagsParser) GetMetricsAddress() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricsAddress")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetMetricsAddress indicates an expected call of GetMetricsAddress.
+func (mr *MockStartupFlagsParserMockRecorder) GetMetricsAddress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsAddress", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetMetricsAddress))
+}
+
+// GetPurgeInterval mocks base method.
+func (m *MockStartupFlagsParser) GetPurgeInterval() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeInterval")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetPurgeInterval indicates an expected call of GetPurgeInterval.
+func (mr *MockStartupFlagsParserMockRecorder) GetPurgeInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeInterval", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetPurgeInterval))
+}
+
+// GetPurgeRetention mocks base method.
+func (m *MockStartupFlagsParser) GetPurgeRetention() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeRetention")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetPurgeRetention indicates an expected call of GetPurgeRetention.
+func (mr *MockStartupFlagsParserMockRecorder) GetPurgeRetention() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeRetention", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetPurgeRetention))
+}
+
+// GetReadYourWritesWindow mocks base method.
+func (m *MockStartupFlagsParser) GetReadYourWritesWindow() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadYourWritesWindow")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetReadYourWritesWindow indicates an expected call of GetReadYourWritesWindow.
+func (mr *MockStartupFlagsParserMockRecorder) GetReadYourWritesWindow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadYourWritesWindow", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetReadYourWritesWindow))
+}
+
+// GetRedirectLogSampleRate mocks base method.
+func (m *MockStartupFlagsParser) GetRedirectLogSampleRate() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedirectLogSampleRate")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetRedirectLogSampleRate indicates an expected call of GetRedirectLogSampleRate.
+func (mr *MockStartupFlagsParserMockRecorder) GetRedirectLogSampleRate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedirectLogSampleRate", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetRedirectLogSampleRate))
+}
+
+// GetRestoreWindow mocks base method.
+func (m *MockStartupFlagsParser) GetRestoreWindow() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRestoreWindow")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetRestoreWindow indicates an expected call of GetRestoreWindow.
+func (mr *MockStartupFlagsParserMockRecorder) GetRestoreWindow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRestoreWindow", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetRestoreWindow))
+}
+
 // GetSecretKey mocks base method.
 func (m *MockStartupFlagsParser) GetSecretKey() string {
 	m.ctrl.T.Helper()
@@ -136,6 +334,62 @@ func (mr *MockStartupFlagsParserMockRecorder) GetServerAddress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServerAddress", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetServerAddress))
 }
 
+// GetShutdownDelay mocks base method.
+func (m *MockStartupFlagsParser) GetShutdownDelay() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShutdownDelay")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetShutdownDelay indicates an expected call of GetShutdownDelay.
+func (mr *MockStartupFlagsParserMockRecorder) GetShutdownDelay() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShutdownDelay", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetShutdownDelay))
+}
+
+// GetShutdownTimeout mocks base method.
+func (m *MockStartupFlagsParser) GetShutdownTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShutdownTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetShutdownTimeout indicates an expected call of GetShutdownTimeout.
+func (mr *MockStartupFlagsParserMockRecorder) GetShutdownTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShutdownTimeout", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetShutdownTimeout))
+}
+
+// GetSkipMigrations mocks base method.
+func (m *MockStartupFlagsParser) GetSkipMigrations() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSkipMigrations")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetSkipMigrations indicates an expected call of GetSkipMigrations.
+func (mr *MockStartupFlagsParserMockRecorder) GetSkipMigrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkipMigrations", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetSkipMigrations))
+}
+
+// GetSocketMode mocks base method.
+func (m *MockStartupFlagsParser) GetSocketMode() fs.FileMode {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocketMode")
+	ret0, _ := ret[0].(fs.FileMode)
+	return ret0
+}
+
+// GetSocketMode indicates an expected call of GetSocketMode.
+func (mr *MockStartupFlagsParserMockRecorder) GetSocketMode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocketMode", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetSocketMode))
+}
+
 // GetTLS mocks base method.
 func (m *MockStartupFlagsParser) GetTLS() bool {
 	m.ctrl.T.Helper()
@@ -150,6 +404,20 @@ func (mr *MockStartupFlagsParserMockRecorder) GetTLS() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTLS", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetTLS))
 }
 
+// GetTLSConf mocks base method.
+func (m *MockStartupFlagsParser) GetTLSConf() *tlsconf.Conf {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTLSConf")
+	ret0, _ := ret[0].(*tlsconf.Conf)
+	return ret0
+}
+
+// GetTLSConf indicates an expected call of GetTLSConf.
+func (mr *MockStartupFlagsParserMockRecorder) GetTLSConf() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTLSConf", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetTLSConf))
+}
+
 // GetTimeout mocks base method.
 func (m *MockStartupFlagsParser) GetTimeout() time.Duration {
 	m.ctrl.T.Helper()
@@ -163,3 +431,45 @@ func (mr *MockStartupFlagsParserMockRecorder) GetTimeout() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeout", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetTimeout))
 }
+
+// GetTraceEndpoint mocks base method.
+func (m *MockStartupFlagsParser) GetTraceEndpoint() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraceEndpoint")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetTraceEndpoint indicates an expected call of GetTraceEndpoint.
+func (mr *MockStartupFlagsParserMockRecorder) GetTraceEndpoint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraceEndpoint", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetTraceEndpoint))
+}
+
+// GetTraceExporter mocks base method.
+func (m *MockStartupFlagsParser) GetTraceExporter() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraceExporter")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetTraceExporter indicates an expected call of GetTraceExporter.
+func (mr *MockStartupFlagsParserMockRecorder) GetTraceExporter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraceExporter", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetTraceExporter))
+}
+
+// GetTraceSampleRatio mocks base method.
+func (m *MockStartupFlagsParser) GetTraceSampleRatio() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraceSampleRatio")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetTraceSampleRatio indicates an expected call of GetTraceSampleRatio.
+func (mr *MockStartupFlagsParserMockRecorder) GetTraceSampleRatio() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraceSampleRatio", reflect.TypeOf((*MockStartupFlagsParser)(nil).GetTraceSampleRatio))
+}
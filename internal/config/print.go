@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect" // nolint:depguard // used to list config values
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/Pklerik/urlshortener/internal/config/dbconf"
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
+)
+
+// Masked replaces secret values on print.
+const Masked = "*****"
+
+// secretKeys keys which values are never printed.
+var secretKeys = []string{"secret_key", "admin_token", "audit.chain_key"}
+
+// Setting single config value prepared for print.
+type Setting struct {
+	Key   string
+	Value string
+}
+
+// Settings returns config values keyed by config file keys, audit and tls values as <section>.<key>.
+// Secrets are masked, passwords are removed from URLs.
+func (sf *StartupFlags) Settings() []Setting {
+	settings := make([]Setting, 0)
+
+	v := reflect.ValueOf(sf).Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("json")
+		if key == "" {
+			continue
+		}
+
+		switch v.Field(i).Interface().(type) {
+		case *audit.Audit, *tlsconf.Conf:
+			settings = append(settings, sectionSettings(key, v.Field(i))...)
+		default:
+			settings = append(settings, Setting{Key: key, Value: maskValue(key, formatValue(v.Field(i).Interface()))})
+		}
+	}
+
+	return settings
+}
+
+// sectionSettings returns values of struct pointed by section.
+func sectionSettings(key string, section reflect.Value) []Setting {
+	if section.IsNil() {
+		return []Setting{{Key: key}}
+	}
+
+	settings := make([]Setting, 0)
+
+	v := section.Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		fieldKey := key + "." + t.Field(i).Tag.Get("json")
+
+		if sinks, ok := v.Field(i).Interface().([]audit.Sink); ok {
+			for n, sink := range sinks {
+				settings = append(settings, Setting{
+					Key:   fmt.Sprintf("%s[%d]", fieldKey, n),
+					Value: fmt.Sprintf("type=%s address=%s format=%s level=%s", sink.Type, redactURL(sink.Address), sink.GetFormat(), sink.GetLevel()),
+				})
+			}
+
+			continue
+		}
+
+		settings = append(settings, Setting{Key: fieldKey, Value: maskValue(fieldKey, formatValue(v.Field(i).Interface()))})
+	}
+
+	return settings
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case *Address:
+		if v == nil {
+			return ""
+		}
+
+		return v.String()
+	case *dbconf.Conf:
+		if v == nil {
+			return ""
+		}
+
+		return v.Redacted()
+	case dbconf.Replicas:
+		dsns := make([]string, 0, len(v))
+		for _, replica := range v {
+			dsns = append(dsns, replica.Redacted())
+		}
+
+		return strings.Join(dsns, ",")
+	case []string:
+		return strings.Join(v, ",")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func maskValue(key, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if slices.Contains(secretKeys, key) {
+		return Masked
+	}
+
+	parts := strings.Split(value, ",")
+	for i, part := range parts {
+		parts[i] = redactURL(part)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// redactURL replaces password in URL, other values are returned as is.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	return u.Redacted()
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect" // nolint:depguard // used to expand references in all config strings
+	"regexp"
+	"strings"
+)
+
+// DefaultSecretKey secret key used when none is set, it is refused in production.
+const DefaultSecretKey = "secret_key"
+
+// Environments of app.
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+var (
+	// ErrDefaultSecret default or empty secret key is used in production.
+	ErrDefaultSecret = errors.New("default secret key is not allowed in production")
+	// ErrUnresolvedRef config reference can't be resolved.
+	ErrUnresolvedRef = errors.New("unresolved config reference")
+	// ErrSecretConflict secret is set both by env and by *_FILE env.
+	ErrSecretConflict = errors.New("secret is set both by value and by file")
+)
+
+// refExp matches ${env:NAME} and ${file:/path} references.
+var refExp = regexp.MustCompile(`\$\{(env|file):([^}]*)\}`)
+
+// ReadSecretFile returns file content without trailing line break, as mounted Docker and Kubernetes secrets.
+func ReadSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("ReadSecretFile: %w", err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// expandRefs replaces references in all strings of v. Relative file paths are resolved from dir.
+func expandRefs(v reflect.Value, dir string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+
+		return expandRefs(v.Elem(), dir)
+	case reflect.Struct:
+		errs := make([]error, 0)
+		for i := range v.NumField() {
+			errs = append(errs, expandRefs(v.Field(i), dir))
+		}
+
+		return errors.Join(errs...)
+	case reflect.Slice:
+		errs := make([]error, 0)
+		for i := range v.Len() {
+			errs = append(errs, expandRefs(v.Index(i), dir))
+		}
+
+		return errors.Join(errs...)
+	case reflect.String:
+		s, err := expandString(v.String(), dir)
+		if err != nil {
+			return err
+		}
+
+		v.SetString(s)
+	}
+
+	return nil
+}
+
+// expandString replaces ${env:NAME} by env value and ${file:/path} by file content.
+func expandString(s, dir string) (string, error) {
+	var errs []error
+
+	expanded := refExp.ReplaceAllStringFunc(s, func(ref string) string {
+		m := refExp.FindStringSubmatch(ref)
+		kind, name := m[1], m[2]
+
+		if kind == "env" {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: env %s is not set", ErrUnresolvedRef, name))
+			}
+
+			return value
+		}
+
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+
+		value, err := ReadSecretFile(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrUnresolvedRef, err))
+		}
+
+		return value
+	})
+
+	return expanded, errors.Join(errs...)
+}
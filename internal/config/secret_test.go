@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile_references(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret_key"), []byte("from-file\n"), 0o600))
+	t.Setenv("SHORTENER_TEST_DB_PASSWORD", `pa"ss`)
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		data       string
+		wantSecret string
+		wantDBPass string
+	}{
+		{
+			name: "env_and_file",
+			data: "secret_key: ${file:secret_key}\n" +
+				"database:\n  dsn: postgresql://user:${env:SHORTENER_TEST_DB_PASSWORD}@localhost:5432/db\n",
+			wantSecret: "from-file",
+			wantDBPass: `pa"ss`,
+		},
+		{name: "absolute_file", data: "secret_key: ${file:" + filepath.Join(dir, "secret_key") + "}\n", wantSecret: "from-file"},
+		{name: "plain", data: "secret_key: plain\n", wantSecret: "plain"},
+		{name: "unset_env", data: "secret_key: ${env:SHORTENER_TEST_UNSET}\n", wantErr: ErrUnresolvedRef},
+		{name: "missing_file", data: "audit:\n  chain_key: ${file:none}\n", wantErr: ErrUnresolvedRef},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0o600))
+
+			sf, err := ReadFile(path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSecret, sf.SecretKey)
+
+			if tt.wantDBPass != "" {
+				require.NotNil(t, sf.DBConf)
+				assert.Equal(t, tt.wantDBPass, sf.DBConf.Password)
+			}
+		})
+	}
+}
+
+func TestStartupFlags_Valid_environment(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		secretKey   string
+		wantErr     error
+	}{
+		{name: "development_default_secret", environment: EnvDevelopment, secretKey: DefaultSecretKey},
+		{name: "production", environment: EnvProduction, secretKey: "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs="},
+		{name: "production_default_secret", environment: EnvProduction, secretKey: DefaultSecretKey, wantErr: ErrDefaultSecret},
+		{name: "production_empty_secret", environment: EnvProduction, wantErr: ErrDefaultSecret},
+		{name: "unknown", environment: "prod", secretKey: "key", wantErr: ErrInvalidConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &StartupFlags{Environment: tt.environment, SecretKey: tt.secretKey, Timeout: 1, BaseURL: "http://localhost:8080"}
+
+			err := sf.Valid()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
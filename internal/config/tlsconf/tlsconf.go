@@ -0,0 +1,157 @@
+// Package tlsconf provide configuration for TLS listener.
+package tlsconf
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DefaultMinVersion lowest TLS version accepted by default.
+const DefaultMinVersion = "1.2"
+
+// DefaultHSTSMaxAge seconds browsers keep using HTTPS only, one year is required for preload.
+const DefaultHSTSMaxAge = 365 * 24 * 60 * 60
+
+// DefaultSelfSignedHosts SANs of generated self-signed certificate.
+var DefaultSelfSignedHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ErrInvalidTLS TLS settings are not supported.
+var ErrInvalidTLS = errors.New("invalid tls config")
+
+// Conf provide configuration for TLS listener.
+// Without CertFile and KeyFile self-signed certificate is generated, it is meant for development only.
+type Conf struct {
+	CertFile string `json:"cert_file" yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `json:"key_file" yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE"`
+	// MinVersion lowest accepted TLS version: 1.2 or 1.3.
+	MinVersion string `json:"min_version" yaml:"min_version" toml:"min_version" env:"TLS_MIN_VERSION"`
+	// CipherSuites names of allowed TLS 1.2 cipher suites, empty allows Go defaults. TLS 1.3 suites are not configurable.
+	CipherSuites []string `json:"cipher_suites" yaml:"cipher_suites" toml:"cipher_suites" env:"TLS_CIPHER_SUITES"`
+	// ClientCAFile CA bundle for client certificates, enables mTLS for internal API.
+	ClientCAFile string `json:"client_ca_file" yaml:"client_ca_file" toml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	// SelfSignedHosts DNS names and IPs of generated self-signed certificate.
+	SelfSignedHosts []string `json:"self_signed_hosts" yaml:"self_signed_hosts" toml:"self_signed_hosts" env:"TLS_SELF_SIGNED_HOSTS"`
+	// HTTPRedirectAddress host:port of plaintext listener redirecting to HTTPS, empty disables it.
+	HTTPRedirectAddress string `json:"http_redirect_address" yaml:"http_redirect_address" toml:"http_redirect_address" env:"HTTP_REDIRECT_ADDRESS"`
+	// HSTSMaxAge seconds of Strict-Transport-Security, 0 gives one year, negative disables header.
+	HSTSMaxAge int `json:"hsts_max_age" yaml:"hsts_max_age" toml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+	// HSTSPreload adds includeSubDomains and preload to Strict-Transport-Security.
+	HSTSPreload bool `json:"hsts_preload" yaml:"hsts_preload" toml:"hsts_preload" env:"HSTS_PRELOAD"`
+}
+
+// SelfSigned returns true if certificate is not configured and must be generated.
+func (c *Conf) SelfSigned() bool {
+	return c == nil || (c.CertFile == "" && c.KeyFile == "")
+}
+
+// MutualTLS returns true if client certificates are verified.
+func (c *Conf) MutualTLS() bool {
+	return c != nil && c.ClientCAFile != ""
+}
+
+// GetMinVersion provide lowest accepted TLS version, TLS 1.2 by default.
+func (c *Conf) GetMinVersion() (uint16, error) {
+	if c == nil || c.MinVersion == "" {
+		return versions[DefaultMinVersion], nil
+	}
+
+	version, ok := versions[c.MinVersion]
+	if !ok {
+		return 0, fmt.Errorf("%w: min_version must be 1.2 or 1.3, got %q", ErrInvalidTLS, c.MinVersion)
+	}
+
+	return version, nil
+}
+
+// GetCipherSuites provide ids of allowed cipher suites, nil allows Go defaults.
+// Insecure cipher suites are rejected.
+func (c *Conf) GetCipherSuites() ([]uint16, error) {
+	if c == nil || len(c.CipherSuites) == 0 {
+		return nil, nil
+	}
+
+	suites := tls.CipherSuites()
+	ids := make([]uint16, 0, len(c.CipherSuites))
+
+	for _, name := range c.CipherSuites {
+		i := slices.IndexFunc(suites, func(s *tls.CipherSuite) bool { return s.Name == name })
+		if i == -1 {
+			return nil, fmt.Errorf("%w: unknown or insecure cipher suite %q", ErrInvalidTLS, name)
+		}
+
+		ids = append(ids, suites[i].ID)
+	}
+
+	return ids, nil
+}
+
+// GetSelfSignedHosts provide SANs of generated certificate.
+func (c *Conf) GetSelfSignedHosts() []string {
+	if c == nil || len(c.SelfSignedHosts) == 0 {
+		return DefaultSelfSignedHosts
+	}
+
+	return c.SelfSignedHosts
+}
+
+// GetHTTPRedirectAddress provide address of plaintext listener redirecting to HTTPS.
+func (c *Conf) GetHTTPRedirectAddress() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HTTPRedirectAddress
+}
+
+// GetHSTSHeader provide value of Strict-Transport-Security header, empty if it is disabled.
+func (c *Conf) GetHSTSHeader() string {
+	maxAge, preload := DefaultHSTSMaxAge, false
+	if c != nil {
+		maxAge, preload = c.HSTSMaxAge, c.HSTSPreload
+	}
+
+	switch {
+	case maxAge < 0:
+		return ""
+	case maxAge == 0:
+		maxAge = DefaultHSTSMaxAge
+	}
+
+	if preload {
+		return fmt.Sprintf("max-age=%d; includeSubDomains; preload", maxAge)
+	}
+
+	return fmt.Sprintf("max-age=%d", maxAge)
+}
+
+// Valid returns ErrInvalidTLS if settings can't be applied.
+func (c *Conf) Valid() error {
+	if c == nil {
+		return nil
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("%w: cert_file and key_file must be set together", ErrInvalidTLS)
+	}
+
+	if _, err := c.GetMinVersion(); err != nil {
+		return err
+	}
+
+	if _, err := c.GetCipherSuites(); err != nil {
+		return err
+	}
+
+	if c.HSTSPreload && (c.HSTSMaxAge < 0 || (c.HSTSMaxAge > 0 && c.HSTSMaxAge < DefaultHSTSMaxAge)) {
+		return fmt.Errorf("%w: hsts_preload requires hsts_max_age of at least %d", ErrInvalidTLS, DefaultHSTSMaxAge)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package tlsconf
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConf_Valid(t *testing.T) {
+	tests := []struct {
+		conf           *Conf
+		name           string
+		wantMinVersion uint16
+		wantSuites     []uint16
+		wantErr        bool
+	}{
+		{name: "nil", wantMinVersion: tls.VersionTLS12},
+		{name: "empty", conf: &Conf{}, wantMinVersion: tls.VersionTLS12},
+		{
+			name:           "configured",
+			conf:           &Conf{CertFile: "cert.pem", KeyFile: "key.pem", MinVersion: "1.2", CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}},
+			wantMinVersion: tls.VersionTLS12,
+			wantSuites:     []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		},
+		{name: "tls13", conf: &Conf{MinVersion: "1.3"}, wantMinVersion: tls.VersionTLS13},
+		{name: "old_version", conf: &Conf{MinVersion: "1.0"}, wantErr: true},
+		{name: "insecure_suite", conf: &Conf{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, wantErr: true},
+		{name: "cert_without_key", conf: &Conf{CertFile: "cert.pem"}, wantErr: true},
+		{name: "preload_default_max_age", conf: &Conf{HSTSPreload: true}, wantMinVersion: tls.VersionTLS12},
+		{name: "preload_short_max_age", conf: &Conf{HSTSPreload: true, HSTSMaxAge: 3600}, wantErr: true},
+		{name: "preload_disabled_hsts", conf: &Conf{HSTSPreload: true, HSTSMaxAge: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conf.Valid()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTLS)
+				return
+			}
+
+			require.NoError(t, err)
+
+			minVersion, _ := tt.conf.GetMinVersion()
+			suites, _ := tt.conf.GetCipherSuites()
+			assert.Equal(t, tt.wantMinVersion, minVersion)
+			assert.Equal(t, tt.wantSuites, suites)
+			assert.Equal(t, tt.conf == nil || tt.conf.CertFile == "", tt.conf.SelfSigned())
+		})
+	}
+}
+
+func TestConf_GetHSTSHeader(t *testing.T) {
+	tests := []struct {
+		conf *Conf
+		name string
+		want string
+	}{
+		{name: "nil", want: "max-age=31536000"},
+		{name: "default", conf: &Conf{}, want: "max-age=31536000"},
+		{name: "max_age", conf: &Conf{HSTSMaxAge: 600}, want: "max-age=600"},
+		{name: "preload", conf: &Conf{HSTSPreload: true}, want: "max-age=31536000; includeSubDomains; preload"},
+		{name: "disabled", conf: &Conf{HSTSMaxAge: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.conf.GetHSTSHeader())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrInvalidConfig returned when config values can't be applied.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Valid returns ErrInvalidConfig with all problems found in sf.
+func (sf *StartupFlags) Valid() error {
+	errs := make([]error, 0)
+
+	if sf.LogLevel != "" {
+		if _, err := zapcore.ParseLevel(sf.LogLevel); err != nil {
+			errs = append(errs, fmt.Errorf("log_level: %w", err))
+		}
+	}
+
+	if sf.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("timeout: must be positive, got %v", sf.Timeout))
+	}
+
+	if u, err := url.Parse(sf.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("base_url: %q is not absolute http url", sf.BaseURL))
+	}
+
+	for _, r := range []struct {
+		key   string
+		ratio float64
+	}{
+		{"webhook_click_sample_rate", sf.ClickSample},
+		{"trace_sample_ratio", sf.TraceSample},
+		{"redirect_log_sample_rate", sf.RedirectLog},
+	} {
+		if r.ratio < 0 || r.ratio > 1 {
+			errs = append(errs, fmt.Errorf("%s: must be from 0 to 1, got %v", r.key, r.ratio))
+		}
+	}
+
+	switch sf.Environment {
+	case "", EnvDevelopment:
+	case EnvProduction:
+		if sf.SecretKey == "" || sf.SecretKey == DefaultSecretKey {
+			errs = append(errs, fmt.Errorf("secret_key: %w", ErrDefaultSecret))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("environment: must be %s or %s, got %q", EnvDevelopment, EnvProduction, sf.Environment))
+	}
+
+	if sf.Audit != nil {
+		for _, sink := range sf.Audit.GetSinks() {
+			if err := sink.Valid(); err != nil {
+				errs = append(errs, fmt.Errorf("audit: %w", err))
+			}
+		}
+	}
+
+	if _, err := parseSocketMode(sf.SocketMode); err != nil {
+		errs = append(errs, fmt.Errorf("socket_mode: %w", err))
+	}
+
+	if err := sf.TLSConf.Valid(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
@@ -17,17 +17,21 @@ import (
 	"time"
 )
 
-var (
-	cert = &x509.Certificate{
-		// указываем уникальный номер сертификата
-		SerialNumber: big.NewInt(1658),
+// certTemplate returns template of self-signed certificate for hosts: DNS names and IPs.
+func certTemplate(hosts []string) (*x509.Certificate, error) {
+	// указываем уникальный номер сертификата
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("certTemplate: %w", err)
+	}
+
+	cert := &x509.Certificate{
+		SerialNumber: serial,
 		// заполняем базовую информацию о владельце сертификата
 		Subject: pkix.Name{
 			Organization: []string{"Pavel.Budkov"},
 			Country:      []string{"RU"},
 		},
-		// разрешаем использование сертификата для 127.0.0.1 и ::1
-		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
 		// сертификат верен, начиная со времени создания
 		NotBefore: time.Now(),
 		// время жизни сертификата — 10 лет
@@ -38,7 +42,18 @@ var (
 		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		KeyUsage:    x509.KeyUsageDigitalSignature,
 	}
-)
+
+	// разрешаем использование сертификата для заданных адресов и имён
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			cert.IPAddresses = append(cert.IPAddresses, ip)
+		} else {
+			cert.DNSNames = append(cert.DNSNames, host)
+		}
+	}
+
+	return cert, nil
+}
 
 // KeyPair holds the file paths for the certificate and private key.
 type KeyPair struct {
@@ -47,14 +62,14 @@ type KeyPair struct {
 }
 
 // GetSertKey checks if certificate and private key files exist in the specified path.
-// If they do not exist, it generates new ones.
+// If they do not exist or certificate doesn't cover all hosts, it generates new self-signed ones.
 // It returns the paths to the certificate and private key files.
-func GetSertKey(path string) (KeyPair, error) {
+func GetSertKey(path string, hosts []string) (KeyPair, error) {
 	certPath := filepath.Join(path, "cert.pem")
 	privateKeyPath := filepath.Join(path, "private.pem")
 
-	if !fileExists(certPath) || !fileExists(privateKeyPath) {
-		return genSertKey(path)
+	if !fileExists(certPath) || !fileExists(privateKeyPath) || !coversHosts(certPath, hosts) {
+		return genSertKey(path, hosts)
 	}
 
 	return KeyPair{
@@ -79,8 +94,38 @@ func fileExists(path string) bool {
 	return false
 }
 
-func genSertKey(path string) (KeyPair, error) {
+// coversHosts returns true if certificate in certPath is valid for all hosts.
+func coversHosts(certPath string, hosts []string) bool {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return false
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	for _, host := range hosts {
+		if cert.VerifyHostname(host) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func genSertKey(path string, hosts []string) (KeyPair, error) {
 	// создаём шаблон сертификата
+	cert, err := certTemplate(hosts)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("genSertKey: %w", err)
+	}
 
 	// создаём новый приватный RSA-ключ длиной 4096 бит
 	// обратите внимание, что для генерации ключа и сертификата
@@ -0,0 +1,127 @@
+package criptography
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
+)
+
+// ErrNoCACerts CA bundle has no certificates.
+var ErrNoCACerts = errors.New("no certificates in CA bundle")
+
+// fileStamp detects file change without reading it.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// CertReloader serves certificate from files and loads it again when files change.
+type CertReloader struct {
+	cert     atomic.Pointer[tls.Certificate]
+	certFile string
+	keyFile  string
+	stamps   [2]fileStamp
+	mu       sync.Mutex
+}
+
+// NewCertReloader - provide instance of CertReloader with loaded certificate.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	cr := &CertReloader{certFile: certFile, keyFile: keyFile}
+
+	if _, err := cr.Reload(); err != nil {
+		return nil, fmt.Errorf("NewCertReloader: %w", err)
+	}
+
+	return cr, nil
+}
+
+// GetCertificate returns current certificate, it is used as tls.Config.GetCertificate.
+func (cr *CertReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return cr.cert.Load(), nil
+}
+
+// Reload loads certificate if files changed since last load and returns true if it was replaced.
+// Broken or mismatched files keep current certificate, they are loaded again on next call.
+func (cr *CertReloader) Reload() (bool, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	stamps, err := statFiles(cr.certFile, cr.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("(cr *CertReloader) Reload: %w", err)
+	}
+
+	if cr.cert.Load() != nil && stamps == cr.stamps {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("(cr *CertReloader) Reload: %w", err)
+	}
+
+	cr.cert.Store(&cert)
+	cr.stamps = stamps
+
+	return true, nil
+}
+
+func statFiles(certFile, keyFile string) ([2]fileStamp, error) {
+	var stamps [2]fileStamp
+
+	for i, path := range []string{certFile, keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return stamps, fmt.Errorf("statFiles: %w", err)
+		}
+
+		stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return stamps, nil
+}
+
+// NewServerTLSConfig provide TLS config of server listener.
+// With client CA bundle client certificates are verified if given, handlers of internal API must require them.
+func NewServerTLSConfig(conf *tlsconf.Conf, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (*tls.Config, error) {
+	minVersion, err := conf.GetMinVersion()
+	if err != nil {
+		return nil, fmt.Errorf("NewServerTLSConfig: %w", err)
+	}
+
+	cipherSuites, err := conf.GetCipherSuites()
+	if err != nil {
+		return nil, fmt.Errorf("NewServerTLSConfig: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		CipherSuites:   cipherSuites,
+		GetCertificate: getCertificate,
+	}
+
+	if !conf.MutualTLS() {
+		return tlsConfig, nil
+	}
+
+	bundle, err := os.ReadFile(conf.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("NewServerTLSConfig: %w", err)
+	}
+
+	tlsConfig.ClientCAs = x509.NewCertPool()
+	if !tlsConfig.ClientCAs.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("NewServerTLSConfig %s: %w", conf.ClientCAFile, ErrNoCACerts)
+	}
+
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,177 @@
+package criptography
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/config/tlsconf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// issue writes certificate signed by parent, self-signed if parent is nil, and its key to dir.
+func issue(t *testing.T, dir, name string, parent *tls.Certificate, isCA bool) (certFile, keyFile string, cert *tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+
+	signerCert, signerKey := tmpl, any(key)
+	if parent != nil {
+		signerCert, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, name+".pem"), filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	return certFile, keyFile, &pair
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := issue(t, dir, "server", nil, false)
+
+	cr, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	first, _ := cr.GetCertificate(nil)
+
+	reloaded, err := cr.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "unchanged files are not loaded again")
+
+	// ротация: новый сертификат с новым ключом под теми же путями.
+	nextCert, nextKey, _ := issue(t, t.TempDir(), "server", nil, false)
+	rotate := func(src, dst string) {
+		data, err := os.ReadFile(src)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(dst, data, 0o600))
+		require.NoError(t, os.Chtimes(dst, time.Now(), time.Now().Add(time.Second)))
+	}
+
+	rotate(nextCert, certFile)
+
+	reloaded, err = cr.Reload()
+	require.Error(t, err, "certificate doesn't match old key")
+	assert.False(t, reloaded)
+
+	current, _ := cr.GetCertificate(nil)
+	assert.Same(t, first, current, "broken pair keeps current certificate")
+
+	rotate(nextKey, keyFile)
+
+	reloaded, err = cr.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	current, _ = cr.GetCertificate(nil)
+	assert.NotSame(t, first, current)
+}
+
+func TestNewServerTLSConfig_mutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _, ca := issue(t, dir, "ca", nil, true)
+	serverCert, serverKey, _ := issue(t, dir, "server", ca, false)
+	_, _, client := issue(t, dir, "client", ca, false)
+	_, _, stranger := issue(t, dir, "stranger", nil, false)
+
+	cr, err := NewCertReloader(serverCert, serverKey)
+	require.NoError(t, err)
+
+	tlsConfig, err := NewServerTLSConfig(&tlsconf.Conf{MinVersion: "1.2", ClientCAFile: caFile}, cr.GetCertificate)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.VerifiedChains) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	srv.TLS = tlsConfig
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+
+	tests := []struct {
+		cert       *tls.Certificate
+		name       string
+		wantStatus int
+	}{
+		{name: "verified_client", cert: client, wantStatus: http.StatusOK},
+		{name: "no_client_cert", wantStatus: http.StatusForbidden},
+		// клиент не отправляет сертификат, не подписанный принимаемыми сервером CA.
+		{name: "unknown_client_ca", cert: stranger, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+			if tt.cert != nil {
+				clientConfig.Certificates = []tls.Certificate{*tt.cert}
+			}
+
+			httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+
+			resp, err := httpClient.Get(srv.URL)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+
+	_, err = NewServerTLSConfig(&tlsconf.Conf{ClientCAFile: serverKey}, cr.GetCertificate)
+	assert.ErrorIs(t, err, ErrNoCACerts)
+}
+
+func TestGetSertKey_hosts(t *testing.T) {
+	dir := t.TempDir()
+
+	keys, err := GetSertKey(dir, []string{"localhost", "127.0.0.1"})
+	require.NoError(t, err)
+
+	pair, err := tls.LoadX509KeyPair(keys.CertPEMFile, keys.PrivateKeyPEMFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost"}, pair.Leaf.DNSNames)
+	assert.Len(t, pair.Leaf.IPAddresses, 1)
+
+	assert.True(t, coversHosts(keys.CertPEMFile, []string{"127.0.0.1"}), "existing certificate is reused")
+	assert.False(t, coversHosts(keys.CertPEMFile, []string{"short.example"}), "new host requires new certificate")
+}
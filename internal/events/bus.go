@@ -0,0 +1,47 @@
+// Package events provide in-process bus for link lifecycle events.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// Publisher provide contract for events producers.
+type Publisher interface {
+	Publish(ctx context.Context, ev model.Event)
+}
+
+// Subscriber handles published event. Subscribers are called synchronously
+// by publisher, so long work must be moved to background jobs.
+type Subscriber func(ctx context.Context, ev model.Event)
+
+// Bus delivers every published event to all subscribers.
+type Bus struct {
+	subscribers []Subscriber
+	mu          sync.RWMutex
+}
+
+// NewBus - provide instance of Bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds subscriber for all events.
+func (b *Bus) Subscribe(s Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, s)
+}
+
+// Publish calls all subscribers with ev.
+func (b *Bus) Publish(ctx context.Context, ev model.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.subscribers {
+		s(ctx, ev)
+	}
+}
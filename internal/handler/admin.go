@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/service"
+)
+
+// adminUserID user id written to audit log for admin actions.
+const adminUserID = model.UserID("admin")
+
+// AdminHandler - provide contract for administrative request handling.
+type AdminHandler interface {
+	AdminAuth(next http.Handler) http.Handler
+	PurgeLinks(w http.ResponseWriter, r *http.Request)
+	GetAuditEvents(w http.ResponseWriter, r *http.Request)
+}
+
+// AdminHandle - wrapper for administrative service handling.
+type AdminHandle struct {
+	service service.AdminServicer
+	token   string
+}
+
+// NewAdminHandler returns instance of AdminHandler.
+// Empty token disables all admin endpoints.
+func NewAdminHandler(adminService service.AdminServicer, token string) AdminHandler {
+	return &AdminHandle{service: adminService, token: token}
+}
+
+// AdminAuth provide middleware checking `Authorization: Bearer <admin token>` header.
+func (ah *AdminHandle) AdminAuth(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if ah.token == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(ah.token)) != 1 {
+			logger.SugarFromContext(r.Context()).Warnf(`Rejected admin request: %s %s`, r.Method, r.URL.Path)
+			http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+
+			return
+		}
+
+		auditUser(r, adminUserID)
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// PurgeLinks permanently removes links immediately, e.g. for legal takedown requests.
+func (ah *AdminHandle) PurgeLinks(w http.ResponseWriter, r *http.Request) {
+	var req model.ShortUrls
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	lds, err := ah.service.PurgeLinks(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	auditLinks(r, lds...)
+
+	resp := make(model.ShortUrls, 0, len(lds))
+	for _, ld := range lds {
+		resp = append(resp, ld.ShortURL)
+	}
+
+	writeJSON(w, http.StatusOK, &resp)
+	logger.SugarFromContext(r.Context()).Infof(`purged <%d> of <%d> urls by admin request`, len(lds), len(req))
+}
+
+// GetAuditEvents searches stored audit events.
+// Query parameters: user_id, action, short_url, from and to in RFC 3339, limit and cursor from previous page next_cursor.
+func (ah *AdminHandle) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		logger.SugarFromContext(r.Context()).Infof(`invalid audit search query: %v`, err)
+		http.Error(w, `Invalid query`, http.StatusBadRequest)
+
+		return
+	}
+
+	events, next, err := ah.service.SearchAuditEvents(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &model.ResAuditEvents{Events: events, NextCursor: next})
+}
+
+func parseAuditFilter(query url.Values) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		UserID:   model.UserID(query.Get("user_id")),
+		Action:   query.Get("action"),
+		ShortURL: query.Get("short_url"),
+	}
+
+	for _, p := range []struct {
+		value *time.Time
+		name  string
+	}{{&filter.From, "from"}, {&filter.To, "to"}} {
+		if raw := query.Get(p.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return filter, fmt.Errorf("parseAuditFilter: %s: %w", p.name, err)
+			}
+
+			*p.value = t
+		}
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, fmt.Errorf("parseAuditFilter: limit: %w", err)
+		}
+
+		filter.Limit = limit
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("parseAuditFilter: cursor: %w", err)
+		}
+
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
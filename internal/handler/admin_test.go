@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/Pklerik/urlshortener/internal/service/links"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandle_AdminAuth(t *testing.T) {
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), baseConfig.GetSecretKey())
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		code          int
+	}{
+		{name: "disabled", token: "", authorization: "Bearer ", code: http.StatusNotFound},
+		{name: "no header", token: "admin-token", code: http.StatusUnauthorized},
+		{name: "wrong token", token: "admin-token", authorization: "Bearer wrong", code: http.StatusUnauthorized},
+		{name: "valid token", token: "admin-token", authorization: "Bearer admin-token", code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ah := NewAdminHandler(ls, tt.token)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/urls/purge", bytes.NewBufferString(`["398f0ca4"]`))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			ah.AdminAuth(http.HandlerFunc(ah.PurgeLinks)).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestAdminHandle_GetAuditEvents(t *testing.T) {
+	auditRepo := inmemory.NewInMemoryAuditRepository()
+	require.NoError(t, auditRepo.InsertAuditEvents(t.Context(), []model.AuditEvent{
+		{Action: model.AuditActionShorten, UserID: "u1", TS: 1760000000},
+		{Action: model.AuditActionFollow, UserID: "u1", TS: 1760000100},
+		{Action: model.AuditActionFollow, UserID: "u2", TS: 1760000200},
+	}))
+
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), baseConfig.GetSecretKey(), links.WithAuditRepository(auditRepo))
+	ah := NewAdminHandler(ls, "admin-token")
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+		want  model.ResAuditEvents
+	}{
+		{name: "bad time", query: "?from=yesterday", code: http.StatusBadRequest},
+		{name: "bad range", query: "?from=2025-10-10T00:00:00Z&to=2025-10-09T00:00:00Z", code: http.StatusBadRequest},
+		{
+			name: "first page", query: "?user_id=u1&limit=1", code: http.StatusOK,
+			want: model.ResAuditEvents{Events: []model.AuditEvent{{ID: 2, Action: model.AuditActionFollow, UserID: "u1", TS: 1760000100}}, NextCursor: 2},
+		},
+		{
+			name: "next page", query: "?user_id=u1&limit=1&cursor=2", code: http.StatusOK,
+			want: model.ResAuditEvents{Events: []model.AuditEvent{{ID: 1, Action: model.AuditActionShorten, UserID: "u1", TS: 1760000000}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ah.GetAuditEvents(w, httptest.NewRequest(http.MethodGet, "/api/admin/audit"+tt.query, nil))
+
+			require.Equal(t, tt.code, w.Code)
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var got model.ResAuditEvents
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
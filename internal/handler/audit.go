@@ -1,113 +1,225 @@
 package handler
 
 import (
-	"bytes"
-	"errors"
-	"fmt"
-	"io"
+	"context"
+	"net"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/Pklerik/urlshortener/internal/config"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Auditer provide audit logging for requests.
 type Auditer interface {
-	AuditMiddleware(next http.Handler) http.Handler
+	Audit(action string) func(next http.Handler) http.Handler
+}
+
+// AuditStore saves audit events for search.
+type AuditStore interface {
+	Record(ev model.AuditEvent)
 }
 
 // Auditor provide audit logging for requests.
 type Auditor struct {
-	Args config.StartupFlagsParser
-	ah   IAuthentication
+	Args  config.StartupFlagsParser
+	ah    IAuthentication
+	store AuditStore
 }
 
-// NewAuditor provide audit logging for requests.
-func NewAuditor(args config.StartupFlagsParser, ah IAuthentication) *Auditor {
+// NewAuditor provide audit logging for requests. Events are also saved to store if it isn't nil.
+func NewAuditor(args config.StartupFlagsParser, ah IAuthentication, store AuditStore) *Auditor {
 	return &Auditor{
-		Args: args,
-		ah:   ah,
+		Args:  args,
+		ah:    ah,
+		store: store,
 	}
 }
 
-// AuditMiddleware provide audit logging for requests.
-func (a *Auditor) AuditMiddleware(next http.Handler) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) {
-		if a.Args.GetAudit() == nil || (a.Args.GetAudit().GetLogFilePath() == "" && a.Args.GetAudit().LogURLPath == "") {
-			next.ServeHTTP(w, r)
-			return
-		}
+type auditCtxKey struct{}
 
-		action := getAction(r.URL.Path)
+// auditRecord collects audit details from handler while request is served.
+type auditRecord struct {
+	userID model.UserID
+	links  []model.LinkData
+	mu     sync.Mutex
+}
 
-		userID, err := a.ah.GetUserIDFromCookie(r)
-		if err != nil {
-			userID = model.UserID("unauthorized")
-		}
+// auditLinks adds links affected by request to audit record. Does nothing outside of Audit middleware.
+func auditLinks(r *http.Request, lds ...model.LinkData) {
+	if rec, ok := r.Context().Value(auditCtxKey{}).(*auditRecord); ok {
+		rec.mu.Lock()
+		rec.links = append(rec.links, lds...)
+		rec.mu.Unlock()
+	}
+}
 
-		req := model.Request{}
+// auditShortURLs adds short codes affected by request to audit record.
+func auditShortURLs(r *http.Request, shortURLs model.ShortUrls) {
+	lds := make([]model.LinkData, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		lds = append(lds, model.LinkData{ShortURL: shortURL})
+	}
 
-		err = reedBodyWithTeeReader(r, &req)
-		if err != nil {
-			logger.Log.Error("cannot read body", zap.Error(err))
-			next.ServeHTTP(w, r)
+	auditLinks(r, lds...)
+}
 
-			return
-		}
+// auditUser overrides user written to audit record.
+func auditUser(r *http.Request, userID model.UserID) {
+	if rec, ok := r.Context().Value(auditCtxKey{}).(*auditRecord); ok {
+		rec.mu.Lock()
+		rec.userID = userID
+		rec.mu.Unlock()
+	}
+}
+
+// Audit provide middleware writing action audit events after response status is known.
+func (a *Auditor) Audit(action string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if !a.enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		if req.URL == "" {
-			logger.Log.Error("request is empty")
+			rec := &auditRecord{}
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), auditCtxKey{}, rec)))
+
+			a.writeRequestEvents(r, action, ww.Status(), rec)
 		}
 
-		extendedLogger := logger.AuditLogger(a.Args.GetAudit())
-		if extendedLogger == nil {
-			next.ServeHTTP(w, r)
-			return
+		return http.HandlerFunc(fn)
+	}
+}
+
+func (a *Auditor) writeRequestEvents(r *http.Request, action string, status int, rec *auditRecord) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	userID := rec.userID
+	if userID == "" {
+		var err error
+		if userID, err = a.ah.GetUserIDFromCookie(r); err != nil {
+			userID = model.UserID("unauthorized")
 		}
+	}
 
-		extendedLogger.Log(logger.Log.Level(), "", zap.Int64("ts", time.Now().Unix()),
-			zap.String("action", action),
-			zap.String("user_id", string(userID)),
-			zap.String("url", req.URL),
-		)
+	base := model.AuditEvent{
+		Version:   model.AuditSchemaVersion,
+		TS:        time.Now().Unix(),
+		Action:    action,
+		Status:    status,
+		RequestID: chimiddleware.GetReqID(r.Context()),
+		ClientIP:  clientIP(r),
+		UserID:    userID,
+		ShortURL:  chi.URLParam(r, "shortURL"),
+	}
 
-		next.ServeHTTP(w, r)
+	if len(rec.links) == 0 {
+		a.write(base)
+		return
 	}
 
-	return http.HandlerFunc(fn)
+	for _, ld := range rec.links {
+		ev := base
+		ev.URL = ld.LongURL
+		ev.ShortURL = ld.ShortURL
+		a.write(ev)
+	}
 }
 
-func getAction(urlPath string) string {
-	if strings.HasSuffix(urlPath, "/") {
-		return "shorten"
+// RecordLinks writes audit entry with action for every link changed outside of HTTP request.
+func (a *Auditor) RecordLinks(action string, userID model.UserID, lds []model.LinkData) {
+	if !a.enabled() {
+		return
 	}
 
-	if strings.HasSuffix(urlPath, "/api/shorten") {
-		return "shorten"
+	for _, ld := range lds {
+		a.write(model.AuditEvent{
+			Version:  model.AuditSchemaVersion,
+			TS:       time.Now().Unix(),
+			Action:   action,
+			UserID:   userID,
+			URL:      ld.LongURL,
+			ShortURL: ld.ShortURL,
+		})
 	}
-
-	return "follow"
 }
 
-func reedBodyWithTeeReader(r *http.Request, req *model.Request) error {
-	var buf bytes.Buffer
+func (a *Auditor) write(ev model.AuditEvent) {
+	if a.store != nil {
+		a.store.Record(ev)
+	}
 
-	tee := io.TeeReader(r.Body, &buf)
+	if !a.Args.GetAudit().Enabled() {
+		return
+	}
+
+	extendedLogger := logger.AuditLogger(a.Args.GetAudit(), a.Args.GetDataDir())
+	if extendedLogger == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.Int("v", ev.Version),
+		zap.Int64("ts", ev.TS),
+		zap.String("action", ev.Action),
+		zap.String("user_id", string(ev.UserID)),
+	}
 
-	body, err := io.ReadAll(tee)
-	if err != nil && !errors.Is(err, io.EOF) {
-		logger.Log.Debug("cannot read body", zap.Error(err))
-		return fmt.Errorf("reedBodyWithTeeReader: %w", err)
+	if ev.Status != 0 {
+		fields = append(fields, zap.Int("status", ev.Status))
 	}
 
-	if err := readReq(r, body, req); err != nil {
-		logger.Log.Error("cannot read request", zap.Error(err))
-		return fmt.Errorf("reedBodyWithTeeReader: %w", err)
+	for _, f := range []struct{ key, value string }{
+		{"request_id", ev.RequestID},
+		{"client_ip", ev.ClientIP},
+		{"url", ev.URL},
+		{"short_url", ev.ShortURL},
+	} {
+		if f.value != "" {
+			fields = append(fields, zap.String(f.key, f.value))
+		}
+	}
+
+	extendedLogger.Log(auditLevel(ev.Status), "", fields...)
+}
+
+// enabled reports whether events are needed: by store for search or by configured audit sinks.
+func (a *Auditor) enabled() bool {
+	return a.store != nil || a.Args.GetAudit().Enabled()
+}
+
+// auditLevel provide event level filtered by sinks: warn for client and error for server failures.
+func auditLevel(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// clientIP provide client address without port. RealIP middleware already applied X-Forwarded-For.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
 
-	return nil
+	return host
 }
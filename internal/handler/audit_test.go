@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/auditstore"
+	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/Pklerik/urlshortener/internal/service/links"
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditor_Audit(t *testing.T) {
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+	args := &config.StartupFlags{
+		BaseURL:   "http://localhost:8080",
+		SecretKey: baseConfig.GetSecretKey(),
+		Audit:     &audit.Audit{LogFilePath: auditFile},
+	}
+
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), args.GetSecretKey())
+	ah := NewAuthenticationHandler(ls)
+	lh := NewLinkHandler(ls, ah, nil, args)
+	auditRepo := inmemory.NewInMemoryAuditRepository()
+	store := auditstore.NewRecorder(auditRepo)
+	auditor := NewAuditor(args, ah, store)
+
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID, chimiddleware.RealIP, ah.AuthUser)
+	r.With(auditor.Audit(model.AuditActionShortenBatch)).Post("/api/shorten/batch", lh.PostBatchJSON)
+	r.With(auditor.Audit(model.AuditActionFollow)).Get("/{shortURL}", lh.Get)
+
+	requests := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{method: http.MethodPost, target: "/api/shorten/batch", body: `[{"correlation_id":"1","original_url":"http://ya.ru"},{"correlation_id":"2","original_url":"http://go.dev"}]`},
+		{method: http.MethodGet, target: "/398f0ca4"},
+		{method: http.MethodGet, target: "/missing0"},
+	}
+	for _, rq := range requests {
+		req := httptest.NewRequest(rq.method, rq.target, bytes.NewBufferString(rq.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.NoError(t, logger.SyncAudit())
+
+	data, err := os.ReadFile(auditFile)
+	require.NoError(t, err)
+
+	var events []model.AuditEvent
+
+	for line := range bytes.SplitSeq(bytes.TrimSpace(data), []byte("\n")) {
+		var ev model.AuditEvent
+		require.NoError(t, json.Unmarshal(line, &ev))
+		events = append(events, ev)
+	}
+
+	require.Len(t, events, 4, "batch produces event per url")
+
+	for _, ev := range events {
+		assert.Equal(t, model.AuditSchemaVersion, ev.Version)
+		assert.Equal(t, "203.0.113.7", ev.ClientIP)
+		assert.NotEmpty(t, ev.RequestID)
+	}
+
+	assert.Equal(t, model.AuditActionShortenBatch, events[0].Action)
+	assert.Equal(t, http.StatusCreated, events[0].Status)
+	assert.Equal(t, "http://go.dev", events[1].URL)
+	assert.Equal(t, events[0].RequestID, events[1].RequestID)
+
+	assert.Equal(t, model.AuditActionFollow, events[2].Action)
+	assert.Equal(t, http.StatusTemporaryRedirect, events[2].Status)
+	assert.Equal(t, "http://ya.ru", events[2].URL)
+
+	assert.Equal(t, http.StatusBadRequest, events[3].Status, "failed request is recorded with its status")
+	assert.Equal(t, "missing0", events[3].ShortURL)
+
+	store.Flush()
+
+	stored, err := auditRepo.SelectAuditEvents(t.Context(), model.AuditFilter{Action: model.AuditActionShortenBatch})
+	require.NoError(t, err)
+	require.Len(t, stored, 2, "events are saved to audit store")
+	assert.Equal(t, "http://go.dev", stored[0].URL, "newest event first")
+	assert.Equal(t, int64(2), stored[0].ID)
+}
+
+func TestAuditor_StoreWithoutSinks(t *testing.T) {
+	args := &config.StartupFlags{BaseURL: "http://localhost:8080", SecretKey: baseConfig.GetSecretKey(), Audit: &audit.Audit{}}
+
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), args.GetSecretKey())
+	ah := NewAuthenticationHandler(ls)
+	auditRepo := inmemory.NewInMemoryAuditRepository()
+	store := auditstore.NewRecorder(auditRepo)
+	auditor := NewAuditor(args, ah, store)
+
+	r := chi.NewRouter()
+	r.With(auditor.Audit(model.AuditActionFollow)).Get("/{shortURL}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing0", nil))
+
+	auditor.RecordLinks(model.AuditActionDeleteURLs, "user", []model.LinkData{{ShortURL: "398f0ca4"}})
+	store.Flush()
+
+	stored, err := auditRepo.SelectAuditEvents(t.Context(), model.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, stored, 2, "events are saved to store without audit sinks")
+	assert.Equal(t, "398f0ca4", stored[0].ShortURL)
+	assert.Equal(t, "missing0", stored[1].ShortURL)
+}
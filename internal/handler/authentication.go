@@ -9,6 +9,7 @@ import (
 	"github.com/Pklerik/urlshortener/internal/service"
 	"github.com/Pklerik/urlshortener/pkg/jwtgenerator"
 	"github.com/samborkent/uuidv7"
+	"go.uber.org/zap"
 )
 
 // IAuthentication provide user authentication middleware.
@@ -57,7 +58,7 @@ func (ah *AuthHandler) AuthUser(next http.Handler) http.Handler {
 			)
 			if err != nil {
 				http.Error(w, "Unable to generete JWT", http.StatusInternalServerError)
-				logger.Sugar.Errorf("Unable to generete JWT: %w", err)
+				logger.SugarFromContext(r.Context()).Errorf("Unable to generete JWT: %v", err)
 
 				return
 			}
@@ -72,6 +73,10 @@ func (ah *AuthHandler) AuthUser(next http.Handler) http.Handler {
 
 		http.SetCookie(w, cookieAuth)
 
+		if userID, err := ah.GetUserIDFromCookie(r); err == nil {
+			logger.AddFields(r.Context(), zap.String("user_id", string(userID)))
+		}
+
 		// передаём управление хендлеру
 		next.ServeHTTP(w, r)
 	}
@@ -83,13 +88,13 @@ func (ah *AuthHandler) AuthUser(next http.Handler) http.Handler {
 func (ah *AuthHandler) GetUserIDFromCookie(r *http.Request) (model.UserID, error) {
 	authCookie, err := r.Cookie("auth_user")
 	if errors.Is(err, http.ErrNoCookie) {
-		logger.Sugar.Infof(`Unable to find auth_user cookie: %d`, http.StatusUnauthorized)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to find auth_user cookie: %d`, http.StatusUnauthorized)
 
 		return model.UserID(uuidv7.New().String()), ErrUnauthorizedUser
 	}
 
 	if err != nil {
-		logger.Sugar.Infof(`Unable to get cookie: status: %d`, http.StatusInternalServerError)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to get cookie: status: %d`, http.StatusInternalServerError)
 	}
 
 	secretKey, ok := ah.service.GetSecret("SECRET_KEY")
@@ -99,7 +104,7 @@ func (ah *AuthHandler) GetUserIDFromCookie(r *http.Request) (model.UserID, error
 
 	userID, err := jwtgenerator.GetUserID(secretKey.(string), authCookie.Value)
 	if err != nil {
-		logger.Sugar.Infof(`Unable to get UserID: status: %d`, http.StatusUnauthorized)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to get UserID: status: %d`, http.StatusUnauthorized)
 
 		return model.UserID(userID.String()), ErrUnauthorizedUser
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/Pklerik/urlshortener/internal/config"
 	"github.com/Pklerik/urlshortener/internal/handler/validators"
 	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/metrics"
 
 	"github.com/Pklerik/urlshortener/internal/model"
 	"github.com/Pklerik/urlshortener/internal/repository"
@@ -28,48 +29,64 @@ type LinkHandler interface {
 	PostBatchJSON(w http.ResponseWriter, r *http.Request)
 	GetUserLinks(w http.ResponseWriter, r *http.Request)
 	DeleteUserLinks(w http.ResponseWriter, r *http.Request)
+	PatchUserLink(w http.ResponseWriter, r *http.Request)
+	GetLinkRevisions(w http.ResponseWriter, r *http.Request)
+	RestoreUserLinks(w http.ResponseWriter, r *http.Request)
+	GetJob(w http.ResponseWriter, r *http.Request)
+}
+
+// JobQueuer - provide contract for durable background jobs.
+type JobQueuer interface {
+	Enqueue(ctx context.Context, jobType string, userID model.UserID, payload any) (model.Job, error)
+	Get(ctx context.Context, id model.UUIDv7) (model.Job, error)
 }
 
 // LinkHandle - wrapper for service handling.
 type LinkHandle struct {
 	service service.LinkServicer
 	ah      IAuthentication
+	jobs    JobQueuer
 	Args    config.StartupFlagsParser
 }
 
 // NewLinkHandler returns instance of LinkHandler.
-func NewLinkHandler(userService service.LinkServicer, ah IAuthentication, args config.StartupFlagsParser) LinkHandler {
-	return &LinkHandle{service: userService, ah: ah, Args: args}
+func NewLinkHandler(userService service.LinkServicer, ah IAuthentication, jobs JobQueuer, args config.StartupFlagsParser) LinkHandler {
+	return &LinkHandle{service: userService, ah: ah, jobs: jobs, Args: args}
 }
 
 // Get returns Handler for URLs registration for GET method.
 func (lh *LinkHandle) Get(w http.ResponseWriter, r *http.Request) {
 	ld, err := lh.service.GetShort(r.Context(), chi.URLParam(r, "shortURL"))
 	if err != nil {
-		logger.Sugar.Infof(`Unable to find long URL for short: %s: status: %d`, r.URL.Path[1:], http.StatusBadRequest)
+		metrics.Redirects.Inc(metrics.RedirectMiss)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to find long URL for short: %s: status: %d`, r.URL.Path[1:], http.StatusBadRequest)
 		http.Error(w, `Unable to find long URL for short`, http.StatusBadRequest)
 
 		return
 	}
 
+	auditLinks(r, ld)
+
 	if ld.IsDeleted {
+		metrics.Redirects.Inc(metrics.RedirectGone)
 		w.WriteHeader(http.StatusGone)
-		logger.Sugar.Infof(`Full Link: %s, for Short "%s" was deleted.`, ld.LongURL, chi.URLParam(r, "shortURL"))
+		logger.SugarFromContext(r.Context()).Infof(`Full Link: %s, for Short "%s" was deleted.`, ld.LongURL, chi.URLParam(r, "shortURL"))
 
 		return
 	}
 
+	metrics.Redirects.Inc(metrics.RedirectHit)
 	w.Header().Add("Location", ld.LongURL)
 
 	w.WriteHeader(http.StatusTemporaryRedirect)
-	logger.Sugar.Infof(`Full Link: %s, for Short "%s"`, ld.LongURL, chi.URLParam(r, "shortURL"))
+	logger.SugarFromContext(r.Context()).Infof(`Full Link: %s, for Short "%s"`, ld.LongURL, chi.URLParam(r, "shortURL"))
 }
 
 // PostText returns Handler for URLs registration for GET method.
 func (lh *LinkHandle) PostText(w http.ResponseWriter, r *http.Request) {
 	err := validators.TextPlain(w, r)
 	if err != nil {
-		logger.Sugar.Debugf("Unsupported Content-type: Header: after validation:", w.Header().Values("Content-type"))
+		logger.SugarFromContext(r.Context()).Debugf("Unsupported Content-type: Header: after validation: %v", w.Header().Values("Content-type"))
 		return
 	}
 
@@ -77,7 +94,7 @@ func (lh *LinkHandle) PostText(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		logger.Sugar.Infof(`Unable to read body: status: %d`, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to read body: status: %d`, http.StatusBadRequest)
 		http.Error(w, `Unable to read body`, http.StatusBadRequest)
 
 		return
@@ -85,27 +102,31 @@ func (lh *LinkHandle) PostText(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := lh.ah.GetUserIDFromCookie(r)
 	if err != nil && !errors.Is(err, ErrUnauthorizedUser) {
-		logger.Sugar.Errorf(`authorize service error: %d`, http.StatusInternalServerError)
+		logger.SugarFromContext(r.Context()).Errorf(`authorize service error: %d`, http.StatusInternalServerError)
 		http.Error(w, `Unauthorized`, http.StatusInternalServerError)
 
 		return
 	}
 
 	if errors.Is(err, ErrUnauthorizedUser) {
-		logger.Sugar.Errorf(`Unauthorized: status: %d`, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Errorf(`Unauthorized: status: %d`, http.StatusBadRequest)
 		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
 	}
 
 	lds, err := lh.service.RegisterLinks(r.Context(), []string{string(body)}, userID)
+	countShorten(err)
+
 	if err != nil && !errors.Is(err, repository.ErrExistingLink) {
-		logger.Sugar.Errorf(`Unable to shorten URL: status: %d`, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Errorf(`Unable to shorten URL: status: %d`, http.StatusBadRequest)
 		http.Error(w, `Unable to shorten URL`, http.StatusBadRequest)
 
 		return
 	}
 
+	auditLinks(r, lds...)
+
 	if errors.Is(err, repository.ErrExistingLink) {
-		logger.Sugar.Infof(`Found existing urls: status: %d`, http.StatusConflict)
+		logger.SugarFromContext(r.Context()).Infof(`Found existing urls: status: %d`, http.StatusConflict)
 		w.WriteHeader(http.StatusConflict)
 	} else {
 		w.WriteHeader(http.StatusCreated)
@@ -115,13 +136,13 @@ func (lh *LinkHandle) PostText(w http.ResponseWriter, r *http.Request) {
 
 	_, err = w.Write([]byte(redirectURL))
 	if err != nil {
-		logger.Sugar.Infof(`Unexpected exception: status: %w`, err)
+		logger.SugarFromContext(r.Context()).Infof(`Unexpected exception: %v`, err)
 		http.Error(w, `Unexpected exception: `, http.StatusInternalServerError)
 
 		return
 	}
 
-	logger.Sugar.Infof(`created ShortURL redirection: "%s" for longURL: "%s"`, redirectURL, lds[0].LongURL)
+	logger.SugarFromContext(r.Context()).Infof(`created ShortURL redirection: "%s" for longURL: "%s"`, redirectURL, lds[0].LongURL)
 }
 
 // PostJSON returns Handler for URLs registration for GET method.
@@ -135,12 +156,12 @@ func (lh *LinkHandle) PostJSON(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil && !errors.Is(err, io.EOF) {
-		logger.Log.Debug("cannot read body", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("cannot read body", zap.Error(err))
 	}
 
 	var req model.Request
 	if err := readReq(r, body, &req); err != nil {
-		logger.Log.Debug("cannot read request", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("cannot read request", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 
@@ -150,15 +171,19 @@ func (lh *LinkHandle) PostJSON(w http.ResponseWriter, r *http.Request) {
 	}
 
 	lds, err := lh.service.RegisterLinks(r.Context(), []string{req.URL}, userID)
+	countShorten(err)
+
 	if err != nil && !errors.Is(err, repository.ErrExistingLink) {
-		logger.Sugar.Infof(`Unable to shorten URL: status: %d`, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to shorten URL: status: %d`, http.StatusBadRequest)
 		http.Error(w, `Unable to shorten URL`, http.StatusBadRequest)
 
 		return
 	}
 
+	auditLinks(r, lds...)
+
 	if errors.Is(err, repository.ErrExistingLink) {
-		logger.Sugar.Infof(`Found existing urls: status: %d`, http.StatusConflict)
+		logger.SugarFromContext(r.Context()).Infof(`Found existing urls: status: %d`, http.StatusConflict)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusConflict)
 	} else {
@@ -177,23 +202,22 @@ func (lh *LinkHandle) PostJSON(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := writeRes(w, &resp); err != nil {
-		logger.Log.Debug("error encoding response", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("error encoding response", zap.Error(err))
 		http.Error(w, `Unexpected exception: `, http.StatusInternalServerError)
 
 		return
 	}
 
-	logger.Sugar.Infof(`created ShortURL redirection: "%s" for longURL: "%s"`, resp.Result, lds[0].LongURL)
+	logger.SugarFromContext(r.Context()).Infof(`created ShortURL redirection: "%s" for longURL: "%s"`, resp.Result, lds[0].LongURL)
 }
 
 // PingDB provide 200 for successful database ping.
 func (lh *LinkHandle) PingDB(w http.ResponseWriter, r *http.Request) {
-	logger.Sugar.Infof(`Full request: %#v`, *r)
-
-	ctx, cancel := context.WithTimeout(context.Background(), lh.Args.GetTimeout())
+	ctx, cancel := context.WithTimeout(r.Context(), lh.Args.GetTimeout())
 	defer cancel()
 
 	if err := lh.service.PingDB(ctx); err != nil {
+		logger.SugarFromContext(r.Context()).Errorf("Ping db error: %v", err)
 		http.Error(w, "ping db error", http.StatusInternalServerError)
 
 		return
@@ -213,16 +237,16 @@ func (lh *LinkHandle) PostBatchJSON(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil && !errors.Is(err, io.EOF) {
-		logger.Log.Debug("cannot read body", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("cannot read body", zap.Error(err))
 	}
 
 	var req model.SlReqPostBatch
 	if err := readReq(r, body, &req); err != nil {
-		logger.Log.Debug("cannot read request", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("cannot read request", zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 
-	logger.Sugar.Infof("req struct for batch: %s", req)
+	logger.SugarFromContext(r.Context()).Infof("req struct for batch: %s", req)
 
 	reqLongUrls := make([]string, 0, len(req))
 	for _, reqElem := range req {
@@ -235,13 +259,17 @@ func (lh *LinkHandle) PostBatchJSON(w http.ResponseWriter, r *http.Request) {
 	}
 
 	lds, err := lh.service.RegisterLinks(r.Context(), reqLongUrls, userID)
+	countShorten(err)
+
 	if err != nil && !errors.Is(err, repository.ErrExistingLink) {
-		logger.Sugar.Infof(`Unable to shorten URL: status: %d`, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to shorten URL: status: %d`, http.StatusBadRequest)
 		http.Error(w, `Unable to shorten URL`, http.StatusBadRequest)
 
 		return
 	}
 
+	auditLinks(r, lds...)
+
 	resp := make(model.SlResPostBatch, 0, len(lds))
 	for i, linkData := range lds {
 		resp = append(resp, model.ResPostBatch{
@@ -254,7 +282,7 @@ func (lh *LinkHandle) PostBatchJSON(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 
 	if err := writeRes(w, &resp); err != nil {
-		logger.Log.Debug("error encoding response", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("error encoding response", zap.Error(err))
 		http.Error(w, `Unexpected exception: `, http.StatusInternalServerError)
 
 		return
@@ -270,14 +298,14 @@ func (lh *LinkHandle) GetUserLinks(w http.ResponseWriter, r *http.Request) {
 
 	lds, err := lh.service.ProvideUserLinks(r.Context(), userID)
 	if err != nil && !errors.Is(err, repository.ErrNotFoundLink) {
-		logger.Sugar.Infof(`Unable to get URLs for User %s: status: %d`, userID, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Infof(`Unable to get URLs for User %s: status: %d`, userID, http.StatusBadRequest)
 		http.Error(w, fmt.Sprintf(`Unable to get URLs for User %s: status: %d`, userID, http.StatusBadRequest), http.StatusBadRequest)
 
 		return
 	}
 
 	if errors.Is(err, repository.ErrNotFoundLink) {
-		logger.Sugar.Infof(`No links found for User %s: status: %d`, userID, http.StatusBadRequest)
+		logger.SugarFromContext(r.Context()).Infof(`No links found for User %s: status: %d`, userID, http.StatusBadRequest)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusNoContent)
 
@@ -287,8 +315,11 @@ func (lh *LinkHandle) GetUserLinks(w http.ResponseWriter, r *http.Request) {
 	resp := make(model.LongShortURLs, 0, len(lds))
 	for _, linkData := range lds {
 		resp = append(resp, model.LongShortURL{
-			LongURL:  linkData.LongURL,
-			ShortURL: lh.Args.GetAddressShortURL() + "/" + linkData.ShortURL,
+			LongURL:   linkData.LongURL,
+			ShortURL:  lh.Args.GetAddressShortURL() + "/" + linkData.ShortURL,
+			CreatedAt: linkData.CreatedAt,
+			UpdatedAt: linkData.UpdatedAt,
+			DeletedAt: linkData.DeletedAt,
 		})
 	}
 
@@ -296,47 +327,183 @@ func (lh *LinkHandle) GetUserLinks(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
 	if err := writeRes(w, &resp); err != nil {
-		logger.Log.Debug("error encoding response", zap.Error(err))
+		logger.FromContext(r.Context()).Debug("error encoding response", zap.Error(err))
 		http.Error(w, `Unexpected exception: `, http.StatusInternalServerError)
 
 		return
 	}
 }
 
-// DeleteUserLinks provide realization od batch user links deletion.
+// DeleteUserLinks enqueues batch user links deletion and returns job for status polling.
 func (lh *LinkHandle) DeleteUserLinks(w http.ResponseWriter, r *http.Request) {
-	err := validators.ApplicationJSON(w, r)
+	var req model.ShortUrls
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := lh.ah.GetUserIDFromCookie(r)
 	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
 		return
 	}
-	defer r.Body.Close()
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil && !errors.Is(err, io.EOF) {
-		logger.Log.Debug("cannot read body", zap.Error(err))
+	job, err := lh.jobs.Enqueue(r.Context(), model.JobTypeDeleteLinks, userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	auditShortURLs(r, req)
+
+	resp := newResJob(job)
+	writeJSON(w, http.StatusAccepted, &resp)
+	logger.SugarFromContext(r.Context()).Infof(`url: "%s" Accepted for deletion: %s`, req, job.String())
+}
+
+// GetJob provide status of background job owned by current user.
+func (lh *LinkHandle) GetJob(w http.ResponseWriter, r *http.Request) {
+	userID, err := lh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	// Postgres не сравнивает uuid со строкой другого вида, такой задачи нет ни в одном хранилище.
+	jobID := chi.URLParam(r, "jobID")
+	if !isUUID(jobID) {
+		writeServiceError(w, repository.ErrNotFoundJob)
+		return
+	}
+
+	job, err := lh.jobs.Get(r.Context(), model.UUIDv7(jobID))
+	if err == nil && job.UserID != userID {
+		err = repository.ErrNotFoundJob
+	}
+
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := newResJob(job)
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+// isUUID reports whether s is uuid in canonical 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func newResJob(job model.Job) model.ResJob {
+	return model.ResJob{
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+		ID:        job.ID,
+		Status:    job.Status,
+		LastError: job.LastError,
+		Attempts:  job.Attempts,
+	}
+}
+
+// PatchUserLink changes destination of user link keeping short code.
+func (lh *LinkHandle) PatchUserLink(w http.ResponseWriter, r *http.Request) {
+	var req model.ReqEditLink
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := lh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
 	}
 
+	ld, err := lh.service.EditLink(r.Context(), userID, chi.URLParam(r, "shortURL"), req.LongURL)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	auditLinks(r, ld)
+
+	resp := model.LongShortURL{
+		LongURL:   ld.LongURL,
+		ShortURL:  lh.Args.GetAddressShortURL() + "/" + ld.ShortURL,
+		CreatedAt: ld.CreatedAt,
+		UpdatedAt: ld.UpdatedAt,
+		DeletedAt: ld.DeletedAt,
+	}
+
+	writeJSON(w, http.StatusOK, &resp)
+	logger.SugarFromContext(r.Context()).Infof(`changed ShortURL redirection: "%s" to longURL: "%s"`, resp.ShortURL, resp.LongURL)
+}
+
+// GetLinkRevisions provide destination change history of user link.
+func (lh *LinkHandle) GetLinkRevisions(w http.ResponseWriter, r *http.Request) {
+	userID, err := lh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	revs, err := lh.service.ProvideLinkRevisions(r.Context(), userID, chi.URLParam(r, "shortURL"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := model.LinkRevisions(revs)
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+// RestoreUserLinks restores user links deleted inside restore window.
+func (lh *LinkHandle) RestoreUserLinks(w http.ResponseWriter, r *http.Request) {
 	var req model.ShortUrls
-	if err := readReq(r, body, &req); err != nil {
-		logger.Log.Debug("cannot read request", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
+	if !readJSONReq(w, r, &req) {
+		return
 	}
 
 	userID, err := lh.ah.GetUserIDFromCookie(r)
 	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	lds, err := lh.service.RestoreLinks(r.Context(), userID, req)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
-	// Асинхронное выполнение
-	go func() {
-		batchCtx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+	auditLinks(r, lds...)
 
-		if err := lh.service.MarkAsDeleted(batchCtx, userID, req); err != nil {
-			logger.Sugar.Errorf("Failed to mark URLs as deleted: %v", err)
-		}
-	}()
+	resp := make(model.LongShortURLs, 0, len(lds))
+	for _, linkData := range lds {
+		resp = append(resp, model.LongShortURL{
+			LongURL:   linkData.LongURL,
+			ShortURL:  lh.Args.GetAddressShortURL() + "/" + linkData.ShortURL,
+			CreatedAt: linkData.CreatedAt,
+			UpdatedAt: linkData.UpdatedAt,
+		})
+	}
 
-	w.WriteHeader(http.StatusAccepted)
-	logger.Sugar.Infof(`url: "%s" Accepted for deletion`, req)
+	writeJSON(w, http.StatusOK, &resp)
+	logger.SugarFromContext(r.Context()).Infof(`restored <%d> of <%d> urls for User %s`, len(lds), len(req), userID)
 }
@@ -8,16 +8,24 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/jobqueue"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/Pklerik/urlshortener/internal/model"
 	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
 	mock_repository "github.com/Pklerik/urlshortener/internal/repository/mocks"
 	"github.com/Pklerik/urlshortener/internal/service"
 	"github.com/Pklerik/urlshortener/internal/service/links"
+	"github.com/Pklerik/urlshortener/pkg/jwtgenerator"
 	"github.com/go-chi/chi"
+	"github.com/goccy/go-json"
 	"github.com/golang/mock/gomock"
+	"github.com/samborkent/uuidv7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -74,6 +82,7 @@ func TestLinkHandle_Get(t *testing.T) {
 			lh := NewLinkHandler(
 				links.NewLinksService(r, baseConfig.GetSecretKey()),
 				NewAuthenticationHandler(ls),
+				nil,
 				tt.fields.Args,
 			)
 			rctx := chi.NewRouteContext()
@@ -117,6 +126,7 @@ func TestLinkHandle_PostText(t *testing.T) {
 			lh := NewLinkHandler(
 				tt.fields.linkService,
 				NewAuthenticationHandler(tt.fields.linkService),
+				nil,
 				tt.fields.Args,
 			)
 			lh.PostText(tt.args.w, tt.args.r)
@@ -154,6 +164,7 @@ func TestLinkHandle_PostJson(t *testing.T) {
 			lh := NewLinkHandler(
 				tt.fields.linkService,
 				NewAuthenticationHandler(tt.fields.linkService),
+				nil,
 				tt.fields.Args,
 			)
 			lh.PostText(tt.args.w, tt.args.r)
@@ -194,6 +205,7 @@ func TestLinkHandle_PingDB(t *testing.T) {
 			lh := NewLinkHandler(
 				tt.fields.linkService,
 				NewAuthenticationHandler(tt.fields.linkService),
+				nil,
 				tt.fields.Args,
 			)
 			lh.PingDB(tt.args.w, tt.args.r)
@@ -255,6 +267,144 @@ func TestLinkHandle_PostBatchJSON(t *testing.T) {
 	}
 }
 
+// authRequest adds auth_user cookie for userID to request.
+func authRequest(t *testing.T, r *http.Request, userID uuidv7.UUID) *http.Request {
+	t.Helper()
+
+	token, err := jwtgenerator.BuildJWTString(userID, baseConfig.GetSecretKey())
+	require.NoError(t, err)
+
+	r.AddCookie(&http.Cookie{Name: "auth_user", Value: token})
+
+	return r
+}
+
+func TestLinkHandle_GetUserLinks(t *testing.T) {
+	userID := uuidv7.New()
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), baseConfig.GetSecretKey())
+	lh := NewLinkHandler(ls, NewAuthenticationHandler(ls), nil, &config.StartupFlags{BaseURL: "http://localhost:8080"})
+
+	_, err := ls.RegisterLinks(context.Background(), []string{"http://ya.ru"}, model.UserID(userID.String()))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	lh.GetUserLinks(w, authRequest(t, httptest.NewRequest(http.MethodGet, "/api/user/urls", nil), userID))
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp model.LongShortURLs
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, "http://localhost:8080/398f0ca4", resp[0].ShortURL)
+	assert.WithinDuration(t, time.Now(), resp[0].CreatedAt, time.Minute)
+	assert.Nil(t, resp[0].DeletedAt)
+}
+
+func TestLinkHandle_DeleteUserLinks(t *testing.T) {
+	owner, stranger := uuidv7.New(), uuidv7.New()
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), baseConfig.GetSecretKey())
+	queue := jobqueue.New(inmemory.NewInMemoryJobsRepository(), jobqueue.WithPollInterval(time.Millisecond))
+	queue.Register(model.JobTypeDeleteLinks, ls.HandleDeleteJob)
+	lh := NewLinkHandler(ls, NewAuthenticationHandler(ls), queue, &config.StartupFlags{BaseURL: "http://localhost:8080"})
+
+	_, err := ls.RegisterLinks(context.Background(), []string{"http://ya.ru"}, model.UserID(owner.String()))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/user/urls", bytes.NewBufferString(`["398f0ca4"]`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	lh.DeleteUserLinks(w, authRequest(t, req, owner))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var accepted model.ResJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	assert.Equal(t, model.JobPending, accepted.Status)
+
+	getJobByID := func(userID uuidv7.UUID, jobID string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("jobID", jobID)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/"+jobID, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		lh.GetJob(w, authRequest(t, req, userID))
+
+		return w
+	}
+	getJob := func(userID uuidv7.UUID) *httptest.ResponseRecorder {
+		return getJobByID(userID, string(accepted.ID))
+	}
+
+	assert.Equal(t, http.StatusNotFound, getJob(stranger).Code, "job is visible to owner only")
+	assert.Equal(t, http.StatusNotFound, getJobByID(owner, "not-a-uuid").Code, "malformed id doesn't reach storage")
+
+	require.NoError(t, queue.Start(context.Background()))
+	defer queue.Drain(context.Background())
+
+	require.Eventually(t, func() bool {
+		var job model.ResJob
+
+		w := getJob(owner)
+
+		return w.Code == http.StatusOK && json.Unmarshal(w.Body.Bytes(), &job) == nil && job.Status == model.JobDone
+	}, time.Second, 5*time.Millisecond)
+
+	ld, err := ls.GetShort(context.Background(), "398f0ca4")
+	require.NoError(t, err)
+	assert.True(t, ld.IsDeleted)
+}
+
+func TestLinkHandle_PatchUserLink(t *testing.T) {
+	owner, stranger := uuidv7.New(), uuidv7.New()
+	ls := links.NewLinksService(inmemory.NewInMemoryLinksRepository(), baseConfig.GetSecretKey())
+	lh := NewLinkHandler(ls, NewAuthenticationHandler(ls), nil, &config.StartupFlags{BaseURL: "http://localhost:8080"})
+
+	_, err := ls.RegisterLinks(context.Background(), []string{"http://ya.ru"}, model.UserID(owner.String()))
+	require.NoError(t, err)
+
+	patch := func(userID uuidv7.UUID, body string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("shortURL", "398f0ca4")
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/user/urls/398f0ca4", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		w := httptest.NewRecorder()
+		lh.PatchUserLink(w, authRequest(t, req, userID))
+
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		userID uuidv7.UUID
+		code   int
+	}{
+		{name: "invalid url", userID: owner, body: `{"original_url": "ya.ru"}`, code: http.StatusBadRequest},
+		{name: "not owner", userID: stranger, body: `{"original_url": "https://yandex.ru"}`, code: http.StatusForbidden},
+		{name: "owner", userID: owner, body: `{"original_url": "https://yandex.ru"}`, code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, patch(tt.userID, tt.body).Code)
+		})
+	}
+
+	ld, err := ls.GetShort(context.Background(), "398f0ca4")
+	require.NoError(t, err)
+	assert.Equal(t, "https://yandex.ru", ld.LongURL)
+
+	revs, err := ls.ProvideLinkRevisions(context.Background(), model.UserID(owner.String()), "398f0ca4")
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	assert.Equal(t, "http://ya.ru", revs[0].OldURL)
+	assert.Equal(t, "https://yandex.ru", revs[0].NewURL)
+}
+
 // ExampleGet demonstrates how to use Get method of LinkHandler.
 func ExampleGet() {
 	ctrl := gomock.NewController(nil)
@@ -267,6 +417,7 @@ func ExampleGet() {
 	lh := NewLinkHandler(
 		links.NewLinksService(r, baseConfig.GetSecretKey()),
 		NewAuthenticationHandler(ls),
+		nil,
 		baseConfig,
 	)
 	rctx := chi.NewRouteContext()
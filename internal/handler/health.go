@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Pklerik/urlshortener/internal/health"
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// HealthHandler - provide contract for liveness and readiness probes.
+type HealthHandler interface {
+	Liveness(w http.ResponseWriter, r *http.Request)
+	Readiness(w http.ResponseWriter, r *http.Request)
+}
+
+// HealthHandle - wrapper for health checker.
+type HealthHandle struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler returns instance of HealthHandler.
+func NewHealthHandler(checker *health.Checker) HealthHandler {
+	return &HealthHandle{checker: checker}
+}
+
+// Liveness provide 200 while process is able to serve requests, dependencies are not checked.
+func (hh *HealthHandle) Liveness(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, &model.ResHealth{Status: model.HealthUp})
+}
+
+// Readiness provide 200 if all dependencies are healthy, otherwise 503 with failed checks.
+func (hh *HealthHandle) Readiness(w http.ResponseWriter, r *http.Request) {
+	res := hh.checker.Check(r.Context())
+
+	status := http.StatusOK
+	if res.Status != model.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, &res)
+}
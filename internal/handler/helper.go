@@ -2,13 +2,18 @@ package handler
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
+	"github.com/Pklerik/urlshortener/internal/handler/validators"
 	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/metrics"
 	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/service"
 	"github.com/goccy/go-json"
 	"go.uber.org/zap"
 )
@@ -47,3 +52,70 @@ func writeRes(w http.ResponseWriter, res model.Responser) error {
 
 	return nil
 }
+
+// writeServiceError maps service and repository errors to http statuses.
+func writeServiceError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrEmptyWorkspaceName), errors.Is(err, service.ErrInvalidRole),
+		errors.Is(err, service.ErrEmptyLongURL), errors.Is(err, service.ErrInvalidLongURL),
+		errors.Is(err, service.ErrInvalidWebhook), errors.Is(err, service.ErrInvalidAuditFilter):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrNotFoundWorkspace), errors.Is(err, repository.ErrNotFoundInvitation),
+		errors.Is(err, repository.ErrNotFoundLink), errors.Is(err, repository.ErrNotFoundJob),
+		errors.Is(err, repository.ErrNotFoundWebhook):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrExpiredInvitation), errors.Is(err, service.ErrDeletedLink):
+		status = http.StatusGone
+	}
+
+	logger.Sugar.Infof(`request error: %v: status: %d`, err, status)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// countShorten counts shorten request result by RegisterLinks error.
+func countShorten(err error) {
+	switch {
+	case err == nil:
+		metrics.Shortens.Inc(metrics.ShortenSuccess)
+	case errors.Is(err, repository.ErrExistingLink):
+		metrics.Shortens.Inc(metrics.ShortenConflict)
+	default:
+		metrics.Shortens.Inc(metrics.ShortenError)
+	}
+}
+
+// readJSONReq validates content type and decodes body to req.
+// Returns false if response is already written.
+func readJSONReq(w http.ResponseWriter, r *http.Request, req model.Requester) bool {
+	if err := validators.ApplicationJSON(w, r); err != nil {
+		return false
+	}
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		logger.Log.Debug("cannot read body", zap.Error(err))
+	}
+
+	if err := readReq(r, body, req); err != nil {
+		logger.Log.Debug("cannot read request", zap.Error(err))
+		http.Error(w, `Unable to read request`, http.StatusBadRequest)
+
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, res model.Responser) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := writeRes(w, res); err != nil {
+		logger.Log.Debug("error encoding response", zap.Error(err))
+	}
+}
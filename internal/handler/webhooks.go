@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/go-chi/chi"
+)
+
+// WebhookHandler - provide contract for webhooks request handling.
+type WebhookHandler interface {
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	GetWebhooks(w http.ResponseWriter, r *http.Request)
+	DeleteWebhook(w http.ResponseWriter, r *http.Request)
+	GetDeliveries(w http.ResponseWriter, r *http.Request)
+}
+
+// WebhookHandle - wrapper for webhook service handling.
+type WebhookHandle struct {
+	service service.WebhookServicer
+	ah      IAuthentication
+}
+
+// NewWebhookHandler returns instance of WebhookHandler.
+func NewWebhookHandler(whService service.WebhookServicer, ah IAuthentication) WebhookHandler {
+	return &WebhookHandle{service: whService, ah: ah}
+}
+
+// CreateWebhook registers webhook of current user. Response contains signing secret.
+func (wh *WebhookHandle) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req model.ReqWebhook
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	webhook, err := wh.service.CreateWebhook(r.Context(), userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &webhook)
+	logger.SugarFromContext(r.Context()).Infof(`created %s`, webhook.String())
+}
+
+// GetWebhooks provide all webhooks of current user.
+func (wh *WebhookHandle) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	whs, err := wh.service.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if len(whs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	resp := model.Webhooks(whs)
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+// DeleteWebhook removes webhook of current user.
+func (wh *WebhookHandle) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	if err := wh.service.DeleteWebhook(r.Context(), userID, webhookIDParam(r)); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries provide latest delivery log of webhook.
+func (wh *WebhookHandle) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	deliveries, err := wh.service.ListDeliveries(r.Context(), userID, webhookIDParam(r))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := model.WebhookDeliveries(deliveries)
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+func webhookIDParam(r *http.Request) model.WebhookID {
+	return model.WebhookID(chi.URLParam(r, "webhookID"))
+}
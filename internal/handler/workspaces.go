@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/go-chi/chi"
+)
+
+// WorkspaceHandler - provide contract for workspaces request handling.
+type WorkspaceHandler interface {
+	CreateWorkspace(w http.ResponseWriter, r *http.Request)
+	GetWorkspaces(w http.ResponseWriter, r *http.Request)
+	CreateInvitation(w http.ResponseWriter, r *http.Request)
+	AcceptInvitation(w http.ResponseWriter, r *http.Request)
+	PostWorkspaceLinks(w http.ResponseWriter, r *http.Request)
+	GetWorkspaceLinks(w http.ResponseWriter, r *http.Request)
+	DeleteWorkspaceLinks(w http.ResponseWriter, r *http.Request)
+	RestoreWorkspaceLinks(w http.ResponseWriter, r *http.Request)
+}
+
+// WorkspaceHandle - wrapper for workspace service handling.
+type WorkspaceHandle struct {
+	service service.WorkspaceServicer
+	ah      IAuthentication
+	Args    config.StartupFlagsParser
+}
+
+// NewWorkspaceHandler returns instance of WorkspaceHandler.
+func NewWorkspaceHandler(wsService service.WorkspaceServicer, ah IAuthentication, args config.StartupFlagsParser) WorkspaceHandler {
+	return &WorkspaceHandle{service: wsService, ah: ah, Args: args}
+}
+
+// CreateWorkspace creates workspace owned by current user.
+func (wh *WorkspaceHandle) CreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	var req model.ReqWorkspace
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	ws, err := wh.service.CreateWorkspace(r.Context(), req.Name, userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &ws)
+	logger.SugarFromContext(r.Context()).Infof(`created %s`, ws.String())
+}
+
+// GetWorkspaces provide all workspaces of current user with user roles.
+func (wh *WorkspaceHandle) GetWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	members, err := wh.service.ListWorkspaces(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if len(members) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	resp := model.WorkspaceMembers(members)
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+// CreateInvitation creates invitation token for workspace. Only owners can invite.
+func (wh *WorkspaceHandle) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	var req model.ReqInvitation
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	inv, err := wh.service.InviteToWorkspace(r.Context(), workspaceIDParam(r), userID, req.Role)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &inv)
+	logger.SugarFromContext(r.Context()).Infof(`created %s`, inv.String())
+}
+
+// AcceptInvitation adds current user to workspace by invitation token.
+func (wh *WorkspaceHandle) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	member, err := wh.service.AcceptInvitation(r.Context(), chi.URLParam(r, "token"), userID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &member)
+	logger.SugarFromContext(r.Context()).Infof(`accepted invitation: %s`, member.String())
+}
+
+// PostWorkspaceLinks provide json batch POST new workspace links realization.
+func (wh *WorkspaceHandle) PostWorkspaceLinks(w http.ResponseWriter, r *http.Request) {
+	var req model.SlReqPostBatch
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	reqLongUrls := make([]string, 0, len(req))
+	for _, reqElem := range req {
+		reqLongUrls = append(reqLongUrls, reqElem.LongURL)
+	}
+
+	lds, err := wh.service.RegisterWorkspaceLinks(r.Context(), workspaceIDParam(r), reqLongUrls, userID)
+	if err != nil && !errors.Is(err, repository.ErrExistingLink) {
+		writeServiceError(w, err)
+		return
+	}
+
+	auditLinks(r, lds...)
+
+	status := http.StatusCreated
+	if errors.Is(err, repository.ErrExistingLink) {
+		status = http.StatusConflict
+	}
+
+	resp := make(model.SlResPostBatch, 0, len(lds))
+	for i, linkData := range lds {
+		resp = append(resp, model.ResPostBatch{
+			CorrelationID: req[i].CorrelationID,
+			ShortURL:      wh.Args.GetAddressShortURL() + "/" + linkData.ShortURL,
+		})
+	}
+
+	writeJSON(w, status, &resp)
+}
+
+// GetWorkspaceLinks provide all links of workspace to any member.
+func (wh *WorkspaceHandle) GetWorkspaceLinks(w http.ResponseWriter, r *http.Request) {
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	lds, err := wh.service.ProvideWorkspaceLinks(r.Context(), workspaceIDParam(r), userID)
+	if errors.Is(err, repository.ErrNotFoundLink) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := make(model.LongShortURLs, 0, len(lds))
+	for _, linkData := range lds {
+		resp = append(resp, model.LongShortURL{
+			LongURL:   linkData.LongURL,
+			ShortURL:  wh.Args.GetAddressShortURL() + "/" + linkData.ShortURL,
+			CreatedAt: linkData.CreatedAt,
+			UpdatedAt: linkData.UpdatedAt,
+			DeletedAt: linkData.DeletedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, &resp)
+}
+
+// DeleteWorkspaceLinks marks workspace links as deleted. Requires editor role.
+func (wh *WorkspaceHandle) DeleteWorkspaceLinks(w http.ResponseWriter, r *http.Request) {
+	var req model.ShortUrls
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	if err := wh.service.MarkWorkspaceLinksDeleted(r.Context(), workspaceIDParam(r), userID, req); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	auditShortURLs(r, req)
+
+	w.WriteHeader(http.StatusAccepted)
+	logger.SugarFromContext(r.Context()).Infof(`url: "%s" Accepted for deletion`, req)
+}
+
+// RestoreWorkspaceLinks restores workspace links deleted inside restore window. Requires editor role.
+func (wh *WorkspaceHandle) RestoreWorkspaceLinks(w http.ResponseWriter, r *http.Request) {
+	var req model.ShortUrls
+	if !readJSONReq(w, r, &req) {
+		return
+	}
+
+	userID, err := wh.ah.GetUserIDFromCookie(r)
+	if err != nil {
+		http.Error(w, `Unauthorized`, http.StatusUnauthorized)
+		return
+	}
+
+	lds, err := wh.service.RestoreWorkspaceLinks(r.Context(), workspaceIDParam(r), userID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	auditLinks(r, lds...)
+
+	resp := make(model.LongShortURLs, 0, len(lds))
+	for _, linkData := range lds {
+		resp = append(resp, model.LongShortURL{
+			LongURL:   linkData.LongURL,
+			ShortURL:  wh.Args.GetAddressShortURL() + "/" + linkData.ShortURL,
+			CreatedAt: linkData.CreatedAt,
+			UpdatedAt: linkData.UpdatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, &resp)
+	logger.SugarFromContext(r.Context()).Infof(`restored <%d> of <%d> urls of workspace %s`, len(lds), len(req), workspaceIDParam(r))
+}
+
+func workspaceIDParam(r *http.Request) model.WorkspaceID {
+	return model.WorkspaceID(chi.URLParam(r, "workspaceID"))
+}
@@ -0,0 +1,118 @@
+// Package health aggregates readiness checks of app dependencies.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// shutdownCheck name of check reported while app is shutting down.
+const shutdownCheck = "shutdown"
+
+// ErrShuttingDown reported by readiness after graceful shutdown has begun.
+var ErrShuttingDown = errors.New("shutting down")
+
+// CheckFunc checks single dependency, returns nil if it is healthy.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	fn   CheckFunc
+	name string
+}
+
+// Checker runs registered checks concurrently, each one limited by timeout.
+type Checker struct {
+	shuttingDown func() bool
+	checks       []check
+	timeout      time.Duration
+	mu           sync.RWMutex
+}
+
+// New - provide instance of Checker. shuttingDown reports whether app stops accepting traffic, may be nil.
+func New(timeout time.Duration, shuttingDown func() bool) *Checker {
+	return &Checker{timeout: timeout, shuttingDown: shuttingDown}
+}
+
+// Register adds named check.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Check runs all checks and returns their results in registration order.
+// Status is down if any check failed or app is shutting down.
+func (c *Checker) Check(ctx context.Context) model.ResHealth {
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	res := model.ResHealth{Status: model.HealthUp, Checks: make([]model.HealthCheck, len(checks))}
+
+	var wg sync.WaitGroup
+
+	for i, ch := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res.Checks[i] = c.run(ctx, ch)
+		}()
+	}
+
+	wg.Wait()
+
+	if c.shuttingDown != nil && c.shuttingDown() {
+		res.Checks = append(res.Checks, model.HealthCheck{
+			Name:   shutdownCheck,
+			Status: model.HealthDown,
+			Error:  ErrShuttingDown.Error(),
+		})
+	}
+
+	for _, ch := range res.Checks {
+		if ch.Status != model.HealthUp {
+			res.Status = model.HealthDown
+		}
+	}
+
+	return res
+}
+
+func (c *Checker) run(ctx context.Context, ch check) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	// проверка может не учитывать ctx, поэтому ответ не ждёт её дольше таймаута.
+	go func() { done <- ch.fn(ctx) }()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := model.HealthCheck{
+		Name:      ch.name,
+		Status:    model.HealthUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		res.Status = model.HealthDown
+		res.Error = err.Error()
+	}
+
+	return res
+}
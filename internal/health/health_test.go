@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Check(t *testing.T) {
+	ok := func(_ context.Context) error { return nil }
+	failing := func(_ context.Context) error { return errors.New("connection refused") }
+	hanging := func(_ context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		checks       map[string]CheckFunc
+		wantChecks   map[string]string
+		name         string
+		wantStatus   string
+		shuttingDown bool
+	}{
+		{
+			name:       "all_up",
+			checks:     map[string]CheckFunc{"database": ok, "audit": ok},
+			wantStatus: model.HealthUp,
+			wantChecks: map[string]string{"database": model.HealthUp, "audit": model.HealthUp},
+		},
+		{
+			name:       "failed_check",
+			checks:     map[string]CheckFunc{"database": failing, "audit": ok},
+			wantStatus: model.HealthDown,
+			wantChecks: map[string]string{"database": model.HealthDown, "audit": model.HealthUp},
+		},
+		{
+			name:       "timeout",
+			checks:     map[string]CheckFunc{"file_storage": hanging},
+			wantStatus: model.HealthDown,
+			wantChecks: map[string]string{"file_storage": model.HealthDown},
+		},
+		{
+			name:         "shutting_down",
+			checks:       map[string]CheckFunc{"database": ok},
+			shuttingDown: true,
+			wantStatus:   model.HealthDown,
+			wantChecks:   map[string]string{"database": model.HealthUp, shutdownCheck: model.HealthDown},
+		},
+		{
+			name:       "no_checks",
+			wantStatus: model.HealthUp,
+			wantChecks: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := New(50*time.Millisecond, func() bool { return tt.shuttingDown })
+			for name, fn := range tt.checks {
+				checker.Register(name, fn)
+			}
+
+			start := time.Now()
+			res := checker.Check(context.Background())
+
+			assert.Less(t, time.Since(start), 500*time.Millisecond, "slow checks are cut by timeout")
+			assert.Equal(t, tt.wantStatus, res.Status)
+			require.Len(t, res.Checks, len(tt.wantChecks))
+
+			for _, ch := range res.Checks {
+				assert.Equal(t, tt.wantChecks[ch.Name], ch.Status, ch.Name)
+				assert.Equal(t, ch.Status == model.HealthDown, ch.Error != "", ch.Name)
+			}
+		})
+	}
+}
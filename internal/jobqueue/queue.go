@@ -0,0 +1,350 @@
+// Package jobqueue provide durable background jobs processing with retries.
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/samborkent/uuidv7"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultMaxAttempts  = 5
+	defaultBackoff      = time.Second
+	defaultMaxBackoff   = 5 * time.Minute
+	defaultPollInterval = time.Second
+	// defaultLease time after last heartbeat when running job is considered abandoned by crashed instance.
+	defaultLease  = time.Minute
+	updateTimeout = 5 * time.Second
+)
+
+// ErrUnknownJobType - there is no handler registered for job type.
+var ErrUnknownJobType = errors.New("unknown job type")
+
+// Handler processes job. Returned error schedules retry.
+type Handler func(ctx context.Context, job model.Job) error
+
+// Queue - pool of workers processing jobs from JobsRepository.
+type Queue struct {
+	repo         repository.JobsRepository
+	handlers     map[string]Handler
+	wake         chan struct{}
+	stop         context.CancelFunc
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	workers      int
+	maxAttempts  int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	pollInterval time.Duration
+	lease        time.Duration
+}
+
+// Option provide optional Queue settings.
+type Option func(q *Queue)
+
+// WithWorkers sets number of parallel workers.
+func WithWorkers(n int) Option {
+	return func(q *Queue) {
+		if n > 0 {
+			q.workers = n
+		}
+	}
+}
+
+// WithMaxAttempts sets number of attempts before job is marked as failed.
+func WithMaxAttempts(n int) Option {
+	return func(q *Queue) {
+		if n > 0 {
+			q.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets base and max delay between attempts. Delay doubles after every attempt.
+func WithBackoff(base, maxDelay time.Duration) Option {
+	return func(q *Queue) {
+		if base > 0 && maxDelay >= base {
+			q.backoff = base
+			q.maxBackoff = maxDelay
+		}
+	}
+}
+
+// WithPollInterval sets period between storage checks when queue is idle.
+func WithPollInterval(interval time.Duration) Option {
+	return func(q *Queue) {
+		if interval > 0 {
+			q.pollInterval = interval
+		}
+	}
+}
+
+// WithLease sets time after last heartbeat when running job is returned to pending.
+// Running jobs are touched every third of lease.
+func WithLease(lease time.Duration) Option {
+	return func(q *Queue) {
+		if lease > 0 {
+			q.lease = lease
+		}
+	}
+}
+
+// New - provide instance of Queue.
+func New(repo repository.JobsRepository, opts ...Option) *Queue {
+	q := &Queue{
+		repo:         repo,
+		handlers:     make(map[string]Handler),
+		wake:         make(chan struct{}, 1),
+		workers:      defaultWorkers,
+		maxAttempts:  defaultMaxAttempts,
+		backoff:      defaultBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		pollInterval: defaultPollInterval,
+		lease:        defaultLease,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// Register sets handler for job type. Must be called before Start.
+func (q *Queue) Register(jobType string, h Handler) {
+	q.handlers[jobType] = h
+}
+
+// Enqueue stores new pending job with payload encoded as JSON.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, userID model.UserID, payload any) (model.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return model.Job{}, fmt.Errorf("Enqueue: %w", err)
+	}
+
+	job, err := q.repo.CreateJob(ctx, model.Job{
+		ID:          model.UUIDv7(uuidv7.New().String()),
+		Type:        jobType,
+		UserID:      userID,
+		Payload:     string(data),
+		Status:      model.JobPending,
+		RunAt:       time.Now(),
+		MaxAttempts: q.maxAttempts,
+	})
+	if err != nil {
+		return job, fmt.Errorf("Enqueue: %w", err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// Get provide job by id.
+func (q *Queue) Get(ctx context.Context, id model.UUIDv7) (model.Job, error) {
+	job, err := q.repo.SelectJob(ctx, id)
+	if err != nil {
+		return job, fmt.Errorf("Get: %w", err)
+	}
+
+	return job, nil
+}
+
+// Start returns jobs abandoned by stopped or crashed instances to pending and starts workers.
+// Jobs of other live instances keep running: they are touched more often than lease expires.
+// Workers run until Drain is called.
+func (q *Queue) Start(ctx context.Context) error {
+	if err := q.resetAbandoned(ctx); err != nil {
+		return fmt.Errorf("Start: %w", err)
+	}
+
+	stopCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	q.stop, q.cancel = stop, cancel
+
+	for range q.workers {
+		q.wg.Add(1)
+
+		go func() {
+			defer q.wg.Done()
+			q.work(stopCtx, runCtx)
+		}()
+	}
+
+	q.wg.Add(1)
+
+	go func() {
+		defer q.wg.Done()
+		q.reap(stopCtx)
+	}()
+
+	return nil
+}
+
+// resetAbandoned returns running jobs with expired lease to pending.
+func (q *Queue) resetAbandoned(ctx context.Context) error {
+	reset, err := q.repo.ResetRunningJobs(ctx, q.lease)
+	if err != nil {
+		return fmt.Errorf("resetAbandoned: %w", err)
+	}
+
+	if reset > 0 {
+		logger.Sugar.Infof("Job queue resumed <%d> abandoned jobs", reset)
+	}
+
+	return nil
+}
+
+// reap resets abandoned jobs every lease until ctx is done, so jobs of crashed instance don't wait for restart.
+func (q *Queue) reap(ctx context.Context) {
+	ticker := time.NewTicker(q.lease)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.resetAbandoned(ctx); err != nil && ctx.Err() == nil {
+				logger.Sugar.Errorf("Unable to reset abandoned jobs: %v", err)
+			}
+		}
+	}
+}
+
+// Drain stops claiming new jobs once ready jobs are processed and waits for workers.
+// If ctx is done first running jobs are canceled and stay in storage for next Start.
+func (q *Queue) Drain(ctx context.Context) error {
+	if q.stop == nil {
+		return nil
+	}
+
+	q.stop()
+
+	done := make(chan struct{})
+
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+
+		return fmt.Errorf("Drain: %w", ctx.Err())
+	}
+}
+
+func (q *Queue) work(stopCtx, runCtx context.Context) {
+	timer := time.NewTimer(q.pollInterval)
+	defer timer.Stop()
+
+	for runCtx.Err() == nil {
+		job, err := q.repo.ClaimJob(runCtx, time.Now())
+		if err == nil {
+			q.process(runCtx, job)
+			continue
+		}
+
+		if !errors.Is(err, repository.ErrNoJobs) {
+			logger.Sugar.Errorf("Unable to claim job: %v", err)
+		}
+
+		timer.Reset(q.pollInterval)
+
+		select {
+		case <-stopCtx.Done():
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job model.Job) {
+	err := q.run(ctx, job)
+
+	switch {
+	case err == nil:
+		job.Status = model.JobDone
+		job.LastError = ""
+	case ctx.Err() != nil:
+		// выполнение прервано остановкой: попытка не засчитывается, задача продолжится после запуска.
+		job.Status = model.JobPending
+		job.Attempts--
+		job.RunAt = time.Now()
+	case job.Attempts >= job.MaxAttempts || errors.Is(err, ErrUnknownJobType):
+		job.Status = model.JobFailed
+		job.LastError = err.Error()
+	default:
+		job.Status = model.JobPending
+		job.LastError = err.Error()
+		job.RunAt = time.Now().Add(q.delay(job.Attempts))
+	}
+
+	logger.Sugar.Infof("Processed %s: %v", job.String(), err)
+
+	// состояние сохраняется даже если выполнение прервано отменой ctx.
+	updateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), updateTimeout)
+	defer cancel()
+
+	if err := q.repo.UpdateJob(updateCtx, job); err != nil {
+		logger.Sugar.Errorf("Unable to update %s: %v", job.String(), err)
+	}
+}
+
+// run calls job handler touching job until handler returns, so other instances don't take it.
+func (q *Queue) run(ctx context.Context, job model.Job) error {
+	h, ok := q.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJobType, job.Type)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(q.lease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := q.repo.TouchJob(ctx, job.ID); err != nil && ctx.Err() == nil {
+					logger.Sugar.Warnf("Unable to touch %s: %v", job.String(), err)
+				}
+			}
+		}
+	}()
+
+	return h(ctx, job)
+}
+
+// delay returns backoff before next attempt.
+func (q *Queue) delay(attempts int) time.Duration {
+	d := q.backoff
+	for i := 1; i < attempts && d < q.maxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, q.maxBackoff)
+}
@@ -0,0 +1,161 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_Process(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	tests := []struct {
+		name         string
+		failures     int32
+		wantStatus   model.JobStatus
+		wantAttempts int
+	}{
+		{name: "success", failures: 0, wantStatus: model.JobDone, wantAttempts: 1},
+		{name: "retried", failures: 2, wantStatus: model.JobDone, wantAttempts: 3},
+		{name: "failed", failures: 10, wantStatus: model.JobFailed, wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			q := New(inmemory.NewInMemoryJobsRepository(),
+				WithWorkers(2), WithMaxAttempts(3),
+				WithBackoff(time.Millisecond, 5*time.Millisecond), WithPollInterval(time.Millisecond),
+			)
+
+			var calls atomic.Int32
+
+			q.Register("test", func(_ context.Context, job model.Job) error {
+				assert.JSONEq(t, `["a","b"]`, job.Payload)
+
+				if calls.Add(1) <= tt.failures {
+					return errors.New("temporary")
+				}
+
+				return nil
+			})
+
+			job, err := q.Enqueue(ctx, "test", "user", model.ShortUrls{"a", "b"})
+			require.NoError(t, err)
+			require.NoError(t, q.Start(ctx))
+
+			require.Eventually(t, func() bool {
+				job, err = q.Get(ctx, job.ID)
+				return err == nil && (job.Status == model.JobDone || job.Status == model.JobFailed)
+			}, time.Second, time.Millisecond)
+
+			require.NoError(t, q.Drain(ctx))
+			assert.Equal(t, tt.wantStatus, job.Status)
+			assert.Equal(t, tt.wantAttempts, job.Attempts)
+		})
+	}
+}
+
+func TestQueue_Drain(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	ctx := context.Background()
+	repo := inmemory.NewInMemoryJobsRepository()
+	q := New(repo, WithWorkers(1), WithPollInterval(time.Hour))
+
+	started := make(chan struct{})
+
+	q.Register("slow", func(ctx context.Context, _ model.Job) error {
+		close(started)
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	job, err := q.Enqueue(ctx, "slow", "user", nil)
+	require.NoError(t, err)
+	require.NoError(t, q.Start(ctx))
+	<-started
+
+	drainCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, q.Drain(drainCtx), context.DeadlineExceeded)
+
+	job, err = q.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobPending, job.Status, "interrupted job is kept for next start")
+	assert.Zero(t, job.Attempts, "interrupted attempt is not counted")
+}
+
+func TestQueue_Lease(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	ctx := context.Background()
+	repo := inmemory.NewInMemoryJobsRepository()
+	lease := 30 * time.Millisecond
+
+	// задача живого экземпляра продлевается и не сбрасывается другим экземпляром.
+	live := New(repo, WithWorkers(1), WithPollInterval(time.Millisecond), WithLease(lease))
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	var runs atomic.Int32
+
+	live.Register("slow", func(_ context.Context, _ model.Job) error {
+		if runs.Add(1) == 1 {
+			close(started)
+		}
+
+		<-release
+
+		return nil
+	})
+
+	job, err := live.Enqueue(ctx, "slow", "user", nil)
+	require.NoError(t, err)
+	require.NoError(t, live.Start(ctx))
+	<-started
+
+	time.Sleep(3 * lease)
+
+	reset, err := repo.ResetRunningJobs(ctx, lease)
+	require.NoError(t, err)
+	assert.Zero(t, reset, "touched job is not reset")
+
+	close(release)
+	require.NoError(t, live.Drain(ctx))
+
+	job, err = live.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobDone, job.Status)
+	assert.Equal(t, int32(1), runs.Load(), "job runs once")
+
+	// задача упавшего экземпляра возвращается в очередь после истечения аренды.
+	abandoned, err := live.Enqueue(ctx, "slow", "user", nil)
+	require.NoError(t, err)
+
+	_, err = repo.ClaimJob(ctx, time.Now())
+	require.NoError(t, err)
+
+	reset, err = repo.ResetRunningJobs(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, reset, "lease is not expired yet")
+
+	time.Sleep(2 * lease)
+
+	reset, err = repo.ResetRunningJobs(ctx, lease)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reset)
+
+	abandoned, err = live.Get(ctx, abandoned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.JobPending, abandoned.Status)
+}
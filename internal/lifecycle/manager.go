@@ -0,0 +1,153 @@
+// Package lifecycle tracks background tasks and releases app resources on shutdown.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+)
+
+// Manager tracks goroutines started outside of request handling and shutdown hooks.
+type Manager struct {
+	tasks      map[uint64]string
+	stopCtx    context.Context
+	stop       context.CancelFunc
+	abandonCtx context.Context
+	abandon    context.CancelFunc
+	hooks      []hook
+	wg         sync.WaitGroup
+	nextID     uint64
+	mu         sync.Mutex
+	draining   atomic.Bool
+	closing    bool
+}
+
+type hook struct {
+	fn   func(ctx context.Context) error
+	name string
+}
+
+// New - provide instance of Manager.
+func New() *Manager {
+	m := &Manager{tasks: make(map[uint64]string)}
+	m.stopCtx, m.stop = context.WithCancel(context.Background())
+	m.abandonCtx, m.abandon = context.WithCancel(context.Background())
+
+	return m
+}
+
+// Go runs finite task fn in tracked goroutine. Shutdown waits for it until deadline,
+// after that ctx passed to fn is canceled.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.track(m.abandonCtx, name, fn)
+}
+
+// GoLoop runs long living task fn in tracked goroutine. ctx passed to fn is canceled
+// as soon as Shutdown starts, fn must return promptly after that.
+func (m *Manager) GoLoop(name string, fn func(ctx context.Context)) {
+	m.track(m.stopCtx, name, fn)
+}
+
+func (m *Manager) track(ctx context.Context, name string, fn func(ctx context.Context)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closing {
+		logger.Sugar.Warnf("Task %q is abandoned: shutdown in progress", name)
+		return
+	}
+
+	id := m.nextID
+	m.nextID++
+	m.tasks[id] = name
+
+	m.wg.Add(1)
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			delete(m.tasks, id)
+			m.mu.Unlock()
+			m.wg.Done()
+		}()
+
+		fn(ctx)
+	}()
+}
+
+// OnShutdown registers hook called after tracked tasks are finished.
+// Hooks are called in reverse registration order, so resources opened first are released last.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// BeginShutdown marks app as shutting down before connections are drained.
+// Tasks keep running, only ShuttingDown reports true.
+func (m *Manager) BeginShutdown() {
+	m.draining.Store(true)
+}
+
+// ShuttingDown reports whether shutdown has begun, readiness checks fail since then.
+func (m *Manager) ShuttingDown() bool {
+	return m.draining.Load()
+}
+
+// Shutdown stops loops, waits for tracked tasks until ctx is done and calls shutdown hooks.
+// Tasks still running at deadline are logged and their context is canceled.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.BeginShutdown()
+
+	m.mu.Lock()
+	m.closing = true
+	m.mu.Unlock()
+
+	m.stop()
+
+	done := make(chan struct{})
+
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	var errs []error
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.mu.Lock()
+		for _, name := range m.tasks {
+			logger.Sugar.Warnf("Task %q is abandoned: %v", name, ctx.Err())
+		}
+		m.mu.Unlock()
+
+		errs = append(errs, fmt.Errorf("waiting tasks: %w", ctx.Err()))
+	}
+
+	m.abandon()
+
+	for i := len(m.hooks) - 1; i >= 0; i-- {
+		h := m.hooks[i]
+		if err := h.fn(ctx); err != nil {
+			logger.Sugar.Errorf("Shutdown hook %q failed: %v", h.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+
+			continue
+		}
+
+		logger.Sugar.Infof("Shutdown hook %q done", h.name)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("Shutdown: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	m := New()
+
+	var (
+		finished, loopStopped, abandoned bool
+		order                            []string
+	)
+
+	m.Go("finite", func(_ context.Context) {
+		time.Sleep(10 * time.Millisecond)
+
+		finished = true
+	})
+	m.GoLoop("loop", func(ctx context.Context) {
+		<-ctx.Done()
+
+		loopStopped = true
+	})
+	m.OnShutdown("first", func(_ context.Context) error {
+		order = append(order, "first")
+		return nil
+	})
+	m.OnShutdown("second", func(_ context.Context) error {
+		order = append(order, "second")
+		return errors.New("close failed")
+	})
+
+	assert.False(t, m.ShuttingDown())
+
+	err := m.Shutdown(context.Background())
+	require.Error(t, err, "hook errors are returned")
+	assert.True(t, m.ShuttingDown())
+	assert.True(t, finished)
+	assert.True(t, loopStopped)
+	assert.Equal(t, []string{"second", "first"}, order, "hooks are called in reverse order")
+
+	m.Go("late", func(_ context.Context) { abandoned = true })
+	assert.False(t, abandoned, "tasks are rejected after shutdown")
+}
+
+func TestManager_ShutdownDeadline(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	m := New()
+	canceled := make(chan struct{})
+
+	m.Go("stuck", func(ctx context.Context) {
+		<-ctx.Done()
+		close(canceled)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, m.Shutdown(ctx), context.DeadlineExceeded)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned task context is not canceled")
+	}
+}
@@ -0,0 +1,244 @@
+// Package listener opens TCP, Unix socket and systemd socket-activated listeners.
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall" // nolint:depguard // ECONNREFUSED tells stale socket from used one
+	"time"
+)
+
+// Address schemes of not TCP listeners.
+const (
+	// SchemeUnix prefix of Unix socket path: unix:///run/shortener.sock.
+	SchemeUnix = "unix://"
+	// SchemeSystemd prefix of socket passed by systemd: systemd://name, name is FileDescriptorName of socket unit.
+	// Empty name takes first not used socket.
+	SchemeSystemd = "systemd://"
+)
+
+// DefaultSocketMode permissions of created Unix socket.
+const DefaultSocketMode fs.FileMode = 0o660
+
+const (
+	// listenFDsStart first file descriptor passed by systemd.
+	listenFDsStart = 3
+	// staleDialTimeout timeout of probing existing Unix socket.
+	staleDialTimeout = time.Second
+)
+
+var (
+	// ErrNoSystemdSocket systemd didn't pass requested socket.
+	ErrNoSystemdSocket = errors.New("no socket passed by systemd")
+	// ErrEmptySocketPath Unix socket path is not set.
+	ErrEmptySocketPath = errors.New("empty unix socket path")
+	// ErrSocketInUse Unix socket accepts connections of another running instance.
+	ErrSocketInUse = errors.New("unix socket is in use")
+)
+
+// inherited sockets passed by systemd, taken once on first use.
+var inherited struct {
+	files []*os.File
+	once  sync.Once
+	mu    sync.Mutex
+}
+
+// Open provide listener for addr:
+//
+//	host:port              TCP listener.
+//	unix:///path           Unix socket created with mode, stale socket file is replaced,
+//	                       socket of running instance is kept and ErrSocketInUse is returned.
+//	systemd://[name]       socket passed by systemd socket activation (LISTEN_FDS).
+func Open(addr string, mode fs.FileMode) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, SchemeUnix):
+		return openUnix(strings.TrimPrefix(addr, SchemeUnix), mode)
+	case strings.HasPrefix(addr, SchemeSystemd):
+		return openSystemd(strings.TrimPrefix(addr, SchemeSystemd))
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("Open: %w", err)
+	}
+
+	return ln, nil
+}
+
+// CloseUnused closes systemd sockets which were not taken by Open and returns their names.
+func CloseUnused() []string {
+	inherited.mu.Lock()
+	defer inherited.mu.Unlock()
+
+	names := make([]string, 0)
+
+	for i, f := range inherited.files {
+		if f == nil {
+			continue
+		}
+
+		names = append(names, f.Name())
+		f.Close()
+
+		inherited.files[i] = nil
+	}
+
+	return names
+}
+
+func openUnix(path string, mode fs.FileMode) (net.Listener, error) {
+	if path == "" {
+		return nil, fmt.Errorf("openUnix: %w", ErrEmptySocketPath)
+	}
+
+	if err := removeStale(path); err != nil {
+		return nil, fmt.Errorf("openUnix: %w", err)
+	}
+
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("openUnix: %w", err)
+	}
+
+	// файл удаляет unixListener.Close, только если он всё ещё наш.
+	ln.SetUnlinkOnClose(false)
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("openUnix: %w", err)
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		os.Remove(path)
+
+		return nil, fmt.Errorf("openUnix: %w", err)
+	}
+
+	return &unixListener{UnixListener: ln, path: path, file: fi}, nil
+}
+
+// removeStale removes socket file left after crash. Socket accepting connections belongs
+// to running instance and is kept: ErrSocketInUse is returned.
+func removeStale(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("removeStale: %w", err)
+	}
+
+	if fi.Mode()&fs.ModeSocket == 0 {
+		// не сокет, net.Listen вернёт понятную ошибку.
+		return nil
+	}
+
+	conn, err := net.DialTimeout("unix", path, staleDialTimeout)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("removeStale %s: %w", path, ErrSocketInUse)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("removeStale: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removeStale: %w", err)
+	}
+
+	return nil
+}
+
+// unixListener removes socket file on close unless it was replaced by another instance.
+type unixListener struct {
+	*net.UnixListener
+	file fs.FileInfo
+	path string
+}
+
+// Close closes listener and removes its socket file.
+func (ul *unixListener) Close() error {
+	err := ul.UnixListener.Close()
+
+	if fi, serr := os.Lstat(ul.path); serr == nil && os.SameFile(fi, ul.file) {
+		os.Remove(ul.path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Close: %w", err)
+	}
+
+	return nil
+}
+
+func openSystemd(name string) (net.Listener, error) {
+	inherited.once.Do(func() { inherited.files = systemdFiles(listenFDsStart) })
+
+	inherited.mu.Lock()
+	defer inherited.mu.Unlock()
+
+	for i, f := range inherited.files {
+		if f == nil || (name != "" && f.Name() != name) {
+			continue
+		}
+
+		ln, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("openSystemd %s: %w", f.Name(), err)
+		}
+
+		// FileListener работает с копией дескриптора.
+		f.Close()
+
+		inherited.files[i] = nil
+
+		return ln, nil
+	}
+
+	return nil, fmt.Errorf("openSystemd %q: %w", name, ErrNoSystemdSocket)
+}
+
+// systemdFiles returns sockets passed by systemd and unsets its envs, so child processes don't take them.
+// Socket without LISTEN_FDNAMES entry is named by its descriptor number.
+func systemdFiles(firstFD int) []*os.File {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	files := make([]*os.File, 0, count)
+
+	for i := range count {
+		fd := firstFD + i
+
+		name := strconv.Itoa(fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		files = append(files, os.NewFile(uintptr(fd), name))
+	}
+
+	return files
+}
@@ -0,0 +1,123 @@
+package listener
+
+import (
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_unix(t *testing.T) {
+	// путь к сокету ограничен ~100 символами, t.TempDir бывает длиннее.
+	dir, err := os.MkdirTemp("", "sock")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "app.sock")
+
+	// устаревший сокет от прошлого запуска заменяется.
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	ln, err := Open(SchemeUnix+path, 0o600)
+	require.NoError(t, err)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm())
+
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	conn.Close()
+
+	require.NoError(t, ln.Close())
+	assert.NoFileExists(t, path, "socket is removed on close")
+
+	_, err = Open(SchemeUnix, DefaultSocketMode)
+	assert.ErrorIs(t, err, ErrEmptySocketPath)
+}
+
+func TestOpen_unixInUse(t *testing.T) {
+	dir, err := os.MkdirTemp("", "sock")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "app.sock")
+
+	running, err := Open(SchemeUnix+path, DefaultSocketMode)
+	require.NoError(t, err)
+
+	// второй экземпляр не отбирает сокет у работающего.
+	_, err = Open(SchemeUnix+path, DefaultSocketMode)
+	require.ErrorIs(t, err, ErrSocketInUse)
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err, "running instance still accepts connections")
+	conn.Close()
+
+	// сокет, заменённый другим экземпляром, не удаляется при остановке.
+	require.NoError(t, os.Remove(path))
+
+	other, err := Open(SchemeUnix+path, DefaultSocketMode)
+	require.NoError(t, err)
+
+	require.NoError(t, running.Close())
+	assert.FileExists(t, path, "socket of other instance is kept")
+
+	require.NoError(t, other.Close())
+	assert.NoFileExists(t, path)
+}
+
+func TestOpen_systemd(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	f, err := tcp.(*net.TCPListener).File()
+	require.NoError(t, err)
+	tcp.Close()
+
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "1")
+	t.Setenv("LISTEN_FDNAMES", "public")
+
+	// настоящие сокеты systemd в тесте не нужны.
+	inherited.once.Do(func() {})
+	inherited.files = systemdFiles(int(f.Fd()))
+	t.Cleanup(func() { inherited.files = nil })
+
+	assert.Empty(t, os.Getenv("LISTEN_FDS"), "envs are not passed to child processes")
+
+	_, err = Open(SchemeSystemd+"admin", DefaultSocketMode)
+	require.ErrorIs(t, err, ErrNoSystemdSocket)
+
+	ln, err := Open(SchemeSystemd+"public", DefaultSocketMode)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	assert.Equal(t, "tcp", ln.Addr().Network())
+
+	_, err = Open(SchemeSystemd, DefaultSocketMode)
+	require.ErrorIs(t, err, ErrNoSystemdSocket, "socket is taken once")
+	assert.Empty(t, CloseUnused())
+}
+
+func Test_systemdFiles_otherPID(t *testing.T) {
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "2")
+
+	assert.Empty(t, systemdFiles(listenFDsStart))
+}
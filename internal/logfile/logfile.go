@@ -0,0 +1,389 @@
+// Package logfile provide append-only log file with size and time based rotation,
+// gzip compression of rotated files and retention by count and age.
+package logfile
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat sortable time suffix of rotated files.
+const backupTimeFormat = "20060102T150405.000"
+
+// Options of rotation and retention. Zero values disable corresponding limit.
+type Options struct {
+	// MaxSize size in bytes after which file is rotated.
+	MaxSize int64
+	// RotateInterval period after which file is rotated.
+	RotateInterval time.Duration
+	// MaxBackups count of rotated files kept.
+	MaxBackups int
+	// MaxAge age of rotated files kept.
+	MaxAge time.Duration
+	// Compress gzip rotated files.
+	Compress bool
+}
+
+// File rotating log file, safe for concurrent use.
+type File struct {
+	openedAt time.Time
+	file     *os.File
+	now      func() time.Time
+	path     string
+	opts     Options
+	size     int64
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	cleanMu  sync.Mutex
+}
+
+// Open opens or creates file at path and its directory.
+func Open(path string, opts Options) (*File, error) {
+	f := &File{path: filepath.Clean(path), opts: opts, now: time.Now}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0750); err != nil {
+		return nil, fmt.Errorf("Open: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Name provide path of active file.
+func (f *File) Name() string {
+	return f.path
+}
+
+// Write implement io.Writer interface. Rotates file before write if limits are exceeded.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("(f *File) Write: %w", os.ErrClosed)
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	if err != nil {
+		return n, fmt.Errorf("(f *File) Write: %w", err)
+	}
+
+	return n, nil
+}
+
+// Sync implement zapcore.WriteSyncer interface.
+func (f *File) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	if err := f.file.Sync(); err != nil {
+		return fmt.Errorf("(f *File) Sync: %w", err)
+	}
+
+	return nil
+}
+
+// Rotate moves active file to backup and opens a new one.
+func (f *File) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rotate()
+}
+
+// Reopen closes and opens file at the same path, e.g. after external logrotate moved it.
+func (f *File) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.close(); err != nil {
+		return err
+	}
+
+	return f.open()
+}
+
+// Close closes active file and waits for background compression.
+func (f *File) Close() error {
+	f.mu.Lock()
+	err := f.close()
+	f.mu.Unlock()
+
+	f.wg.Wait()
+
+	return err
+}
+
+// backup rotated file with parsed name.
+type backup struct {
+	rotatedAt time.Time
+	path      string
+	counter   int
+}
+
+// Backups provide rotated files oldest first. Only names written by rotation are listed,
+// so other files sharing the prefix are never touched by retention.
+func (f *File) Backups() ([]string, error) {
+	dir := filepath.Dir(f.path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("(f *File) Backups: %w", err)
+	}
+
+	parsed := make([]backup, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if rotatedAt, counter, ok := f.parseBackup(entry.Name()); ok {
+			parsed = append(parsed, backup{rotatedAt: rotatedAt, counter: counter, path: filepath.Join(dir, entry.Name())})
+		}
+	}
+
+	slices.SortFunc(parsed, func(a, b backup) int {
+		if c := a.rotatedAt.Compare(b.rotatedAt); c != 0 {
+			return c
+		}
+
+		return a.counter - b.counter
+	})
+
+	backups := make([]string, 0, len(parsed))
+	for _, b := range parsed {
+		backups = append(backups, b.path)
+	}
+
+	return backups, nil
+}
+
+func (f *File) shouldRotate(size int64) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.opts.MaxSize > 0 && f.size+size > f.opts.MaxSize {
+		return true
+	}
+
+	return f.opts.RotateInterval > 0 && f.now().Sub(f.openedAt) >= f.opts.RotateInterval
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("(f *File) open: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(fmt.Errorf("(f *File) open: %w", err), file.Close())
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+
+	return nil
+}
+
+func (f *File) close() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	if err != nil {
+		return fmt.Errorf("(f *File) close: %w", err)
+	}
+
+	return nil
+}
+
+func (f *File) rotate() error {
+	if err := f.close(); err != nil {
+		return err
+	}
+
+	backup := f.backupName(f.now())
+
+	if err := os.Rename(f.path, backup); err != nil {
+		return errors.Join(fmt.Errorf("(f *File) rotate: %w", err), f.open())
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+
+	go func() {
+		defer f.wg.Done()
+
+		if f.opts.Compress {
+			// ошибка сжатия не критична: файл остаётся несжатым и попадает под retention.
+			_ = compress(backup)
+		}
+
+		f.cleanup()
+	}()
+
+	return nil
+}
+
+// backupPrefix provide name prefix and extension of rotated files, e.g. "audit-" and ".log".
+func (f *File) backupPrefix() (string, string) {
+	base := filepath.Base(f.path)
+	ext := filepath.Ext(base)
+
+	return strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backupName provide not used path of file rotated at t. Files rotated in the same millisecond
+// get counter, e.g. "audit-20261018T100000.000-1.log", so rename never overwrites backup.
+func (f *File) backupName(t time.Time) string {
+	prefix, ext := f.backupPrefix()
+	stamp := t.Format(backupTimeFormat)
+
+	for n := 0; ; n++ {
+		name := prefix + stamp + ext
+		if n > 0 {
+			name = prefix + stamp + "-" + strconv.Itoa(n) + ext
+		}
+
+		path := filepath.Join(filepath.Dir(f.path), name)
+		if !exists(path) && !exists(path+".gz") {
+			return path
+		}
+	}
+}
+
+// parseBackup provide rotation time and counter of backup name, false if name wasn't made by rotation.
+func (f *File) parseBackup(name string) (time.Time, int, bool) {
+	prefix, ext := f.backupPrefix()
+
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+
+	if rest, ok = strings.CutSuffix(strings.TrimSuffix(rest, ".gz"), ext); !ok {
+		return time.Time{}, 0, false
+	}
+
+	stamp, counter := rest, 0
+
+	// в формате времени нет "-", после него может быть только счётчик.
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		n, err := strconv.Atoi(rest[i+1:])
+		if err != nil || n <= 0 || strconv.Itoa(n) != rest[i+1:] {
+			return time.Time{}, 0, false
+		}
+
+		stamp, counter = rest[:i], n
+	}
+
+	rotatedAt, err := time.Parse(backupTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	return rotatedAt, counter, true
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// cleanup removes rotated files exceeding MaxBackups or MaxAge.
+func (f *File) cleanup() {
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+
+	f.cleanMu.Lock()
+	defer f.cleanMu.Unlock()
+
+	backups, err := f.Backups()
+	if err != nil {
+		return
+	}
+
+	for i, backup := range backups {
+		expired := f.opts.MaxBackups > 0 && i < len(backups)-f.opts.MaxBackups
+
+		if !expired && f.opts.MaxAge > 0 {
+			if info, err := os.Stat(backup); err == nil {
+				expired = f.now().Sub(info.ModTime()) > f.opts.MaxAge
+			}
+		}
+
+		if expired {
+			_ = os.Remove(backup)
+		}
+	}
+}
+
+// compress replaces file with its gzip copy.
+func compress(path string) error {
+	src, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+
+	dst, err := os.OpenFile(filepath.Clean(tmp), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	_, err = io.Copy(gz, src)
+	if err = errors.Join(err, gz.Close(), dst.Close()); err != nil {
+		return errors.Join(fmt.Errorf("compress: %w", err), os.Remove(tmp))
+	}
+
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package logfile
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Rotation(t *testing.T) {
+	record := strings.Repeat("x", 9) + "\n"
+
+	tests := []struct {
+		name        string
+		opts        Options
+		advance     time.Duration
+		writes      int
+		wantBackups int
+	}{
+		{name: "no limits", writes: 5},
+		{name: "by size", opts: Options{MaxSize: 25}, writes: 5, wantBackups: 2},
+		{name: "by time", opts: Options{RotateInterval: time.Hour}, advance: time.Hour, writes: 3, wantBackups: 2},
+		{name: "max backups", opts: Options{MaxSize: 10, MaxBackups: 2}, writes: 5, wantBackups: 2},
+		{name: "compressed", opts: Options{MaxSize: 10, Compress: true}, writes: 3, wantBackups: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+			path := filepath.Join(t.TempDir(), "logs", "audit.log")
+
+			f, err := Open(path, tt.opts)
+			require.NoError(t, err)
+
+			f.now = func() time.Time { return now }
+			f.openedAt = now
+
+			for range tt.writes {
+				_, err := f.Write([]byte(record))
+				require.NoError(t, err)
+
+				// уникальное имя каждого архива
+				now = now.Add(tt.advance + time.Millisecond)
+			}
+
+			require.NoError(t, f.Close())
+
+			backups, err := f.Backups()
+			require.NoError(t, err)
+			require.Len(t, backups, tt.wantBackups)
+
+			total := readAll(t, path)
+			for _, backup := range backups {
+				assert.Equal(t, tt.opts.Compress, strings.HasSuffix(backup, ".log.gz"))
+				total += readAll(t, backup)
+			}
+
+			if tt.opts.MaxBackups == 0 {
+				assert.Equal(t, strings.Repeat(record, tt.writes), total, "no records lost on rotation")
+			}
+		})
+	}
+}
+
+func TestFile_Backups(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+
+	// файлы с тем же префиксом, не созданные ротацией.
+	foreign := []string{"audit-notes.log", "audit-20261018T100000.000-x.log", "audit-20261018T100000.000-01.log", "audit-1.log.gz"}
+	for _, name := range foreign {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("keep\n"), 0600))
+	}
+
+	f, err := Open(path, Options{MaxBackups: 2})
+	require.NoError(t, err)
+
+	f.now = func() time.Time { return now }
+
+	for i := range 4 {
+		_, err := f.Write([]byte(strconv.Itoa(i) + "\n"))
+		require.NoError(t, err)
+
+		// ротации в одну миллисекунду не перезаписывают друг друга.
+		require.NoError(t, f.Rotate())
+	}
+
+	require.NoError(t, f.Close())
+
+	backups, err := f.Backups()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "audit-20261018T100000.000-2.log"),
+		filepath.Join(dir, "audit-20261018T100000.000-3.log"),
+	}, backups)
+
+	assert.Equal(t, "2\n", readAll(t, backups[0]))
+	assert.Equal(t, "3\n", readAll(t, backups[1]))
+
+	for _, name := range foreign {
+		assert.FileExists(t, filepath.Join(dir, name), "retention removes only rotated files")
+	}
+}
+
+func TestFile_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	f, err := Open(path, Options{})
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	// внешний logrotate переносит файл и присылает SIGHUP
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, f.Reopen())
+
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, "first\n", readAll(t, path+".1"))
+	assert.Equal(t, "second\n", readAll(t, path))
+}
+
+func readAll(t *testing.T, path string) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	var r io.Reader = f
+
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+
+		r = gz
+	}
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(data)
+}
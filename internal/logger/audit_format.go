@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"go.uber.org/zap/zapcore"
+)
+
+// cefVendor and cefProduct identify the app in CEF header.
+const (
+	cefVendor  = "Pklerik"
+	cefProduct = "urlshortener"
+)
+
+// cefKeys maps audit fields to CEF extension keys.
+var cefKeys = map[string]string{
+	"action":     "act",
+	"user_id":    "suser",
+	"client_ip":  "src",
+	"url":        "request",
+	"request_id": "externalId",
+	"status":     "outcome",
+	"short_url":  "cs1",
+}
+
+// auditJSONEncoder encodes only fields: empty config has no time, level and message keys.
+var auditJSONEncoder = zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+
+// auditFormatter encodes audit entry to newline terminated record.
+type auditFormatter func(ent zapcore.Entry, fields []zapcore.Field) ([]byte, error)
+
+func newAuditFormatter(format string) auditFormatter {
+	switch format {
+	case audit.FormatCEF:
+		return formatCEF
+	case audit.FormatLogfmt:
+		return formatLogfmt
+	default:
+		return formatJSON
+	}
+}
+
+func formatJSON(ent zapcore.Entry, fields []zapcore.Field) ([]byte, error) {
+	buf, err := auditJSONEncoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, fmt.Errorf("formatJSON: %w", err)
+	}
+	defer buf.Free()
+
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// formatLogfmt encodes fields as `key=value` pairs.
+func formatLogfmt(_ zapcore.Entry, fields []zapcore.Field) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for i, f := range auditFieldValues(fields) {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(f.key)
+		buf.WriteByte('=')
+
+		if f.value == "" || strings.ContainsAny(f.value, " =\"\\\t\r\n") {
+			buf.WriteString(strconv.Quote(f.value))
+		} else {
+			buf.WriteString(f.value)
+		}
+	}
+
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// formatCEF encodes fields as ArcSight Common Event Format record.
+func formatCEF(ent zapcore.Entry, fields []zapcore.Field) ([]byte, error) {
+	values := auditFieldValues(fields)
+
+	var action, version string
+
+	ext := make([]string, 0, len(values)+1)
+
+	for _, f := range values {
+		key, ok := cefKeys[f.key]
+		if !ok {
+			key = f.key
+		}
+
+		switch f.key {
+		case "v":
+			version = f.value
+			continue
+		case "action":
+			action = f.value
+		case "ts":
+			// rt ожидает миллисекунды.
+			key, f.value = "rt", f.value+"000"
+		}
+
+		ext = append(ext, key+"="+cefEscapeExt(f.value))
+		if key == "cs1" {
+			ext = append(ext, "cs1Label=short_url")
+		}
+	}
+
+	record := fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s\n",
+		cefVendor, cefProduct, cefEscapeHeader(version), cefEscapeHeader(action), cefEscapeHeader(action),
+		cefSeverity(ent.Level), strings.Join(ext, " "))
+
+	return []byte(record), nil
+}
+
+func cefSeverity(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.ErrorLevel:
+		return 8
+	case level == zapcore.WarnLevel:
+		return 5
+	default:
+		return 3
+	}
+}
+
+func cefEscapeHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`).Replace(s)
+}
+
+func cefEscapeExt(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+type auditFieldValue struct {
+	key   string
+	value string
+}
+
+// auditFieldValues provide fields as strings keeping their order.
+func auditFieldValues(fields []zapcore.Field) []auditFieldValue {
+	enc := zapcore.NewMapObjectEncoder()
+	values := make([]auditFieldValue, 0, len(fields))
+
+	for _, f := range fields {
+		f.AddTo(enc)
+		values = append(values, auditFieldValue{key: f.Key, value: fmt.Sprint(enc.Fields[f.Key])})
+	}
+
+	return values
+}
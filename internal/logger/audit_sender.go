@@ -0,0 +1,463 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/batcher"
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/Pklerik/urlshortener/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	auditRequestTimeout = 5 * time.Second
+	auditRetryBackoff   = 200 * time.Millisecond
+	auditMaxBackoff     = 5 * time.Second
+)
+
+var (
+	// ErrAuditUnexpectedStatus returned when audit endpoint responds with non 2xx status.
+	ErrAuditUnexpectedStatus = errors.New("unexpected audit endpoint status")
+	// ErrAuditSpillFull returned when entries don't fit into spill file size limit.
+	ErrAuditSpillFull = errors.New("audit spill file is full")
+)
+
+// AuditMetrics counters of remote audit delivery, published at /debug/vars.
+var AuditMetrics = expvar.NewMap("audit_sender")
+
+// AuditSender asynchronously delivers audit entries to remote endpoint in batches.
+// Write never blocks: entries which don't fit into the queue are spilled to disk or dropped.
+type AuditSender struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	client     *http.Client
+	spill      *spillFile
+	batcher    *batcher.Batcher[[]byte]
+	lastErr    atomic.Pointer[error]
+	url        string
+	batchSize  int
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewAuditSender creates AuditSender for url and starts its delivery loop.
+func NewAuditSender(url string, auditConf *audit.Audit) *AuditSender {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	as := &AuditSender{
+		ctx:        ctx,
+		cancel:     cancel,
+		client:     auditConf.GetURLWriter().GetClient(),
+		url:        url,
+		batchSize:  auditConf.GetBatchSize(),
+		maxRetries: auditConf.GetMaxRetries(),
+		backoff:    auditRetryBackoff,
+	}
+
+	if spillPath := auditConf.GetSpillFilePath(); spillPath != "" {
+		as.spill = newSpillFile(filepath.Clean(spillPath), auditConf.GetSpillMaxSize())
+	}
+
+	as.batcher = batcher.New(auditConf.GetQueueSize(), as.batchSize, auditConf.GetFlushInterval(),
+		as.send, batcher.WithTick(as.replaySpill))
+
+	return as
+}
+
+// Write implement zapcore.WriteSyncer interface. Message is copied because zap reuses buffers.
+func (as *AuditSender) Write(message []byte) (int, error) {
+	entry := bytes.Clone(bytes.TrimSpace(message))
+	if len(entry) == 0 {
+		return len(message), nil
+	}
+
+	if as.batcher.Add(entry) {
+		AuditMetrics.Add("queued", 1)
+	} else {
+		as.overflow([][]byte{entry})
+	}
+
+	return len(message), nil
+}
+
+// WriteRecord implement AuditSink interface.
+func (as *AuditSender) WriteRecord(_ zapcore.Level, record []byte) error {
+	_, err := as.Write(record)
+
+	return err
+}
+
+// QueueLen returns count of entries waiting for delivery.
+func (as *AuditSender) QueueLen() int {
+	return as.batcher.Len()
+}
+
+// Healthy returns error of the last failed delivery, nil after successful one.
+func (as *AuditSender) Healthy() error {
+	if errp := as.lastErr.Load(); errp != nil {
+		return fmt.Errorf("(as *AuditSender) Healthy: %w", *errp)
+	}
+
+	return nil
+}
+
+// Sync implement zapcore.WriteSyncer interface. Sends all queued entries.
+func (as *AuditSender) Sync() error {
+	as.batcher.Flush()
+
+	return nil
+}
+
+// Close sends queued entries and stops delivery loop.
+// If ctx expires first, undelivered entries are spilled or dropped.
+func (as *AuditSender) Close(ctx context.Context) error {
+	defer as.cancel()
+
+	if err := as.batcher.Close(ctx); err != nil {
+		// прерываем повторы, оставшиеся записи уходят в overflow.
+		as.cancel()
+		<-as.batcher.Done()
+
+		return fmt.Errorf("(as *AuditSender) Close: %w", err)
+	}
+
+	return nil
+}
+
+// send delivers batch collected by batcher. Failed batch goes to overflow.
+func (as *AuditSender) send(batch [][]byte) {
+	if err := as.deliver(batch, as.maxRetries); err != nil {
+		as.overflow(batch)
+	}
+}
+
+// deliver sends batch with retries.
+func (as *AuditSender) deliver(batch [][]byte, retries int) error {
+	delay := as.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := as.post(batch)
+		if err == nil {
+			AuditMetrics.Add("sent", int64(len(batch)))
+			as.lastErr.Store(nil)
+
+			return nil
+		}
+
+		if attempt >= retries || as.ctx.Err() != nil {
+			Sugar.Warnf("Error sending %d audit entries to <%s>: %v", len(batch), as.url, err)
+			as.lastErr.Store(&err)
+			AuditMetrics.Add("failed_batches", 1)
+
+			return err
+		}
+
+		AuditMetrics.Add("retries", 1)
+
+		select {
+		case <-time.After(delay):
+		case <-as.ctx.Done():
+		}
+
+		delay = min(delay*2, auditMaxBackoff)
+	}
+}
+
+// post sends batch as JSON array.
+func (as *AuditSender) post(batch [][]byte) (err error) {
+	body := make([]byte, 0, len(batch)*128)
+	body = append(body, '[')
+	body = append(body, bytes.Join(batch, []byte(","))...)
+	body = append(body, ']')
+
+	ctx, cancel := context.WithTimeout(as.ctx, auditRequestTimeout)
+	defer cancel()
+
+	// пачка собрана из разных запросов, поэтому отправка начинает свою трассу.
+	ctx, span := tracing.Start(ctx, "audit.Send", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("audit.entries", len(batch))))
+	defer tracing.End(span, &err)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, as.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("(as *AuditSender) post: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := as.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("(as *AuditSender) post: %w", err)
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			Sugar.Errorf("Error closing response body: %v", err)
+		}
+	}()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("(as *AuditSender) post: %w: %d", ErrAuditUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// overflow spills entries to disk if configured, otherwise drops them.
+func (as *AuditSender) overflow(entries [][]byte) {
+	if as.spill != nil {
+		err := as.spill.append(entries)
+		if err == nil {
+			AuditMetrics.Add("spilled", int64(len(entries)))
+			return
+		}
+
+		if errors.Is(err, ErrAuditSpillFull) {
+			AuditMetrics.Add("spill_full", int64(len(entries)))
+		} else {
+			Sugar.Errorf("Error writing audit spill file: %v", err)
+		}
+	}
+
+	AuditMetrics.Add("dropped", int64(len(entries)))
+}
+
+// replaySpill resends spilled entries without retries, so unavailable endpoint doesn't block the loop.
+// Entries are removed from spill file only after endpoint accepted them.
+func (as *AuditSender) replaySpill() {
+	if as.spill == nil {
+		return
+	}
+
+	for as.ctx.Err() == nil {
+		entries, next, err := as.spill.next(as.batchSize)
+		if err != nil {
+			Sugar.Errorf("Error reading audit spill file: %v", err)
+			return
+		}
+
+		if next == as.spill.delivered() {
+			return
+		}
+
+		if len(entries) > 0 && as.deliver(entries, 0) != nil {
+			return
+		}
+
+		if err := as.spill.ack(next); err != nil {
+			Sugar.Errorf("Error updating audit spill file: %v", err)
+			return
+		}
+	}
+}
+
+// spillFile keeps audit entries as JSON lines. Entries are read from offset of the first
+// not delivered entry, offset is saved next to the file, so delivery resumes after restart.
+// File is truncated when all entries are delivered.
+type spillFile struct {
+	path string
+	// maxSize limit of not delivered entries size, 0 if unlimited.
+	maxSize int64
+	offset  int64
+	mu      sync.Mutex
+}
+
+func newSpillFile(path string, maxSize int64) *spillFile {
+	sf := &spillFile{path: path, maxSize: maxSize}
+
+	if data, err := os.ReadFile(sf.offsetPath()); err == nil {
+		offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if fi, serr := os.Stat(path); err == nil && serr == nil && offset >= 0 && offset <= fi.Size() {
+			sf.offset = offset
+		}
+	}
+
+	return sf
+}
+
+func (sf *spillFile) offsetPath() string {
+	return sf.path + ".offset"
+}
+
+func (sf *spillFile) append(entries [][]byte) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		buf.Write(entry)
+		buf.WriteByte('\n')
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	f, err := os.OpenFile(sf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("(sf *spillFile) append: %w", err)
+	}
+
+	if sf.maxSize > 0 {
+		fi, err := f.Stat()
+		if err != nil {
+			return errors.Join(fmt.Errorf("(sf *spillFile) append: %w", err), f.Close())
+		}
+
+		if fi.Size()-sf.offset+int64(buf.Len()) > sf.maxSize {
+			return errors.Join(fmt.Errorf("(sf *spillFile) append: %w", ErrAuditSpillFull), f.Close())
+		}
+	}
+
+	_, err = f.Write(buf.Bytes())
+
+	return errors.Join(err, f.Close())
+}
+
+// delivered returns offset of the first not delivered entry.
+func (sf *spillFile) delivered() int64 {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	return sf.offset
+}
+
+// next reads up to limit not delivered entries and returns them with offset after the last one.
+// Entries stay in file until ack.
+func (sf *spillFile) next(limit int) ([][]byte, int64, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	f, err := os.Open(sf.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, sf.offset, nil
+	}
+
+	if err != nil {
+		return nil, sf.offset, fmt.Errorf("(sf *spillFile) next: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(sf.offset, io.SeekStart); err != nil {
+		return nil, sf.offset, fmt.Errorf("(sf *spillFile) next: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	entries := make([][]byte, 0, limit)
+	next := sf.offset
+
+	for len(entries) < limit {
+		line, err := reader.ReadBytes('\n')
+		next += int64(len(line))
+
+		if entry := bytes.TrimSpace(line); len(entry) > 0 {
+			entries = append(entries, entry)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, sf.offset, fmt.Errorf("(sf *spillFile) next: %w", err)
+		}
+	}
+
+	return entries, next, nil
+}
+
+// ack marks entries before offset as delivered. Fully delivered file is truncated,
+// file with large delivered head is compacted.
+func (sf *spillFile) ack(offset int64) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	fi, err := os.Stat(sf.path)
+	if err != nil {
+		return fmt.Errorf("(sf *spillFile) ack: %w", err)
+	}
+
+	sf.offset = offset
+
+	switch {
+	case offset >= fi.Size():
+		sf.offset = 0
+
+		if err := os.Truncate(sf.path, 0); err != nil {
+			return fmt.Errorf("(sf *spillFile) ack: %w", err)
+		}
+	case sf.maxSize > 0 && offset >= sf.maxSize/2:
+		if err := sf.compact(); err != nil {
+			return fmt.Errorf("(sf *spillFile) ack: %w", err)
+		}
+	default:
+		if err := os.WriteFile(sf.offsetPath(), strconv.AppendInt(nil, offset, 10), 0600); err != nil {
+			return fmt.Errorf("(sf *spillFile) ack: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := os.Remove(sf.offsetPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("(sf *spillFile) ack: %w", err)
+	}
+
+	return nil
+}
+
+// compact rewrites not delivered entries to new file, so delivered head doesn't take disk space.
+func (sf *spillFile) compact() error {
+	src, err := os.Open(sf.path)
+	if err != nil {
+		return fmt.Errorf("(sf *spillFile) compact: %w", err)
+	}
+	defer src.Close()
+
+	if _, err := src.Seek(sf.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("(sf *spillFile) compact: %w", err)
+	}
+
+	tmp := sf.path + ".tmp"
+
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("(sf *spillFile) compact: %w", err)
+	}
+
+	_, err = io.Copy(dst, src)
+	if err = errors.Join(err, dst.Close()); err != nil {
+		return errors.Join(fmt.Errorf("(sf *spillFile) compact: %w", err), os.Remove(tmp))
+	}
+
+	// сохранённое смещение относится к старому файлу: после сбоя записи лучше отправить повторно, чем потерять.
+	if err := os.Remove(sf.offsetPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Join(fmt.Errorf("(sf *spillFile) compact: %w", err), os.Remove(tmp))
+	}
+
+	if err := os.Rename(tmp, sf.path); err != nil {
+		return fmt.Errorf("(sf *spillFile) compact: %w", err)
+	}
+
+	sf.offset = 0
+
+	return nil
+}
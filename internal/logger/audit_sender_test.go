@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"context"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditMetric(name string) int64 {
+	if v, ok := AuditMetrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+
+	return 0
+}
+
+type auditCollector struct {
+	entries     []map[string]any
+	contentType string
+	mu          sync.Mutex
+}
+
+func (ac *auditCollector) handler(t *testing.T, up *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var batch []map[string]any
+		assert.NoError(t, json.Unmarshal(body, &batch))
+
+		ac.mu.Lock()
+		ac.entries = append(ac.entries, batch...)
+		ac.contentType = r.Header.Get("Content-Type")
+		ac.mu.Unlock()
+	}
+}
+
+func (ac *auditCollector) len() int {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	return len(ac.entries)
+}
+
+func TestAuditSender_Batching(t *testing.T) {
+	require.NoError(t, Initialize("ERROR"))
+
+	var up atomic.Bool
+	up.Store(true)
+
+	collector := &auditCollector{}
+	srv := httptest.NewServer(collector.handler(t, &up))
+	defer srv.Close()
+
+	as := NewAuditSender(srv.URL, &audit.Audit{BatchSize: 2, FlushInterval: 10})
+
+	for _, action := range []string{"shorten", "follow", "delete"} {
+		_, err := as.Write([]byte(`{"action":"` + action + `"}` + "\n"))
+		require.NoError(t, err)
+	}
+
+	assert.Eventually(t, func() bool { return collector.len() == 2 }, time.Second, 10*time.Millisecond, "full batch is sent without waiting for flush")
+
+	require.NoError(t, as.Close(context.Background()))
+	assert.Equal(t, 3, collector.len(), "close flushes partial batch")
+	assert.Equal(t, "application/json", collector.contentType)
+	assert.Equal(t, "delete", collector.entries[2]["action"])
+}
+
+func TestAuditSender_EndpointDown(t *testing.T) {
+	require.NoError(t, Initialize("ERROR"))
+
+	var up atomic.Bool
+
+	collector := &auditCollector{}
+	srv := httptest.NewServer(collector.handler(t, &up))
+	defer srv.Close()
+
+	t.Run("write_never_blocks", func(t *testing.T) {
+		dropped := auditMetric("dropped")
+		as := NewAuditSender(srv.URL, &audit.Audit{QueueSize: 1, BatchSize: 1, MaxRetries: 5})
+
+		start := time.Now()
+		for range 100 {
+			_, err := as.Write([]byte(`{"action":"follow"}`))
+			require.NoError(t, err)
+		}
+
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+		assert.Greater(t, auditMetric("dropped"), dropped)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, as.Close(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("spill_and_replay", func(t *testing.T) {
+		spillPath := filepath.Join(t.TempDir(), "audit_spill.json")
+		as := NewAuditSender(srv.URL, &audit.Audit{
+			SpillFilePath: spillPath,
+			BatchSize:     10, MaxRetries: -1, FlushInterval: 0.05,
+		})
+
+		for range 3 {
+			_, err := as.Write([]byte(`{"action":"shorten"}`))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, as.Sync())
+		assert.FileExists(t, spillPath)
+		assert.ErrorIs(t, as.Healthy(), ErrAuditUnexpectedStatus)
+
+		up.Store(true)
+
+		assert.Eventually(t, func() bool { return collector.len() == 3 }, 2*time.Second, 10*time.Millisecond, "spilled entries are replayed")
+		require.NoError(t, as.Close(context.Background()))
+		assert.NoError(t, as.Healthy(), "sender is healthy after successful delivery")
+		assert.NoFileExists(t, spillPath+".offset")
+	})
+}
+
+func TestSpillFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit_spill.json")
+	entry := func(n int) []byte { return []byte(`{"n":` + strconv.Itoa(n) + `}`) }
+
+	sf := newSpillFile(path, 0)
+	require.NoError(t, sf.append([][]byte{entry(1), entry(2), entry(3)}))
+
+	entries, next, err := sf.next(2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{entry(1), entry(2)}, entries)
+
+	entries, _, err = sf.next(2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{entry(1), entry(2)}, entries, "not acknowledged entries are read again")
+
+	require.NoError(t, sf.ack(next))
+
+	// после перезапуска доставка продолжается с сохранённого смещения.
+	sf = newSpillFile(path, 0)
+	require.NoError(t, sf.append([][]byte{entry(4)}))
+
+	entries, next, err = sf.next(10)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{entry(3), entry(4)}, entries)
+
+	require.NoError(t, sf.ack(next))
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Zero(t, fi.Size(), "delivered file is truncated")
+	assert.NoFileExists(t, sf.offsetPath())
+
+	t.Run("size limit", func(t *testing.T) {
+		sf := newSpillFile(filepath.Join(t.TempDir(), "audit_spill.json"), 16)
+		require.NoError(t, sf.append([][]byte{entry(1), entry(2)}))
+		require.ErrorIs(t, sf.append([][]byte{entry(3)}), ErrAuditSpillFull)
+
+		entries, next, err := sf.next(1)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.NoError(t, sf.ack(next))
+
+		require.NoError(t, sf.append([][]byte{entry(3)}), "delivered entries free the space")
+
+		entries, _, err = sf.next(10)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{entry(2), entry(3)}, entries)
+
+		data, err := os.ReadFile(sf.path)
+		require.NoError(t, err)
+		assert.Equal(t, "{\"n\":2}\n{\"n\":3}\n", string(data), "delivered head is compacted")
+	})
+}
@@ -0,0 +1,393 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/auditchain"
+	"github.com/Pklerik/urlshortener/internal/batcher"
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/Pklerik/urlshortener/internal/logfile"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// auditSocketTimeout limits socket writes, so hung collector doesn't stop the queue.
+	auditSocketTimeout = 500 * time.Millisecond
+	syslogAppName      = "urlshortener"
+	syslogMsgID        = "audit"
+)
+
+// syslogFacilities RFC 5424 facility codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "daemon": 3, "auth": 4, "syslog": 5, "authpriv": 10,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// AuditSink destination of encoded audit records.
+type AuditSink interface {
+	WriteRecord(level zapcore.Level, record []byte) error
+	Sync() error
+	Close(ctx context.Context) error
+}
+
+// auditCore zapcore.Core writing formatted entries to AuditSink.
+type auditCore struct {
+	zapcore.LevelEnabler
+	sink   AuditSink
+	format auditFormatter
+	fields []zapcore.Field
+}
+
+func newAuditSinkCore(sinkConf audit.Sink, dataDir string, auditConf *audit.Audit) (zapcore.Core, AuditSink, error) {
+	if err := sinkConf.Valid(); err != nil {
+		return nil, nil, fmt.Errorf("newAuditSinkCore: %w", err)
+	}
+
+	level, err := zapcore.ParseLevel(sinkConf.GetLevel())
+	if err != nil {
+		return nil, nil, fmt.Errorf("newAuditSinkCore: %w", err)
+	}
+
+	sink, err := openAuditSink(sinkConf, dataDir, auditConf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("newAuditSinkCore: %w", err)
+	}
+
+	return &auditCore{LevelEnabler: level, sink: sink, format: newAuditFormatter(sinkConf.GetFormat())}, sink, nil
+}
+
+// With implement zapcore.Core interface.
+func (c *auditCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(slices.Clone(c.fields), fields...)
+
+	return &clone
+}
+
+// Check implement zapcore.Core interface.
+func (c *auditCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+// Write implement zapcore.Core interface.
+func (c *auditCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	record, err := c.format(ent, append(slices.Clone(c.fields), fields...))
+	if err != nil {
+		return fmt.Errorf("(c *auditCore) Write: %w", err)
+	}
+
+	if err := c.sink.WriteRecord(ent.Level, record); err != nil {
+		return fmt.Errorf("(c *auditCore) Write: %w", err)
+	}
+
+	return nil
+}
+
+// Sync implement zapcore.Core interface.
+func (c *auditCore) Sync() error {
+	return c.sink.Sync()
+}
+
+func openAuditSink(sinkConf audit.Sink, dataDir string, auditConf *audit.Audit) (AuditSink, error) {
+	switch sinkConf.Type {
+	case audit.SinkFile:
+		return openFileSink(sinkConf, dataDir, auditConf)
+	case audit.SinkHTTP:
+		return NewAuditSender(sinkConf.GetAddress(), auditConf), nil
+	case audit.SinkSyslog:
+		facility, ok := syslogFacilities[sinkConf.GetFacility()]
+		if !ok {
+			return nil, fmt.Errorf("openAuditSink: %w: unknown syslog facility %q", audit.ErrInvalidSink, sinkConf.Facility)
+		}
+
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "-"
+		}
+
+		return newSocketSink(sinkConf.GetNetwork(), sinkConf.GetAddress(), syslogFrame(facility, hostname), auditConf), nil
+	case audit.SinkUnix:
+		return newSocketSink("unix", sinkConf.GetAddress(),
+			func(_ zapcore.Level, record []byte) []byte { return record }, auditConf), nil
+	default:
+		return &stdoutSink{}, nil
+	}
+}
+
+// fileSink writes records to rotating file, hash chained if enabled.
+type fileSink struct {
+	w    zapcore.WriteSyncer
+	file *logfile.File
+}
+
+// openFileSink opens rotating audit file, relative path is resolved from dataDir.
+// Chain continues from the last record of active file or of the newest rotated file.
+func openFileSink(sinkConf audit.Sink, dataDir string, auditConf *audit.Audit) (*fileSink, error) {
+	fullPath := sinkConf.GetAddress()
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(dataDir, fullPath)
+	}
+
+	file, err := logfile.Open(fullPath, logfile.Options{
+		MaxSize:        auditConf.GetMaxSize(),
+		RotateInterval: auditConf.GetRotateInterval(),
+		MaxBackups:     auditConf.GetMaxBackups(),
+		MaxAge:         auditConf.GetMaxAge(),
+		Compress:       auditConf.GetCompress(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openFileSink: %w", err)
+	}
+
+	Sugar.Infof("Audit log file initialized: %s", file.Name())
+
+	if !auditConf.GetHashChain() {
+		return &fileSink{w: file, file: file}, nil
+	}
+
+	if sinkConf.GetFormat() != audit.FormatJSON {
+		Sugar.Warnf("Audit hash chain supports only json format, %s is not chained", file.Name())
+		return &fileSink{w: file, file: file}, nil
+	}
+
+	state, err := auditchain.LastState(file.Name())
+	if err == nil && state.Seq == 0 {
+		if backups, _ := file.Backups(); len(backups) > 0 {
+			state, err = auditchain.LastState(backups[len(backups)-1])
+		}
+	}
+
+	if err != nil {
+		Sugar.Errorf("Unable to continue audit hash chain of %s, new chain started: %v", file.Name(), err)
+	}
+
+	return &fileSink{
+		w:    auditchain.NewWriter(file, state, auditConf.GetChainKey(), auditConf.GetCheckpointEvery()),
+		file: file,
+	}, nil
+}
+
+// WriteRecord implement AuditSink interface.
+func (fs *fileSink) WriteRecord(_ zapcore.Level, record []byte) error {
+	if _, err := fs.w.Write(record); err != nil {
+		return fmt.Errorf("(fs *fileSink) WriteRecord: %w", err)
+	}
+
+	return nil
+}
+
+// Sync implement AuditSink interface.
+func (fs *fileSink) Sync() error {
+	if err := fs.w.Sync(); err != nil {
+		return fmt.Errorf("(fs *fileSink) Sync: %w", err)
+	}
+
+	return nil
+}
+
+// Close implement AuditSink interface.
+func (fs *fileSink) Close(_ context.Context) error {
+	return errors.Join(fs.Sync(), fs.file.Close())
+}
+
+// Reopen reopens file after it was moved by external logrotate.
+func (fs *fileSink) Reopen() error {
+	if err := fs.file.Reopen(); err != nil {
+		return fmt.Errorf("(fs *fileSink) Reopen: %w", err)
+	}
+
+	return nil
+}
+
+// socketSink writes records to unix or udp socket, reconnecting after failures.
+// Records are written from background loop, so slow or hung collector doesn't block requests:
+// records which don't fit into the queue are dropped.
+type socketSink struct {
+	conn    net.Conn
+	batcher *batcher.Batcher[[]byte]
+	lastErr atomic.Pointer[error]
+	frame   func(level zapcore.Level, record []byte) []byte
+	network string
+	address string
+	mu      sync.Mutex
+	closed  bool
+}
+
+func newSocketSink(network, address string, frame func(level zapcore.Level, record []byte) []byte, auditConf *audit.Audit) *socketSink {
+	ss := &socketSink{network: network, address: address, frame: frame}
+	ss.batcher = batcher.New(auditConf.GetQueueSize(), auditConf.GetBatchSize(), auditConf.GetFlushInterval(), ss.send)
+
+	return ss
+}
+
+// WriteRecord implement AuditSink interface. Record is framed at once, so syslog timestamp is the time of event.
+func (ss *socketSink) WriteRecord(level zapcore.Level, record []byte) error {
+	if !ss.batcher.Add(ss.frame(level, record)) {
+		AuditMetrics.Add("socket_dropped", 1)
+	}
+
+	return nil
+}
+
+// QueueLen returns count of records waiting for writing.
+func (ss *socketSink) QueueLen() int {
+	return ss.batcher.Len()
+}
+
+// Healthy returns error of the last failed write, nil after successful one.
+func (ss *socketSink) Healthy() error {
+	if errp := ss.lastErr.Load(); errp != nil {
+		return *errp
+	}
+
+	return nil
+}
+
+// send writes records collected by batcher. Record is dropped if collector is unavailable.
+func (ss *socketSink) send(batch [][]byte) {
+	for _, msg := range batch {
+		if err := ss.write(msg); err != nil {
+			ss.lastErr.Store(&err)
+			AuditMetrics.Add("socket_dropped", 1)
+
+			continue
+		}
+
+		ss.lastErr.Store(nil)
+	}
+}
+
+func (ss *socketSink) write(msg []byte) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.closed {
+		return fmt.Errorf("(ss *socketSink) write: %w", net.ErrClosed)
+	}
+
+	if ss.conn == nil {
+		conn, err := net.DialTimeout(ss.network, ss.address, auditSocketTimeout)
+		if err != nil {
+			return fmt.Errorf("(ss *socketSink) write: %w", err)
+		}
+
+		ss.conn = conn
+	}
+
+	if err := ss.conn.SetWriteDeadline(time.Now().Add(auditSocketTimeout)); err != nil {
+		return errors.Join(fmt.Errorf("(ss *socketSink) write: %w", err), ss.reset())
+	}
+
+	if _, err := ss.conn.Write(msg); err != nil {
+		return errors.Join(fmt.Errorf("(ss *socketSink) write: %w", err), ss.reset())
+	}
+
+	return nil
+}
+
+// Sync implement AuditSink interface. Writes all queued records.
+func (ss *socketSink) Sync() error {
+	ss.batcher.Flush()
+
+	return nil
+}
+
+// Close implement AuditSink interface. Writes queued records until ctx expires.
+func (ss *socketSink) Close(ctx context.Context) error {
+	err := ss.batcher.Close(ctx)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	// если ctx истёк, оставшиеся в очереди записи отбрасываются.
+	ss.closed = true
+
+	if err != nil {
+		return errors.Join(fmt.Errorf("(ss *socketSink) Close: %w", err), ss.reset())
+	}
+
+	return ss.reset()
+}
+
+func (ss *socketSink) reset() error {
+	if ss.conn == nil {
+		return nil
+	}
+
+	err := ss.conn.Close()
+	ss.conn = nil
+
+	if err != nil {
+		return fmt.Errorf("(ss *socketSink) reset: %w", err)
+	}
+
+	return nil
+}
+
+// syslogFrame provide RFC 5424 message framing.
+func syslogFrame(facility int, hostname string) func(level zapcore.Level, record []byte) []byte {
+	pid := os.Getpid()
+
+	return func(level zapcore.Level, record []byte) []byte {
+		return fmt.Appendf(nil, "<%d>1 %s %s %s %d %s - %s",
+			facility*8+syslogSeverity(level), time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+			hostname, syslogAppName, pid, syslogMsgID, bytes.TrimRight(record, "\n"))
+	}
+}
+
+func syslogSeverity(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.DPanicLevel:
+		return 2
+	case level == zapcore.ErrorLevel:
+		return 3
+	case level == zapcore.WarnLevel:
+		return 4
+	case level == zapcore.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// stdoutSink writes records to stdout.
+type stdoutSink struct {
+	mu sync.Mutex
+}
+
+// WriteRecord implement AuditSink interface.
+func (ss *stdoutSink) WriteRecord(_ zapcore.Level, record []byte) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if _, err := os.Stdout.Write(record); err != nil {
+		return fmt.Errorf("(ss *stdoutSink) WriteRecord: %w", err)
+	}
+
+	return nil
+}
+
+// Sync implement AuditSink interface. Stdout is not buffered.
+func (ss *stdoutSink) Sync() error {
+	return nil
+}
+
+// Close implement AuditSink interface.
+func (ss *stdoutSink) Close(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/config/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var testAuditFields = []zapcore.Field{
+	zap.Int("v", 1),
+	zap.Int64("ts", 1760781600),
+	zap.String("action", "follow"),
+	zap.String("user_id", "unauthorized"),
+	zap.Int("status", 307),
+	zap.String("url", "http://ya.ru/?a=b c"),
+	zap.String("short_url", "398f0ca4"),
+}
+
+func Test_newAuditFormatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		level  zapcore.Level
+		want   string
+	}{
+		{
+			name: "json", format: audit.FormatJSON, level: zapcore.InfoLevel,
+			want: `{"v":1,"ts":1760781600,"action":"follow","user_id":"unauthorized","status":307,"url":"http://ya.ru/?a=b c","short_url":"398f0ca4"}` + "\n",
+		},
+		{
+			name: "logfmt", format: audit.FormatLogfmt, level: zapcore.InfoLevel,
+			want: `v=1 ts=1760781600 action=follow user_id=unauthorized status=307 url="http://ya.ru/?a=b c" short_url=398f0ca4` + "\n",
+		},
+		{
+			name: "cef", format: audit.FormatCEF, level: zapcore.WarnLevel,
+			want: `CEF:0|Pklerik|urlshortener|1|follow|follow|5|rt=1760781600000 act=follow suser=unauthorized outcome=307 request=http://ya.ru/?a\=b c cs1=398f0ca4 cs1Label=short_url` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := newAuditFormatter(tt.format)(zapcore.Entry{Level: tt.level}, testAuditFields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(record))
+		})
+	}
+}
+
+func TestSocketSink(t *testing.T) {
+	require.NoError(t, Initialize("ERROR"))
+
+	dir := t.TempDir()
+
+	t.Run("syslog", func(t *testing.T) {
+		addr := filepath.Join(dir, "log.sock")
+
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+		require.NoError(t, err)
+
+		defer conn.Close()
+
+		sinkConf := audit.Sink{Type: audit.SinkSyslog, Address: addr, Level: "warn", Facility: "auth"}
+		core, sink, err := newAuditSinkCore(sinkConf, dir, &audit.Audit{})
+		require.NoError(t, err)
+
+		defer sink.Close(context.Background())
+
+		l := zap.New(core)
+		l.Info("", zap.String("action", "follow"))
+		l.Warn("", zap.String("action", "shorten"))
+		require.NoError(t, sink.Sync())
+
+		buf := make([]byte, 1024)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+
+		msg := string(buf[:n])
+		assert.True(t, strings.HasPrefix(msg, "<36>1 "), "auth facility with warning severity: %s", msg)
+		assert.Contains(t, msg, " urlshortener ")
+		assert.True(t, strings.HasSuffix(msg, ` audit - {"action":"shorten"}`), msg)
+	})
+
+	t.Run("unix", func(t *testing.T) {
+		addr := filepath.Join(dir, "audit.sock")
+
+		ln, err := net.Listen("unix", addr)
+		require.NoError(t, err)
+
+		defer ln.Close()
+
+		sinkConf := audit.Sink{Type: audit.SinkUnix, Address: addr, Format: audit.FormatLogfmt}
+		core, sink, err := newAuditSinkCore(sinkConf, dir, &audit.Audit{})
+		require.NoError(t, err)
+
+		defer sink.Close(context.Background())
+
+		require.NoError(t, core.Write(zapcore.Entry{Level: zapcore.InfoLevel}, []zapcore.Field{zap.String("action", "follow")}))
+		require.NoError(t, sink.Sync())
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+
+		defer conn.Close()
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "action=follow\n", line)
+	})
+
+	t.Run("collector down", func(t *testing.T) {
+		dropped := auditMetric("socket_dropped")
+
+		sinkConf := audit.Sink{Type: audit.SinkUnix, Address: filepath.Join(dir, "absent.sock")}
+		core, sink, err := newAuditSinkCore(sinkConf, dir, &audit.Audit{})
+		require.NoError(t, err)
+
+		defer sink.Close(context.Background())
+
+		require.NoError(t, core.Write(zapcore.Entry{}, nil), "write doesn't wait for collector")
+		require.NoError(t, core.Sync())
+
+		assert.Error(t, sink.(interface{ Healthy() error }).Healthy())
+		assert.Equal(t, dropped+1, auditMetric("socket_dropped"), "record is dropped")
+	})
+
+	t.Run("collector hangs", func(t *testing.T) {
+		addr := filepath.Join(dir, "hung.sock")
+
+		// сборщик принимает соединения, но не читает.
+		ln, err := net.Listen("unix", addr)
+		require.NoError(t, err)
+
+		defer ln.Close()
+
+		dropped := auditMetric("socket_dropped")
+
+		sinkConf := audit.Sink{Type: audit.SinkUnix, Address: addr}
+		core, sink, err := newAuditSinkCore(sinkConf, dir, &audit.Audit{QueueSize: 1})
+		require.NoError(t, err)
+
+		// большие записи не помещаются в буфер сокета, запись зависает до таймаута.
+		big := zapcore.Field{Key: "url", Type: zapcore.StringType, String: strings.Repeat("a", 1<<20)}
+		for range 3 {
+			require.NoError(t, core.Write(zapcore.Entry{}, []zapcore.Field{big}))
+		}
+
+		start := time.Now()
+		for range 100 {
+			require.NoError(t, core.Write(zapcore.Entry{}, []zapcore.Field{zap.String("action", "follow")}))
+		}
+
+		assert.Less(t, time.Since(start), 100*time.Millisecond, "requests are not blocked by collector")
+		assert.Greater(t, auditMetric("socket_dropped"), dropped, "records over queue size are dropped")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, sink.Close(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestSink_Valid(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    audit.Sink
+		wantErr bool
+	}{
+		{name: "stdout", sink: audit.Sink{Type: audit.SinkStdout, Format: audit.FormatCEF}},
+		{name: "local syslog", sink: audit.Sink{Type: audit.SinkSyslog}},
+		{name: "udp syslog without address", sink: audit.Sink{Type: audit.SinkSyslog, Network: "udp"}, wantErr: true},
+		{name: "http logfmt", sink: audit.Sink{Type: audit.SinkHTTP, Address: "http://audit", Format: audit.FormatLogfmt}, wantErr: true},
+		{name: "unknown format", sink: audit.Sink{Type: audit.SinkFile, Address: "audit.log", Format: "xml"}, wantErr: true},
+		{name: "unknown type", sink: audit.Sink{Type: "kafka"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sink.Valid()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, audit.ErrInvalidSink)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,18 +2,12 @@
 package logger
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"path"
-	"path/filepath"
-	"strings"
 	"sync"
 
 	"github.com/Pklerik/urlshortener/internal/config/audit"
-	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -28,6 +22,8 @@ var (
 
 	config      zap.Config
 	auditLogger *zap.Logger
+	auditSinks  []AuditSink
+	auditMu     sync.RWMutex
 	once        sync.Once
 )
 
@@ -65,112 +61,164 @@ func GetConfig() zapcore.EncoderConfig {
 	return config.EncoderConfig
 }
 
-// RequestLogger — middleware-логер для входящих HTTP-запросов.
-func RequestLogger(h http.HandlerFunc) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		Log.Debug("got incoming HTTP request",
-			zap.String("method", r.Method),
-			zap.String("path", r.URL.Path),
-		)
-		h(w, r)
-	})
+// SetLevel changes log level of Log and Sugar without rebuilding them.
+func SetLevel(level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("SetLevel: %w", err)
+	}
+
+	config.Level.SetLevel(lvl)
+
+	return nil
 }
 
-// AuditLogger provide audit logger.
-func AuditLogger(auditConf *audit.Audit) *zap.Logger {
+// AuditLogger provide audit logger writing to all configured sinks.
+// Relative audit file paths are resolved from dataDir. Invalid sinks are skipped.
+func AuditLogger(auditConf *audit.Audit, dataDir string) *zap.Logger {
 	once.Do(func() {
-		cores := make([]zapcore.Core, 0, 2)
-
-		emptyCfg := zapcore.EncoderConfig{
-			TimeKey:       "",
-			LevelKey:      "",
-			NameKey:       "",
-			CallerKey:     "",
-			MessageKey:    "",
-			StacktraceKey: "",
-		}
-		if filepath := auditConf.GetLogFilePath(); filepath != "" {
-			cores = append(cores, zapcore.NewCore(
-				zapcore.NewJSONEncoder(emptyCfg),      // Custom encoder
-				zapcore.AddSync(getLogFile(filepath)), // Sync to stdout
-				Log.Level(),                           // Use the same level as the original core
-			))
-		}
-
-		if auditConf.GetLogURLPath() != "" {
-			cores = append(cores, zapcore.NewCore(
-				zapcore.NewJSONEncoder(emptyCfg),           // Custom encoder
-				zapcore.AddSync(NewAuditClient(auditConf)), // Sync to stdout
-				Log.Level(), // Use the same level as the original core
-			))
-		}
+		l, sinks := newAuditLogger(auditConf, dataDir)
 
-		core := zapcore.NewTee(cores...)
-		auditLogger = zap.New(core)
+		auditMu.Lock()
+		auditLogger, auditSinks = l, sinks
+		auditMu.Unlock()
 	})
 
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+
 	return auditLogger
 }
 
-// AuditClient provide audit client for zapcore.
-type AuditClient struct {
-	client    *resty.Client
-	auditConf *audit.Audit
+// ReloadAudit replaces audit sinks with ones from auditConf. Old sinks are flushed and closed within ctx deadline.
+func ReloadAudit(ctx context.Context, auditConf *audit.Audit, dataDir string) error {
+	// после перезагрузки ленивая инициализация не должна заменить новые приёмники.
+	once.Do(func() {})
+
+	l, sinks := newAuditLogger(auditConf, dataDir)
+
+	auditMu.Lock()
+	oldSinks := auditSinks
+	auditLogger, auditSinks = l, sinks
+	auditMu.Unlock()
+
+	errs := make([]error, 0, len(oldSinks))
+	for _, sink := range oldSinks {
+		errs = append(errs, sink.Close(ctx))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("ReloadAudit: %w", err)
+	}
+
+	return nil
 }
 
-// NewAuditClient create AuditClient.
-func NewAuditClient(auditConf *audit.Audit) *AuditClient {
-	ac := &AuditClient{
-		client:    auditConf.GetURLWriter(),
-		auditConf: auditConf,
+func newAuditLogger(auditConf *audit.Audit, dataDir string) (*zap.Logger, []AuditSink) {
+	sinkConfs := auditConf.GetSinks()
+	cores := make([]zapcore.Core, 0, len(sinkConfs))
+	sinks := make([]AuditSink, 0, len(sinkConfs))
+
+	for _, sinkConf := range sinkConfs {
+		core, sink, err := newAuditSinkCore(sinkConf, dataDir, auditConf)
+		if err != nil {
+			Sugar.Errorf("Audit sink %s <%s> skipped: %v", sinkConf.Type, sinkConf.Address, err)
+			continue
+		}
+
+		sinks = append(sinks, sink)
+		cores = append(cores, core)
 	}
 
-	return ac
+	return zap.New(zapcore.NewTee(cores...)), sinks
 }
 
-// Write implement zapcore.WriteSyncer interface.
-func (ac *AuditClient) Write(massage []byte) (int, error) {
-	buf := bytes.NewBuffer([]byte{})
-	buf.Write(massage)
+// SyncAudit flushes buffered audit entries. Does nothing if audit logger is not initialized.
+func SyncAudit() error {
+	auditMu.RLock()
+	l := auditLogger
+	auditMu.RUnlock()
 
-	resp, err := ac.client.GetClient().Post(ac.auditConf.GetLogURLPath(), "application-json", buf)
-	if err != nil {
-		Sugar.Errorf("Error sending audit by URL: <%s>: %v", ac.auditConf.GetLogURLPath(), err)
+	if l == nil {
+		return nil
 	}
 
-	err = resp.Body.Close()
-	if err != nil {
-		Sugar.Errorf("Error closing response body: %v", err)
+	if err := l.Sync(); err != nil {
+		return fmt.Errorf("SyncAudit: %w", err)
 	}
 
-	return len(massage), err
+	return nil
 }
 
-func getLogFile(filePath string) *os.File {
-	var (
-		err      error
-		fullPath = filePath
-	)
+// CloseAudit flushes audit entries and closes sinks within ctx deadline.
+func CloseAudit(ctx context.Context) error {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
 
-	if !strings.HasPrefix(filePath, "/") {
-		ex, err := os.Executable()
-		if err != nil {
-			log.Panicln(err)
+	errs := make([]error, 0, len(auditSinks))
+	for _, sink := range auditSinks {
+		errs = append(errs, sink.Close(ctx))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("CloseAudit: %w", err)
+	}
+
+	return nil
+}
+
+// ReopenAudit reopens audit files after they were moved by external logrotate.
+func ReopenAudit() error {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+
+	errs := make([]error, 0, len(auditSinks))
+
+	for _, sink := range auditSinks {
+		if r, ok := sink.(interface{ Reopen() error }); ok {
+			errs = append(errs, r.Reopen())
 		}
+	}
 
-		fullPath = filepath.Dir(filepath.Dir(filepath.Dir(ex))) + "/" + filePath
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("ReopenAudit: %w", err)
 	}
 
-	if err := os.MkdirAll(path.Dir(path.Clean(fullPath)), 0750); err != nil {
-		log.Panicln(err)
+	return nil
+}
+
+// AuditQueueDepth returns count of audit entries waiting for delivery to remote sinks.
+func AuditQueueDepth() int {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+
+	depth := 0
+
+	for _, sink := range auditSinks {
+		if q, ok := sink.(interface{ QueueLen() int }); ok {
+			depth += q.QueueLen()
+		}
 	}
 
-	auditFile, err := os.OpenFile(path.Clean(fullPath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		log.Panicln(err)
+	return depth
+}
+
+// AuditHealth returns errors of audit sinks which failed the last delivery.
+func AuditHealth() error {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+
+	errs := make([]error, 0, len(auditSinks))
+
+	for _, sink := range auditSinks {
+		if h, ok := sink.(interface{ Healthy() error }); ok {
+			errs = append(errs, h.Healthy())
+		}
 	}
 
-	Sugar.Infof("Audit log file initialized: %s", fullPath)
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("AuditHealth: %w", err)
+	}
 
-	return auditFile
+	return nil
 }
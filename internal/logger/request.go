@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type requestCtxKey struct{}
+
+// requestLogger logger of single request, fields are added while request is served.
+type requestLogger struct {
+	log *zap.Logger
+	mu  sync.RWMutex
+}
+
+// WithRequestLogger returns ctx with request-scoped logger having fields.
+func WithRequestLogger(ctx context.Context, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, requestCtxKey{}, &requestLogger{log: Log.With(fields...)})
+}
+
+// AddFields adds fields to request-scoped logger in ctx, so later logs of the request carry them.
+// Does nothing outside of request.
+func AddFields(ctx context.Context, fields ...zap.Field) {
+	if rl, ok := ctx.Value(requestCtxKey{}).(*requestLogger); ok {
+		rl.mu.Lock()
+		rl.log = rl.log.With(fields...)
+		rl.mu.Unlock()
+	}
+}
+
+// FromContext returns request-scoped logger from ctx or Log. trace_id and span_id of current span
+// in ctx are added, so log entries can be found by trace.
+func FromContext(ctx context.Context) *zap.Logger {
+	log := Log
+
+	if rl, ok := ctx.Value(requestCtxKey{}).(*requestLogger); ok {
+		rl.mu.RLock()
+		log = rl.log
+		rl.mu.RUnlock()
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return log
+	}
+
+	return log.With(zap.String("trace_id", sc.TraceID().String()), zap.String("span_id", sc.SpanID().String()))
+}
+
+// SugarFromContext returns sugared FromContext logger.
+func SugarFromContext(ctx context.Context) *zap.SugaredLogger {
+	return FromContext(ctx).Sugar()
+}
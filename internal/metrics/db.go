@@ -0,0 +1,29 @@
+package metrics
+
+import "database/sql"
+
+// RegisterDBStats publishes connection pool statistics returned by stats on every scrape.
+func RegisterDBStats(stats func() sql.DBStats) {
+	for _, m := range []struct {
+		value func(s sql.DBStats) float64
+		name  string
+		help  string
+		gauge bool
+	}{
+		{func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }, "db_max_open_connections", "Maximum number of open connections to the database.", true},
+		{func(s sql.DBStats) float64 { return float64(s.OpenConnections) }, "db_open_connections", "Number of established connections both in use and idle.", true},
+		{func(s sql.DBStats) float64 { return float64(s.InUse) }, "db_in_use_connections", "Number of connections currently in use.", true},
+		{func(s sql.DBStats) float64 { return float64(s.Idle) }, "db_idle_connections", "Number of idle connections.", true},
+		{func(s sql.DBStats) float64 { return float64(s.WaitCount) }, "db_wait_count_total", "Total number of connections waited for.", false},
+		{func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }, "db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", false},
+		{func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }, "db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", false},
+		{func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }, "db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", false},
+	} {
+		fn := func() float64 { return m.value(stats()) }
+		if m.gauge {
+			Default.NewGaugeFunc(m.name, m.help, fn)
+		} else {
+			Default.NewCounterFunc(m.name, m.help, fn)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+)
+
+// Default registry served on admin listener.
+var Default = NewRegistry()
+
+// Buckets of request durations in seconds and response sizes in bytes.
+var (
+	DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	SizeBuckets     = []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576}
+)
+
+// Results of redirect and shorten requests.
+const (
+	RedirectHit  = "hit"
+	RedirectMiss = "miss"
+	RedirectGone = "gone"
+
+	ShortenSuccess  = "success"
+	ShortenConflict = "conflict"
+	ShortenError    = "error"
+)
+
+// Application metrics.
+var (
+	HTTPRequests = Default.NewCounterVec("http_requests_total",
+		"Count of handled HTTP requests.", "method", "route", "status")
+	HTTPDuration = Default.NewHistogramVec("http_request_duration_seconds",
+		"Duration of HTTP requests in seconds.", DurationBuckets, "method", "route")
+	HTTPResponseSize = Default.NewHistogramVec("http_response_size_bytes",
+		"Size of HTTP response bodies in bytes.", SizeBuckets, "method", "route")
+	Redirects = Default.NewCounterVec("shortener_redirects_total",
+		"Short link redirects by result: hit, miss or gone.", "result")
+	Shortens = Default.NewCounterVec("shortener_shorten_total",
+		"Shorten requests by result: success, conflict or error.", "result")
+
+	buildInfo = Default.NewGaugeVec("shortener_build_info",
+		"Build information, value is always 1.", "version", "commit", "goversion")
+)
+
+// SetBuildInfo publishes build version and commit.
+func SetBuildInfo(version, commit string) {
+	buildInfo.Set(1, version, commit, runtime.Version())
+}
+
+// Handler provide http handler serving Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
@@ -0,0 +1,334 @@
+// Package metrics provide application metrics in Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// contentType of Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelSep separates label values in series key, it can't appear in valid UTF-8 label values.
+const labelSep = "\xff"
+
+type metric interface {
+	kind() string
+	write(w *bufio.Writer, name string)
+}
+
+// Registry keeps named metrics and renders them for scraping.
+type Registry struct {
+	metrics map[string]metric
+	help    map[string]string
+	mu      sync.RWMutex
+}
+
+// NewRegistry - provide instance of Registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric), help: make(map[string]string)}
+}
+
+// register adds metric, metric with the same name is replaced.
+func (reg *Registry) register(name, help string, m metric) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.metrics[name] = m
+	reg.help[name] = help
+}
+
+// NewCounterVec registers counter with label names.
+func (reg *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	cv := &CounterVec{vec: newValueVec("counter", labels)}
+	reg.register(name, help, cv.vec)
+
+	return cv
+}
+
+// NewGaugeVec registers gauge with label names.
+func (reg *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	gv := &GaugeVec{vec: newValueVec("gauge", labels)}
+	reg.register(name, help, gv.vec)
+
+	return gv
+}
+
+// NewGaugeFunc registers gauge evaluated by fn on every scrape.
+func (reg *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	reg.register(name, help, funcMetric{fn: fn, metricKind: "gauge"})
+}
+
+// NewCounterFunc registers counter evaluated by fn on every scrape, e.g. maintained by other library.
+func (reg *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	reg.register(name, help, funcMetric{fn: fn, metricKind: "counter"})
+}
+
+// NewHistogramVec registers histogram with upper bounds of buckets and label names.
+func (reg *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	hv := &HistogramVec{
+		labels:  labels,
+		buckets: slices.Sorted(slices.Values(buckets)),
+		series:  make(map[string]*histogramSeries),
+	}
+	reg.register(name, help, hv)
+
+	return hv
+}
+
+// WriteTo writes all metrics sorted by name in text exposition format.
+func (reg *Registry) WriteTo(w io.Writer) (int64, error) {
+	reg.mu.RLock()
+	names := slices.Sorted(maps.Keys(reg.metrics))
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	for _, name := range names {
+		m := reg.metrics[name]
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(reg.help[name]), name, m.kind())
+		m.write(bw, name)
+	}
+	reg.mu.RUnlock()
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, fmt.Errorf("(reg *Registry) WriteTo: %w", err)
+	}
+
+	return cw.n, nil
+}
+
+// Handler provide http handler serving metrics for Prometheus scraper.
+func (reg *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+
+		if _, err := reg.WriteTo(w); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	})
+}
+
+// CounterVec monotonically increasing value per label values.
+type CounterVec struct {
+	vec *valueVec
+}
+
+// Inc increments counter of label values by 1.
+func (cv *CounterVec) Inc(labelValues ...string) {
+	cv.vec.update(labelValues, func(v float64) float64 { return v + 1 })
+}
+
+// Add increases counter of label values by delta, negative delta is ignored.
+func (cv *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+
+	cv.vec.update(labelValues, func(v float64) float64 { return v + delta })
+}
+
+// GaugeVec arbitrary value per label values.
+type GaugeVec struct {
+	vec *valueVec
+}
+
+// Set sets gauge of label values.
+func (gv *GaugeVec) Set(value float64, labelValues ...string) {
+	gv.vec.update(labelValues, func(float64) float64 { return value })
+}
+
+type valueSeries struct {
+	labelValues []string
+	value       float64
+}
+
+type valueVec struct {
+	series     map[string]*valueSeries
+	metricKind string
+	labels     []string
+	mu         sync.Mutex
+}
+
+func newValueVec(kind string, labels []string) *valueVec {
+	return &valueVec{series: make(map[string]*valueSeries), metricKind: kind, labels: labels}
+}
+
+func (vv *valueVec) kind() string {
+	return vv.metricKind
+}
+
+func (vv *valueVec) update(labelValues []string, fn func(v float64) float64) {
+	key := strings.Join(labelValues, labelSep)
+
+	vv.mu.Lock()
+	defer vv.mu.Unlock()
+
+	s, ok := vv.series[key]
+	if !ok {
+		s = &valueSeries{labelValues: slices.Clone(labelValues)}
+		vv.series[key] = s
+	}
+
+	s.value = fn(s.value)
+}
+
+func (vv *valueVec) write(w *bufio.Writer, name string) {
+	vv.mu.Lock()
+	defer vv.mu.Unlock()
+
+	for _, key := range slices.Sorted(maps.Keys(vv.series)) {
+		s := vv.series[key]
+		writeSample(w, name, labelPairs(vv.labels, s.labelValues), s.value)
+	}
+}
+
+type funcMetric struct {
+	fn         func() float64
+	metricKind string
+}
+
+func (fm funcMetric) kind() string {
+	return fm.metricKind
+}
+
+func (fm funcMetric) write(w *bufio.Writer, name string) {
+	writeSample(w, name, "", fm.fn())
+}
+
+// HistogramVec counts observations in cumulative buckets per label values.
+type HistogramVec struct {
+	series  map[string]*histogramSeries
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+// Observe adds value to histogram of label values.
+func (hv *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := strings.Join(labelValues, labelSep)
+
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+
+	s, ok := hv.series[key]
+	if !ok {
+		s = &histogramSeries{labelValues: slices.Clone(labelValues), counts: make([]uint64, len(hv.buckets))}
+		hv.series[key] = s
+	}
+
+	if i, _ := slices.BinarySearch(hv.buckets, value); i < len(hv.buckets) {
+		s.counts[i]++
+	}
+
+	s.sum += value
+	s.count++
+}
+
+func (hv *HistogramVec) kind() string {
+	return "histogram"
+}
+
+func (hv *HistogramVec) write(w *bufio.Writer, name string) {
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+
+	for _, key := range slices.Sorted(maps.Keys(hv.series)) {
+		s := hv.series[key]
+		labels := labelPairs(hv.labels, s.labelValues)
+
+		var cumulative uint64
+
+		for i, bound := range hv.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, name+"_bucket", joinLabels(labels, `le="`+formatFloat(bound)+`"`), float64(cumulative))
+		}
+
+		writeSample(w, name+"_bucket", joinLabels(labels, `le="+Inf"`), float64(s.count))
+		writeSample(w, name+"_sum", labels, s.sum)
+		writeSample(w, name+"_count", labels, float64(s.count))
+	}
+}
+
+func writeSample(w *bufio.Writer, name, labels string, value float64) {
+	w.WriteString(name)
+
+	if labels != "" {
+		w.WriteString("{" + labels + "}")
+	}
+
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func labelPairs(names, values []string) string {
+	pairs := make([]string, 0, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+
+		pairs = append(pairs, name+`="`+escapeLabel(value)+`"`)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+
+	return labels + "," + extra
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err //nolint:wrapcheck // passthrough writer
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	reg := NewRegistry()
+
+	requests := reg.NewCounterVec("requests_total", "Handled requests.", "route", "status")
+	requests.Inc("/{shortURL}", "307")
+	requests.Inc("/{shortURL}", "307")
+	requests.Add(-1, "/{shortURL}", "307")
+	requests.Inc(`/"quoted"`, "200")
+
+	duration := reg.NewHistogramVec("duration_seconds", "Request duration.", []float64{1, 0.1}, "route")
+	duration.Observe(0.05, "/")
+	duration.Observe(0.5, "/")
+	duration.Observe(3, "/")
+
+	reg.NewGaugeFunc("queue_depth", "Queued entries.\nMultiline help.", func() float64 { return 7 })
+
+	var sb strings.Builder
+	_, err := reg.WriteTo(&sb)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP duration_seconds Request duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/",le="0.1"} 1
+duration_seconds_bucket{route="/",le="1"} 2
+duration_seconds_bucket{route="/",le="+Inf"} 3
+duration_seconds_sum{route="/"} 3.55
+duration_seconds_count{route="/"} 3
+# HELP queue_depth Queued entries.\nMultiline help.
+# TYPE queue_depth gauge
+queue_depth 7
+# HELP requests_total Handled requests.
+# TYPE requests_total counter
+requests_total{route="/\"quoted\"",status="200"} 1
+requests_total{route="/{shortURL}",status="307"} 2
+`, sb.String())
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewGaugeVec("build_info", "Build information.", "version").Set(1, "v1.2.3")
+
+	w := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, contentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `build_info{version="v1.2.3"} 1`)
+}
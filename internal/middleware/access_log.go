@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"go.uber.org/zap"
+)
+
+// AccessLog provide middleware writing one JSON entry per request and putting request-scoped logger
+// with request_id into request context. Must be used after RequestID and RealIP.
+// Redirects are logged with probability redirectSample, so hot links don't flood the log.
+func AccessLog(redirectSample float64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := logger.WithRequestLogger(r.Context(), zap.String("request_id", chimiddleware.GetReqID(r.Context())))
+
+			responseData := &responseData{}
+			mw := metricsResponseWriter{
+				ResponseWriter: w,
+				responseData:   responseData,
+			}
+
+			next.ServeHTTP(&mw, r.WithContext(ctx))
+
+			status := responseData.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("route", routeLabel(r)),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", status),
+				zap.Int("bytes", responseData.size),
+				zap.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+				zap.String("client_ip", remoteIP(r)),
+				zap.String("user_agent", r.UserAgent()),
+			}
+
+			if isRedirect(status) && redirectSample < 1 {
+				if rand.Float64() >= redirectSample { //nolint:gosec // выборка логов не требует криптостойкости.
+					return
+				}
+
+				// по sample_rate можно восстановить реальное число редиректов.
+				fields = append(fields, zap.Float64("sample_rate", redirectSample))
+			}
+
+			log := logger.FromContext(ctx)
+
+			switch {
+			case status >= http.StatusInternalServerError:
+				log.Error("request", fields...)
+			case status >= http.StatusBadRequest:
+				log.Warn("request", fields...)
+			default:
+				log.Info("request", fields...)
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func isRedirect(status int) bool {
+	return status >= http.StatusMultipleChoices && status < http.StatusBadRequest && status != http.StatusNotModified
+}
+
+// remoteIP returns client address without port, RealIP middleware may already have stripped it.
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+
+	return r.RemoteAddr
+}
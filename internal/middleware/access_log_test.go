@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+
+	prev := logger.Log
+	logger.Log = zap.New(core)
+	t.Cleanup(func() { logger.Log = prev })
+
+	tests := []struct {
+		name           string
+		target         string
+		redirectSample float64
+		wantStatus     int
+		wantLogged     bool
+	}{
+		{name: "ok", target: "/api/user/urls", redirectSample: 0, wantStatus: http.StatusOK, wantLogged: true},
+		{name: "redirect_sampled_out", target: "/398f0ca4", redirectSample: 0, wantStatus: http.StatusTemporaryRedirect},
+		{name: "redirect_logged", target: "/398f0ca4", redirectSample: 1, wantStatus: http.StatusTemporaryRedirect, wantLogged: true},
+		{name: "not_found", target: "/a/b/c", redirectSample: 1, wantStatus: http.StatusNotFound, wantLogged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.TakeAll()
+
+			r := chi.NewRouter()
+			r.Use(chimiddleware.RequestID, AccessLog(tt.redirectSample))
+			r.Get("/{shortURL}", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTemporaryRedirect)
+			})
+			r.Get("/api/user/urls", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("[]"))
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			entries := logs.FilterMessage("request").All()
+			if !tt.wantLogged {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+
+			fields := entries[0].ContextMap()
+			assert.Equal(t, int64(tt.wantStatus), fields["status"])
+			assert.Equal(t, http.MethodGet, fields["method"])
+			assert.NotEmpty(t, fields["request_id"])
+			assert.Equal(t, "192.0.2.1", fields["client_ip"])
+			assert.Contains(t, fields, "duration_ms")
+		})
+	}
+
+	t.Run("request_scoped_fields", func(t *testing.T) {
+		logs.TakeAll()
+
+		r := chi.NewRouter()
+		r.Use(chimiddleware.RequestID, AccessLog(1))
+		r.Get("/api/user/urls", func(w http.ResponseWriter, r *http.Request) {
+			logger.AddFields(r.Context(), zap.String("user_id", "user-1"))
+			logger.FromContext(r.Context()).Info("service log")
+			_, _ = w.Write([]byte("[]"))
+		})
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/user/urls", nil))
+
+		service := logs.FilterMessage("service log").All()
+		access := logs.FilterMessage("request").All()
+		require.Len(t, service, 1)
+		require.Len(t, access, 1)
+
+		assert.Equal(t, access[0].ContextMap()["request_id"], service[0].ContextMap()["request_id"], "service logs carry request id")
+		assert.Equal(t, "user-1", access[0].ContextMap()["user_id"])
+		assert.Equal(t, "/api/user/urls", access[0].ContextMap()["route"])
+		assert.EqualValues(t, 2, access[0].ContextMap()["bytes"])
+	})
+}
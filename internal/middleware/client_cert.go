@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+)
+
+// RequireClientCert provide middleware rejecting requests without client certificate verified by TLS listener.
+func RequireClientCert(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			logger.SugarFromContext(r.Context()).Warnf(`Rejected request without client certificate: %s %s`, r.Method, r.URL.Path)
+			http.Error(w, `Client certificate required`, http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireClientCert(t *testing.T) {
+	tests := []struct {
+		state      *tls.ConnectionState
+		name       string
+		wantStatus int
+	}{
+		{name: "plain_http", wantStatus: http.StatusForbidden},
+		{name: "no_client_cert", state: &tls.ConnectionState{}, wantStatus: http.StatusForbidden},
+		{name: "verified", state: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil)
+			r.TLS = tt.state
+			w := httptest.NewRecorder()
+
+			RequireClientCert(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import "net/http"
+
+// HSTS provide middleware adding Strict-Transport-Security header to responses served over TLS.
+// Empty value disables the header.
+func HSTS(value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if value == "" {
+			return next
+		}
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil {
+				w.Header().Set("Strict-Transport-Security", value)
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
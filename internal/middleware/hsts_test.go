@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHSTS(t *testing.T) {
+	const value = "max-age=31536000"
+
+	tests := []struct {
+		state *tls.ConnectionState
+		name  string
+		value string
+		want  string
+	}{
+		{name: "tls", state: &tls.ConnectionState{}, value: value, want: value},
+		{name: "plain_http", value: value},
+		{name: "disabled", state: &tls.ConnectionState{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/abc", nil)
+			r.TLS = tt.state
+			w := httptest.NewRecorder()
+
+			HSTS(tt.value)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.want, w.Header().Get("Strict-Transport-Security"))
+		})
+	}
+}
@@ -6,10 +6,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/metrics"
+	"github.com/go-chi/chi"
+)
+
+const (
+	// unmatchedRoute route label of requests not matched by router.
+	unmatchedRoute = "unmatched"
+	// otherMethod method label of not standard methods.
+	otherMethod = "other"
 )
 
 type (
@@ -20,17 +29,17 @@ type (
 	}
 
 	// добавляем реализацию http.ResponseWriter.
-	loggingResponseWriter struct {
+	metricsResponseWriter struct {
 		http.ResponseWriter // встраиваем оригинальный http.ResponseWriter
 		responseData        *responseData
 	}
 )
 
-func (r *loggingResponseWriter) Write(b []byte) (int, error) {
+func (r *metricsResponseWriter) Write(b []byte) (int, error) {
 	// записываем ответ, используя оригинальный http.ResponseWriter
 	size, err := r.ResponseWriter.Write(b)
 	if err != nil {
-		return 0, fmt.Errorf("(*loggingResponseWriter) Write: %w", err)
+		return 0, fmt.Errorf("(*metricsResponseWriter) Write: %w", err)
 	}
 
 	r.responseData.size += size // захватываем размер
@@ -38,48 +47,63 @@ func (r *loggingResponseWriter) Write(b []byte) (int, error) {
 	return size, nil
 }
 
-func (r *loggingResponseWriter) WriteHeader(statusCode int) {
+func (r *metricsResponseWriter) WriteHeader(statusCode int) {
 	// записываем код статуса, используя оригинальный http.ResponseWriter
 	r.ResponseWriter.WriteHeader(statusCode)
 	r.responseData.status = statusCode // захватываем код статуса
 }
 
-// WithLogging добавляет дополнительный код для регистрации сведений о запросе
-// и возвращает новый http.Handler.
-func WithLogging(next http.Handler) http.Handler {
-	logFn := func(w http.ResponseWriter, r *http.Request) {
-		// функция Now() возвращает текущее время
+// WithMetrics counts requests and measures latency and response size per route.
+// Route is the chi pattern, so short codes don't create new series. Wildcard routes are not supported.
+func WithMetrics(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		responseData := &responseData{
-			status: 0,
-			size:   0,
+		responseData := &responseData{}
+		mw := metricsResponseWriter{
+			ResponseWriter: w,
+			responseData:   responseData,
 		}
 
-		lw := loggingResponseWriter{
-			ResponseWriter: w, // встраиваем оригинальный http.ResponseWriter
-			responseData:   responseData,
+		next.ServeHTTP(&mw, r)
+
+		status := responseData.status
+		if status == 0 {
+			status = http.StatusOK
 		}
 
-		next.ServeHTTP(&lw, r) // внедряем реализацию http.ResponseWriter
-
-		// Since возвращает разницу во времени между start
-		// и моментом вызова Since. Таким образом можно посчитать
-		// время выполнения запроса.
-		duration := time.Since(start)
-
-		// отправляем сведения о запросе в zap
-		logger.Sugar.Infoln(
-			r.Method,
-			r.RequestURI,
-			r.Proto,
-			responseData.status,
-			responseData.size,
-			duration,
-		)
+		method, route := methodLabel(r.Method), routeLabel(r)
+
+		metrics.HTTPRequests.Inc(method, route, strconv.Itoa(status))
+		metrics.HTTPDuration.Observe(time.Since(start).Seconds(), method, route)
+		metrics.HTTPResponseSize.Observe(float64(responseData.size), method, route)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// methodLabel returns method of request, any client supplied method outside of RFC 9110 set
+// is reported as "other", so it can't create unbounded number of series.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
 	}
-	// возвращаем функционально расширенный хендлер
-	return http.HandlerFunc(logFn)
+
+	return otherMethod
+}
+
+// routeLabel returns chi route pattern of served request.
+func routeLabel(r *http.Request) string {
+	// запросы без подходящего маршрута доходят только до "/*" подроутера.
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" && !strings.HasSuffix(pattern, "*") {
+			return pattern
+		}
+	}
+
+	return unmatchedRoute
 }
 
 // GZIPMiddleware provide gzip compression/decompression for request and response.
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/metrics"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMetrics(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(WithMetrics)
+	r.Route("/", func(r chi.Router) {
+		r.Get("/{shortURL}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTemporaryRedirect)
+		})
+		r.Post("/", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("http://localhost:8080/398f0ca4"))
+		})
+	})
+
+	for _, target := range []string{"/398f0ca4", "/5f1d7b2c"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a/b/c", nil))
+
+	for _, method := range []string{"FOO", "BAR1"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/398f0ca4", nil))
+	}
+
+	var sb strings.Builder
+	_, err := metrics.Default.WriteTo(&sb)
+	require.NoError(t, err)
+
+	out := sb.String()
+	assert.Contains(t, out, `http_requests_total{method="GET",route="/{shortURL}",status="307"} 2`, "short codes share route series")
+	assert.Contains(t, out, `http_requests_total{method="POST",route="/",status="200"} 1`)
+	assert.Contains(t, out, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, out, `http_response_size_bytes_sum{method="POST",route="/"} 30`)
+	assert.Contains(t, out, `http_requests_total{method="other",route="unmatched",status="405"} 2`, "arbitrary methods share series")
+	assert.NotContains(t, out, `method="FOO"`)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Timeout provide middleware canceling request context after timeout and responding 504.
+// Unlike chi Timeout, timeout is read for every request, so reloaded config is applied at once.
+func Timeout(timeout func() time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout())
+			defer func() {
+				cancel()
+
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					w.WriteHeader(http.StatusGatewayTimeout)
+				}
+			}()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Deadlines provide middleware setting connection read and write deadlines of request from timeout.
+// http.Server timeouts are fixed on start, so without it reloaded timeout longer than startup one
+// would be cut off by server. It must wrap handler before response writer is wrapped.
+func Deadlines(timeout func() time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			deadline := time.Now().Add(timeout())
+			rc := http.NewResponseController(w)
+
+			// ошибки игнорируются: у ResponseWriter без соединения, как в тестах, дедлайнов нет.
+			_ = rc.SetReadDeadline(deadline)
+			_ = rc.SetWriteDeadline(deadline)
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
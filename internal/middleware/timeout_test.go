@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlines(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{name: "reloaded_timeout_longer_than_server_one", timeout: time.Second},
+		{name: "reloaded_timeout_shorter_than_server_one", timeout: 10 * time.Millisecond, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slow := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(100 * time.Millisecond)
+				_, _ = w.Write([]byte("ok"))
+			})
+
+			srv := httptest.NewUnstartedServer(Deadlines(func() time.Duration { return tt.timeout })(slow))
+			srv.Config.WriteTimeout = 50 * time.Millisecond
+			srv.Config.ReadTimeout = time.Second
+			srv.Start()
+
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL)
+			if tt.wantErr {
+				// соединение закрыто сервером без ответа.
+				if err == nil {
+					resp.Body.Close()
+				}
+
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "ok", string(body))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// AuditSchemaVersion version of AuditEvent schema, increased on incompatible changes.
+const AuditSchemaVersion = 1
+
+// Audit actions.
+const (
+	AuditActionShorten          = "shorten"
+	AuditActionShortenBatch     = "shorten_batch"
+	AuditActionFollow           = "follow"
+	AuditActionListURLs         = "list_urls"
+	AuditActionDeleteURLs       = "delete_urls"
+	AuditActionRestoreURLs      = "restore_urls"
+	AuditActionEditURL          = "edit_url"
+	AuditActionWorkspaceShorten = "workspace_shorten"
+	AuditActionWorkspaceDelete  = "workspace_delete_urls"
+	AuditActionWorkspaceRestore = "workspace_restore_urls"
+	AuditActionAdminPurge       = "admin_purge"
+	AuditActionAdminAudit       = "admin_audit_search"
+	AuditActionPurge            = "purge"
+)
+
+// AuditEvent single audit log record. Batch requests produce one event per URL.
+type AuditEvent struct {
+	// ID assigned by audit store, increases with insertion order.
+	ID        int64  `json:"id,omitempty"`
+	Action    string `json:"action"`
+	RequestID string `json:"request_id,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	UserID    UserID `json:"user_id"`
+	URL       string `json:"url,omitempty"`
+	ShortURL  string `json:"short_url,omitempty"`
+	Version   int    `json:"v"`
+	TS        int64  `json:"ts"`
+	// Status HTTP status of response, 0 for events without request.
+	Status int `json:"status,omitempty"`
+}
+
+// AuditFilter provide search conditions for stored audit events. Empty fields match everything.
+type AuditFilter struct {
+	From     time.Time
+	To       time.Time
+	UserID   UserID
+	Action   string
+	ShortURL string
+	// Cursor selects events with ID lower than cursor, 0 starts from the newest event.
+	Cursor int64
+	Limit  int
+}
+
+// Match reports whether event satisfies filter conditions except cursor and limit.
+func (f AuditFilter) Match(ev AuditEvent) bool {
+	switch {
+	case f.UserID != "" && ev.UserID != f.UserID,
+		f.Action != "" && ev.Action != f.Action,
+		f.ShortURL != "" && ev.ShortURL != f.ShortURL,
+		!f.From.IsZero() && ev.TS < f.From.Unix(),
+		!f.To.IsZero() && ev.TS > f.To.Unix():
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobStatus provide state of background job.
+type JobStatus string
+
+const (
+	// JobPending job waits for worker or next retry.
+	JobPending JobStatus = "pending"
+	// JobRunning job is processed by worker.
+	JobRunning JobStatus = "running"
+	// JobDone job finished successfully.
+	JobDone JobStatus = "done"
+	// JobFailed job failed after all attempts.
+	JobFailed JobStatus = "failed"
+)
+
+const (
+	// JobTypeDeleteLinks job marks user links as deleted, payload is ShortUrls.
+	JobTypeDeleteLinks = "delete_links"
+	// JobTypeWebhookDelivery job sends event to webhook, payload is WebhookDelivery id.
+	JobTypeWebhookDelivery = "webhook_delivery"
+)
+
+// Job represents durable background task.
+type Job struct {
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	RunAt       time.Time `json:"run_at" db:"run_at"`
+	ID          UUIDv7    `json:"id" db:"id"`
+	Type        string    `json:"type" db:"type"`
+	UserID      UserID    `json:"user_id" db:"user_id"`
+	Payload     string    `json:"payload" db:"payload"`
+	Status      JobStatus `json:"status" db:"status"`
+	LastError   string    `json:"last_error,omitempty" db:"last_error"`
+	Attempts    int       `json:"attempts" db:"attempts"`
+	MaxAttempts int       `json:"max_attempts" db:"max_attempts"`
+}
+
+// String returns string representation of Job.
+func (j *Job) String() string {
+	return fmt.Sprintf(`Job{ID: %s, Type: %s, Status: %s, Attempts: %d/%d}`, j.ID, j.Type, j.Status, j.Attempts, j.MaxAttempts)
+}
@@ -3,6 +3,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 )
 
 // UUIDv7 is a custom type that embeds uuidv7.UUID.
@@ -15,18 +16,47 @@ type UserID UUIDv7
 // LinkData provide structure for URLs storage.
 // generate:reset
 type LinkData struct {
-	UUID      UUIDv7 `json:"uuid" db:"uuid"`
-	ShortURL  string `json:"short_url" db:"short_url"`
-	LongURL   string `json:"original_url" db:"original_url"`
-	UserID    UserID `json:"user_id" db:"user_id"`
-	IsDeleted bool   `json:"is_deleted" db:"is_deleted"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time  `json:"deleted_at,omitempty" db:"deleted_at"`
+	UUID        UUIDv7      `json:"uuid" db:"uuid"`
+	ShortURL    string      `json:"short_url" db:"short_url"`
+	LongURL     string      `json:"original_url" db:"original_url"`
+	UserID      UserID      `json:"user_id" db:"user_id"`
+	WorkspaceID WorkspaceID `json:"workspace_id,omitempty" db:"workspace_id"`
+	IsDeleted   bool        `json:"is_deleted" db:"is_deleted"`
 }
 
 // User represents the core business model for our app.
 type User struct {
-	ID UserID `json:"id" db:"id"`
+	ID         UserID            `json:"id" db:"id"`
+	Workspaces []WorkspaceMember `json:"workspaces,omitempty" db:"-"`
 }
 
 func (ld *LinkData) String() string {
-	return fmt.Sprintf(`LinkData{UUID: %s, ShortURL: %s, LongURL: %s, UserId: %s}`, ld.UUID, ld.ShortURL, ld.LongURL, ld.UserID)
+	return fmt.Sprintf(`LinkData{UUID: %s, ShortURL: %s, LongURL: %s, UserId: %s, CreatedAt: %s}`,
+		ld.UUID, ld.ShortURL, ld.LongURL, ld.UserID, ld.CreatedAt.Format(time.RFC3339))
+}
+
+// MarkDeleted sets deletion flag and timestamps.
+func (ld *LinkData) MarkDeleted(now time.Time) {
+	ld.IsDeleted = true
+	ld.DeletedAt = &now
+	ld.UpdatedAt = now
+}
+
+// LinkRevision provide history record of link destination change.
+type LinkRevision struct {
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        UUIDv7    `json:"id" db:"id"`
+	LinkID    UUIDv7    `json:"link_id" db:"link_id"`
+	OldURL    string    `json:"old_url" db:"old_url"`
+	NewURL    string    `json:"new_url" db:"new_url"`
+	ChangedBy UserID    `json:"changed_by" db:"changed_by"`
+}
+
+// String returns string representation of LinkRevision.
+func (lr *LinkRevision) String() string {
+	return fmt.Sprintf(`LinkRevision{LinkID: %s, OldURL: %s, NewURL: %s, ChangedBy: %s}`,
+		lr.LinkID, lr.OldURL, lr.NewURL, lr.ChangedBy)
 }
@@ -62,3 +62,45 @@ type ShortUrls []string
 func (us *ShortUrls) String() string {
 	return fmt.Sprintf(`Urls{"%s"}`, strings.Join(*us, `", "`))
 }
+
+// ReqWorkspace provide workspace creation contract.
+type ReqWorkspace struct {
+	Name string `json:"name"`
+}
+
+// String (req *ReqWorkspace) returns string representation of interface realization.
+func (req *ReqWorkspace) String() string {
+	return fmt.Sprintf("ReqWorkspace{Name: %s}", req.Name)
+}
+
+// ReqInvitation provide workspace invitation contract.
+type ReqInvitation struct {
+	Role Role `json:"role"`
+}
+
+// String (req *ReqInvitation) returns string representation of interface realization.
+func (req *ReqInvitation) String() string {
+	return fmt.Sprintf("ReqInvitation{Role: %s}", req.Role)
+}
+
+// ReqEditLink provide link destination change contract.
+type ReqEditLink struct {
+	LongURL string `json:"original_url"`
+}
+
+// String (req *ReqEditLink) returns string representation of interface realization.
+func (req *ReqEditLink) String() string {
+	return fmt.Sprintf("ReqEditLink{LongURL: %s}", req.LongURL)
+}
+
+// ReqWebhook provide webhook registration contract.
+type ReqWebhook struct {
+	URL         string      `json:"url"`
+	WorkspaceID WorkspaceID `json:"workspace_id,omitempty"`
+	Events      EventTypes  `json:"events"`
+}
+
+// String (req *ReqWebhook) returns string representation of interface realization.
+func (req *ReqWebhook) String() string {
+	return fmt.Sprintf("ReqWebhook{URL: %s, WorkspaceID: %s, Events: %s}", req.URL, req.WorkspaceID, req.Events.Join())
+}
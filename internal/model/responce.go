@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Responser interface provide response struct.
 type Responser interface {
@@ -43,8 +46,16 @@ func (r *SlResPostBatch) String() string {
 
 // LongShortURL provide user links contract.
 type LongShortURL struct {
-	ShortURL string `json:"short_url"`
-	LongURL  string `json:"original_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ShortURL  string     `json:"short_url"`
+	LongURL   string     `json:"original_url"`
+}
+
+// String (lsu *LongShortURL) returns string representation of interface realization.
+func (lsu *LongShortURL) String() string {
+	return fmt.Sprintf("LongShortURL{ShortURL: %s, LongURL: %s}", lsu.ShortURL, lsu.LongURL)
 }
 
 // LongShortURLs provide slice for user links contract.
@@ -59,3 +70,106 @@ func (lsu *LongShortURLs) String() string {
 
 	return fmt.Sprint("[", res, "]")
 }
+
+// WorkspaceMembers provide slice for user workspaces contract.
+type WorkspaceMembers []WorkspaceMember
+
+// String (wms *WorkspaceMembers) returns string representation of interface realization.
+func (wms *WorkspaceMembers) String() string {
+	var res string
+	for _, member := range *wms {
+		res += member.String()
+	}
+
+	return fmt.Sprint("[", res, "]")
+}
+
+// LinkRevisions provide slice for link history contract.
+type LinkRevisions []LinkRevision
+
+// String (lrs *LinkRevisions) returns string representation of interface realization.
+func (lrs *LinkRevisions) String() string {
+	var res string
+	for _, rev := range *lrs {
+		res += rev.String()
+	}
+
+	return fmt.Sprint("[", res, "]")
+}
+
+// ResJob provide background job status contract.
+type ResJob struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        UUIDv7    `json:"job_id"`
+	Status    JobStatus `json:"status"`
+	LastError string    `json:"last_error,omitempty"`
+	Attempts  int       `json:"attempts"`
+}
+
+// String (r *ResJob) returns string representation of interface realization.
+func (r *ResJob) String() string {
+	return fmt.Sprintf("ResJob{ID: %s, Status: %s, Attempts: %d}", r.ID, r.Status, r.Attempts)
+}
+
+// Webhooks provide slice for user webhooks contract.
+type Webhooks []Webhook
+
+// String (whs *Webhooks) returns string representation of interface realization.
+func (whs *Webhooks) String() string {
+	var res string
+	for _, wh := range *whs {
+		res += wh.String()
+	}
+
+	return fmt.Sprint("[", res, "]")
+}
+
+// WebhookDeliveries provide slice for webhook delivery log contract.
+type WebhookDeliveries []WebhookDelivery
+
+// String (wds *WebhookDeliveries) returns string representation of interface realization.
+func (wds *WebhookDeliveries) String() string {
+	var res string
+	for _, d := range *wds {
+		res += d.String()
+	}
+
+	return fmt.Sprint("[", res, "]")
+}
+
+// ResAuditEvents provide page of audit events, NextCursor is empty on the last page.
+type ResAuditEvents struct {
+	Events     []AuditEvent `json:"events"`
+	NextCursor int64        `json:"next_cursor,omitempty"`
+}
+
+// String (r *ResAuditEvents) returns string representation of interface realization.
+func (r *ResAuditEvents) String() string {
+	return fmt.Sprintf("ResAuditEvents{Events: %d, NextCursor: %d}", len(r.Events), r.NextCursor)
+}
+
+// Health check statuses.
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// HealthCheck provide result of single dependency check.
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// ResHealth provide aggregated health of service dependencies.
+type ResHealth struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+// String (r *ResHealth) returns string representation of interface realization.
+func (r *ResHealth) String() string {
+	return fmt.Sprintf("ResHealth{Status: %s, Checks: %d}", r.Status, len(r.Checks))
+}
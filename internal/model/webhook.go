@@ -0,0 +1,146 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WebhookID is a custom type for webhook id.
+type WebhookID UUIDv7
+
+// EventType provide kind of link lifecycle event.
+type EventType string
+
+const (
+	// EventLinkCreated link is shortened.
+	EventLinkCreated EventType = "link.created"
+	// EventLinkDeleted link is marked as deleted by owner.
+	EventLinkDeleted EventType = "link.deleted"
+	// EventLinkClicked link redirect is served, events are sampled.
+	EventLinkClicked EventType = "link.clicked"
+	// EventLinkExpired deleted link is purged after retention.
+	EventLinkExpired EventType = "link.expired"
+)
+
+// Valid returns true for known event types.
+func (et EventType) Valid() bool {
+	switch et {
+	case EventLinkCreated, EventLinkDeleted, EventLinkClicked, EventLinkExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventTypes provide set of events webhook subscribed to.
+type EventTypes []EventType
+
+// Contains returns true if et is in set.
+func (ets EventTypes) Contains(et EventType) bool {
+	return slices.Contains(ets, et)
+}
+
+// Join returns comma separated event types for storage.
+func (ets EventTypes) Join() string {
+	parts := make([]string, 0, len(ets))
+	for _, et := range ets {
+		parts = append(parts, string(et))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// ParseEventTypes parses comma separated event types.
+func ParseEventTypes(s string) EventTypes {
+	ets := make(EventTypes, 0, 4)
+
+	for _, part := range strings.Split(s, ",") {
+		if part != "" {
+			ets = append(ets, EventType(part))
+		}
+	}
+
+	return ets
+}
+
+// EventLink provide link state in event.
+type EventLink struct {
+	ShortURL    string      `json:"short_url"`
+	LongURL     string      `json:"original_url"`
+	UserID      UserID      `json:"user_id"`
+	WorkspaceID WorkspaceID `json:"workspace_id,omitempty"`
+}
+
+// Event represents link lifecycle event published by service.
+type Event struct {
+	OccurredAt time.Time `json:"occurred_at"`
+	ID         UUIDv7    `json:"id"`
+	Type       EventType `json:"type"`
+	Link       EventLink `json:"link"`
+}
+
+// String returns string representation of Event.
+func (ev *Event) String() string {
+	return fmt.Sprintf(`Event{ID: %s, Type: %s, ShortURL: %s}`, ev.ID, ev.Type, ev.Link.ShortURL)
+}
+
+// Webhook represents user endpoint receiving link events.
+// Webhook with WorkspaceID receives events of workspace links, otherwise events of user personal links.
+type Webhook struct {
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	ID          WebhookID   `json:"id" db:"id"`
+	UserID      UserID      `json:"user_id" db:"user_id"`
+	WorkspaceID WorkspaceID `json:"workspace_id,omitempty" db:"workspace_id"`
+	URL         string      `json:"url" db:"url"`
+	Secret      string      `json:"secret,omitempty" db:"secret"`
+	Events      EventTypes  `json:"events" db:"events"`
+}
+
+// String returns string representation of Webhook.
+func (wh *Webhook) String() string {
+	return fmt.Sprintf(`Webhook{ID: %s, UserID: %s, URL: %s, Events: %s}`, wh.ID, wh.UserID, wh.URL, wh.Events.Join())
+}
+
+// Receives returns true if webhook gets events of links owned by userID in workspace wsID.
+func (wh *Webhook) Receives(userID UserID, wsID WorkspaceID) bool {
+	if wsID != "" {
+		return wh.WorkspaceID == wsID
+	}
+
+	return wh.WorkspaceID == "" && wh.UserID == userID
+}
+
+// DeliveryStatus provide state of webhook delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending delivery waits for first or next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded endpoint responded with 2xx.
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed all attempts failed.
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// WebhookDelivery provide log entry of event delivery to webhook.
+type WebhookDelivery struct {
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+	ID           UUIDv7         `json:"id" db:"id"`
+	WebhookID    WebhookID      `json:"webhook_id" db:"webhook_id"`
+	EventID      UUIDv7         `json:"event_id" db:"event_id"`
+	EventType    EventType      `json:"event_type" db:"event_type"`
+	Payload      string         `json:"payload" db:"payload"`
+	Status       DeliveryStatus `json:"status" db:"status"`
+	LastError    string         `json:"last_error,omitempty" db:"last_error"`
+	ResponseCode int            `json:"response_code,omitempty" db:"response_code"`
+	Attempts     int            `json:"attempts" db:"attempts"`
+}
+
+// String returns string representation of WebhookDelivery.
+func (d *WebhookDelivery) String() string {
+	return fmt.Sprintf(`WebhookDelivery{ID: %s, WebhookID: %s, EventType: %s, Status: %s, Attempts: %d}`,
+		d.ID, d.WebhookID, d.EventType, d.Status, d.Attempts)
+}
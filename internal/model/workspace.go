@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorkspaceID is a custom type for workspace id.
+type WorkspaceID UUIDv7
+
+// Role provide permissions of workspace member.
+type Role string
+
+const (
+	// RoleOwner can manage members, create and delete links.
+	RoleOwner Role = "owner"
+	// RoleEditor can create and delete links.
+	RoleEditor Role = "editor"
+	// RoleViewer can only list links.
+	RoleViewer Role = "viewer"
+)
+
+// roleWeights orders roles by permissions.
+var roleWeights = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Valid returns true for known roles.
+func (r Role) Valid() bool {
+	_, ok := roleWeights[r]
+	return ok
+}
+
+// Allows returns true if role has at least permissions of required role.
+func (r Role) Allows(required Role) bool {
+	return roleWeights[r] >= roleWeights[required] && r.Valid()
+}
+
+// Workspace represents team which owns links.
+type Workspace struct {
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	ID        WorkspaceID `json:"id" db:"id"`
+	Name      string      `json:"name" db:"name"`
+	OwnerID   UserID      `json:"owner_id" db:"owner_id"`
+}
+
+// String returns string representation of Workspace.
+func (ws *Workspace) String() string {
+	return fmt.Sprintf(`Workspace{ID: %s, Name: %s, OwnerID: %s}`, ws.ID, ws.Name, ws.OwnerID)
+}
+
+// WorkspaceMember provide user role in workspace.
+type WorkspaceMember struct {
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	WorkspaceID WorkspaceID `json:"workspace_id" db:"workspace_id"`
+	UserID      UserID      `json:"user_id" db:"user_id"`
+	Role        Role        `json:"role" db:"role"`
+}
+
+// String returns string representation of WorkspaceMember.
+func (wm *WorkspaceMember) String() string {
+	return fmt.Sprintf(`WorkspaceMember{WorkspaceID: %s, UserID: %s, Role: %s}`, wm.WorkspaceID, wm.UserID, wm.Role)
+}
+
+// WorkspaceInvitation provide one time token for joining workspace.
+type WorkspaceInvitation struct {
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time   `json:"expires_at" db:"expires_at"`
+	Token       string      `json:"token" db:"token"`
+	WorkspaceID WorkspaceID `json:"workspace_id" db:"workspace_id"`
+	Role        Role        `json:"role" db:"role"`
+	InvitedBy   UserID      `json:"invited_by" db:"invited_by"`
+}
+
+// String returns string representation of WorkspaceInvitation.
+func (wi *WorkspaceInvitation) String() string {
+	return fmt.Sprintf(`WorkspaceInvitation{WorkspaceID: %s, Role: %s, ExpiresAt: %s}`,
+		wi.WorkspaceID, wi.Role, wi.ExpiresAt.Format(time.RFC3339))
+}
+
+// Expired returns true if invitation can't be accepted at moment now.
+func (wi *WorkspaceInvitation) Expired(now time.Time) bool {
+	return !now.Before(wi.ExpiresAt)
+}
@@ -0,0 +1,148 @@
+// Package netguard keeps outgoing requests to user provided urls away from internal networks.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"time"
+)
+
+const (
+	// dialTimeout timeout for establishing single connection.
+	dialTimeout = 10 * time.Second
+	// tlsHandshakeTimeout timeout for TLS handshake.
+	tlsHandshakeTimeout = 10 * time.Second
+	// idleConnTimeout how long idle keep-alive connection is kept.
+	idleConnTimeout = 90 * time.Second
+	// maxIdleConns idle keep-alive connections kept for all hosts.
+	maxIdleConns = 100
+)
+
+var (
+	// ErrForbiddenAddress - host resolves to loopback, private or other non public address.
+	ErrForbiddenAddress = errors.New("forbidden address")
+	// ErrNoAddress - host has no addresses.
+	ErrNoAddress = errors.New("host has no addresses")
+)
+
+// sharedAddressSpace carrier grade NAT range, RFC 6598.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Resolver resolves host names, *net.Resolver implements it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Allowed reports whether ip is public unicast address.
+// Loopback, private, link-local (including cloud metadata 169.254.169.254), multicast and unspecified are rejected.
+func Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	switch {
+	case !ip.IsValid(),
+		ip.IsUnspecified(),
+		ip.IsLoopback(),
+		ip.IsPrivate(),
+		ip.IsLinkLocalUnicast(),
+		ip.IsLinkLocalMulticast(),
+		ip.IsInterfaceLocalMulticast(),
+		ip.IsMulticast(),
+		sharedAddressSpace.Contains(ip):
+		return false
+	}
+
+	// 0.0.0.0/8 - "this network".
+	return !ip.Is4() || ip.As4()[0] != 0
+}
+
+// Resolve returns addresses of host. Error is returned if any of them is not allowed,
+// so host with mixed public and internal records can't be used to reach internal network.
+func Resolve(ctx context.Context, resolver Resolver, host string) ([]netip.Addr, error) {
+	ips := []netip.Addr{}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		ips = append(ips, ip)
+	} else {
+		ips, err = resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, fmt.Errorf("Resolve: %w", err)
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("Resolve: %w: %s", ErrNoAddress, host)
+	}
+
+	for _, ip := range ips {
+		if !Allowed(ip) {
+			return nil, fmt.Errorf("Resolve: %w: %s resolves to %s", ErrForbiddenAddress, host, ip)
+		}
+	}
+
+	return ips, nil
+}
+
+// CheckHost returns error if host can't be resolved or resolves to not allowed address.
+func CheckHost(ctx context.Context, resolver Resolver, host string) error {
+	if _, err := Resolve(ctx, resolver, host); err != nil {
+		return fmt.Errorf("CheckHost: %w", err)
+	}
+
+	return nil
+}
+
+// DialContext returns dial function which resolves and checks address on every connection.
+// Checking resolved address right before dialing protects from DNS rebinding after CheckHost.
+func DialContext(resolver Resolver) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("DialContext: %w", err)
+		}
+
+		ips, err := Resolve(ctx, resolver, host)
+		if err != nil {
+			return nil, fmt.Errorf("DialContext: %w", err)
+		}
+
+		errs := make([]error, 0, len(ips))
+
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		return nil, fmt.Errorf("DialContext: %w", errors.Join(errs...))
+	}
+}
+
+// NewClient returns http client which connects only to allowed addresses, ignores proxy settings
+// and doesn't follow redirects: redirect response is returned to caller as is.
+func NewClient(timeout time.Duration, resolver Resolver) *http.Client {
+	transport := &http.Transport{
+		DialContext:           DialContext(resolver),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver resolves names from map.
+type staticResolver map[string][]netip.Addr
+
+func (sr staticResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	ips, ok := sr[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return ips, nil
+}
+
+func addrs(ss ...string) []netip.Addr {
+	ips := make([]netip.Addr, 0, len(ss))
+	for _, s := range ss {
+		ips = append(ips, netip.MustParseAddr(s))
+	}
+
+	return ips
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fd00:ec2::254"},
+		{ip: "100.64.0.1"},
+		{ip: "0.0.0.0"},
+		{ip: "0.1.2.3"},
+		{ip: "::"},
+		{ip: "224.0.0.1"},
+		{ip: "::ffff:127.0.0.1"},
+		{ip: "::ffff:169.254.169.254"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, Allowed(netip.MustParseAddr(tt.ip)))
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	resolver := staticResolver{
+		"crm.example":      addrs("93.184.216.34"),
+		"metadata.example": addrs("169.254.169.254"),
+		"mixed.example":    addrs("93.184.216.34", "10.0.0.5"),
+		"empty.example":    {},
+	}
+
+	tests := []struct {
+		wantErr error
+		name    string
+		host    string
+	}{
+		{name: "public host", host: "crm.example"},
+		{name: "public ip", host: "93.184.216.34"},
+		{name: "loopback ip", host: "127.0.0.1", wantErr: ErrForbiddenAddress},
+		{name: "metadata host", host: "metadata.example", wantErr: ErrForbiddenAddress},
+		{name: "mixed records", host: "mixed.example", wantErr: ErrForbiddenAddress},
+		{name: "no addresses", host: "empty.example", wantErr: ErrNoAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckHost(context.Background(), resolver, tt.host)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+
+	require.Error(t, CheckHost(context.Background(), resolver, "unknown.example"))
+}
+
+func TestNewClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	t.Run("internal address", func(t *testing.T) {
+		client := NewClient(time.Second, staticResolver{})
+
+		_, err := client.Get(srv.URL) // nolint:noctx // test request
+		require.ErrorIs(t, err, ErrForbiddenAddress)
+	})
+
+	t.Run("rebinding", func(t *testing.T) {
+		// имя прошло проверку при создании, но теперь указывает на loopback.
+		client := NewClient(time.Second, staticResolver{"hooks.example": addrs("127.0.0.1")})
+
+		_, err := client.Get("http://hooks.example/") // nolint:noctx // test request
+		require.ErrorIs(t, err, ErrForbiddenAddress)
+	})
+
+	t.Run("redirect is not followed", func(t *testing.T) {
+		client := NewClient(time.Second, staticResolver{})
+		transport := client.Transport.(*http.Transport) // nolint:errcheck // set by NewClient
+		// сервер тестов слушает loopback, подменяем только установку соединения.
+		transport.DialContext = (&net.Dialer{}).DialContext
+
+		resp, err := client.Get(srv.URL) // nolint:noctx // test request
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,149 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// auditColumns columns of audit_events table scanned by scanAuditEvent.
+const auditColumns = "id, action, user_id, status, request_id, client_ip, url, short_url, version, created_at"
+
+// auditInsertColumns number of columns set by InsertAuditEvents for every event.
+const auditInsertColumns = 9
+
+// AuditRepositoryPostgres provide audit events storage in primary db.
+type AuditRepositoryPostgres struct {
+	db *sql.DB
+}
+
+// AuditRepository returns audit events storage sharing links repository connection.
+func (r *LinksRepositoryPostgres) AuditRepository() *AuditRepositoryPostgres {
+	return &AuditRepositoryPostgres{db: r.db}
+}
+
+// InsertAuditEvents stores events with one query, ids are assigned by sequence.
+func (r *AuditRepositoryPostgres) InsertAuditEvents(ctx context.Context, events []model.AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	queryArgs := make([]any, 0, auditInsertColumns*len(events))
+	placeholders := make([]string, 0, len(events))
+
+	for i, ev := range events {
+		row := make([]string, 0, auditInsertColumns)
+		for col := range auditInsertColumns {
+			row = append(row, "$"+strconv.Itoa(i*auditInsertColumns+col+1))
+		}
+
+		placeholders = append(placeholders, "("+strings.Join(row, ", ")+")")
+		queryArgs = append(queryArgs, ev.Action, string(ev.UserID), ev.Status, ev.RequestID, ev.ClientIP,
+			ev.URL, ev.ShortURL, ev.Version, time.Unix(ev.TS, 0))
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO audit_events (action, user_id, status, request_id, client_ip, url, short_url, version, created_at) VALUES "+
+			strings.Join(placeholders, ", "),
+		queryArgs...,
+	)
+	if err != nil {
+		return fmt.Errorf("InsertAuditEvents: %w", err)
+	}
+
+	return nil
+}
+
+// SelectAuditEvents selects events matching filter, newest first.
+func (r *AuditRepositoryPostgres) SelectAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	var (
+		conditions = make([]string, 0, 6)
+		queryArgs  = make([]any, 0, 7)
+	)
+
+	where := func(cond string, arg any) {
+		queryArgs = append(queryArgs, arg)
+		conditions = append(conditions, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(queryArgs))))
+	}
+
+	if filter.UserID != "" {
+		where("user_id = ?", string(filter.UserID))
+	}
+
+	if filter.Action != "" {
+		where("action = ?", filter.Action)
+	}
+
+	if filter.ShortURL != "" {
+		where("short_url = ?", filter.ShortURL)
+	}
+
+	if !filter.From.IsZero() {
+		where("created_at >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		where("created_at <= ?", filter.To)
+	}
+
+	if filter.Cursor > 0 {
+		where("id < ?", filter.Cursor)
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY id DESC"
+
+	if filter.Limit > 0 {
+		queryArgs = append(queryArgs, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(queryArgs))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("SelectAuditEvents: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]model.AuditEvent, 0, max(filter.Limit, 0))
+
+	for rows.Next() {
+		ev, err := scanAuditEvent(rows)
+		if err != nil {
+			return events, fmt.Errorf("SelectAuditEvents: %w", err)
+		}
+
+		events = append(events, ev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return events, fmt.Errorf("SelectAuditEvents: %w", err)
+	}
+
+	return events, nil
+}
+
+func scanAuditEvent(row rowScanner) (model.AuditEvent, error) {
+	var (
+		ev        model.AuditEvent
+		createdAt time.Time
+	)
+
+	err := row.Scan(&ev.ID, &ev.Action, &ev.UserID, &ev.Status, &ev.RequestID, &ev.ClientIP,
+		&ev.URL, &ev.ShortURL, &ev.Version, &createdAt)
+	if err != nil {
+		return ev, fmt.Errorf("scanAuditEvent: %w", err)
+	}
+
+	ev.TS = createdAt.Unix()
+
+	return ev, nil
+}
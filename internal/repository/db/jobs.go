@@ -0,0 +1,154 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// jobColumns columns of jobs table scanned by scanJob.
+const jobColumns = "id, type, user_id, payload, status, attempts, max_attempts, last_error, run_at, created_at, updated_at"
+
+// JobsRepositoryPostgres provide jobs storage in primary db.
+type JobsRepositoryPostgres struct {
+	db *sql.DB
+}
+
+// JobsRepository returns jobs storage sharing links repository connection.
+func (r *LinksRepositoryPostgres) JobsRepository() *JobsRepositoryPostgres {
+	return &JobsRepositoryPostgres{db: r.db}
+}
+
+// CreateJob stores new job.
+func (r *JobsRepositoryPostgres) CreateJob(ctx context.Context, job model.Job) (model.Job, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx,
+		`INSERT INTO jobs (id, type, user_id, payload, status, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7) RETURNING `+jobColumns,
+		job.ID, job.Type, nullUserID(job.UserID), job.Payload, job.Status, job.MaxAttempts, job.RunAt,
+	))
+	if err != nil {
+		return job, fmt.Errorf("CreateJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimJob marks the oldest ready pending job as running.
+// SKIP LOCKED lets several app instances claim jobs concurrently.
+func (r *JobsRepositoryPostgres) ClaimJob(ctx context.Context, now time.Time) (model.Job, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx,
+		`UPDATE jobs SET status = 'running', attempts = attempts + 1, updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs WHERE status = 'pending' AND run_at <= $1
+			ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED
+		) RETURNING `+jobColumns,
+		now,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, repository.ErrNoJobs
+	}
+
+	if err != nil {
+		return job, fmt.Errorf("ClaimJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// UpdateJob saves job state.
+func (r *JobsRepositoryPostgres) UpdateJob(ctx context.Context, job model.Job) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE jobs SET status = $2, attempts = $3, last_error = $4, run_at = $5, updated_at = now() WHERE id = $1`,
+		job.ID, job.Status, job.Attempts, job.LastError, job.RunAt,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateJob: %w", err)
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return repository.ErrNotFoundJob
+	}
+
+	return nil
+}
+
+// SelectJob selects job by id.
+func (r *JobsRepositoryPostgres) SelectJob(ctx context.Context, id model.UUIDv7) (model.Job, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, repository.ErrNotFoundJob
+	}
+
+	if err != nil {
+		return job, fmt.Errorf("SelectJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// TouchJob extends lease of running job.
+func (r *JobsRepositoryPostgres) TouchJob(ctx context.Context, id model.UUIDv7) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE jobs SET updated_at = now() WHERE id = $1 AND status = 'running'`, id)
+	if err != nil {
+		return fmt.Errorf("TouchJob: %w", err)
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return repository.ErrNotFoundJob
+	}
+
+	return nil
+}
+
+// ResetRunningJobs returns running jobs with expired lease to pending.
+// Lease is checked by db clock, so clocks of app instances don't matter.
+func (r *JobsRepositoryPostgres) ResetRunningJobs(ctx context.Context, lease time.Duration) (int, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE jobs SET status = 'pending', updated_at = now()
+		WHERE status = 'running' AND updated_at < now() - make_interval(secs => $1)`,
+		lease.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ResetRunningJobs: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ResetRunningJobs: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+func scanJob(row rowScanner) (model.Job, error) {
+	var (
+		job    model.Job
+		userID sql.NullString
+	)
+
+	err := row.Scan(&job.ID, &job.Type, &userID, &job.Payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.LastError, &job.RunAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return job, fmt.Errorf("scanJob: %w", err)
+	}
+
+	if userID.Valid {
+		job.UserID = model.UserID(userID.String)
+	}
+
+	return job, nil
+}
+
+// nullUserID returns nil for system jobs without user.
+func nullUserID(userID model.UserID) any {
+	if userID == "" {
+		return nil
+	}
+
+	return string(userID)
+}
@@ -0,0 +1,94 @@
+package dbrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// RestoreLinks unmarks links deleted not earlier than deletedAfter.
+func (r *LinksRepositoryPostgres) RestoreLinks(ctx context.Context, ids []model.UUIDv7, deletedAfter time.Time) ([]model.LinkData, error) {
+	if len(ids) == 0 {
+		return []model.LinkData{}, nil
+	}
+
+	lds, err := r.queryLinks(ctx,
+		"UPDATE links SET is_deleted = false, deleted_at = NULL, updated_at = now() "+
+			"WHERE id = ANY($1::uuid[]) AND is_deleted AND deleted_at >= $2 RETURNING "+linkColumns,
+		idsToStrings(ids), deletedAfter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("RestoreLinks: %w", err)
+	}
+
+	for _, ld := range lds {
+		r.replicas.markWrite(ld.UserID)
+	}
+
+	return lds, nil
+}
+
+// PurgeLinks permanently removes links.
+func (r *LinksRepositoryPostgres) PurgeLinks(ctx context.Context, ids []model.UUIDv7) ([]model.LinkData, error) {
+	if len(ids) == 0 {
+		return []model.LinkData{}, nil
+	}
+
+	lds, err := r.queryLinks(ctx, "DELETE FROM links WHERE id = ANY($1::uuid[]) RETURNING "+linkColumns, idsToStrings(ids))
+	if err != nil {
+		return nil, fmt.Errorf("PurgeLinks: %w", err)
+	}
+
+	return lds, nil
+}
+
+// PurgeDeleted permanently removes links deleted before deletedBefore.
+func (r *LinksRepositoryPostgres) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]model.LinkData, error) {
+	lds, err := r.queryLinks(ctx,
+		"DELETE FROM links WHERE is_deleted AND deleted_at < $1 RETURNING "+linkColumns,
+		deletedBefore,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("PurgeDeleted: %w", err)
+	}
+
+	return lds, nil
+}
+
+// queryLinks runs query returning linkColumns in transaction on primary.
+func (r *LinksRepositoryPostgres) queryLinks(ctx context.Context, query string, args ...any) ([]model.LinkData, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating tx error: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying links: %w", err)
+	}
+
+	lds, err := collectLinks(rows)
+	rows.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("error collecting links: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing tx error: %w", err)
+	}
+
+	return lds, nil
+}
+
+func idsToStrings(ids []model.UUIDv7) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, string(id))
+	}
+
+	return res
+}
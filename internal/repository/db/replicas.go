@@ -0,0 +1,198 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/config/dbconf"
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+const (
+	// replicaHealthCheckInterval period between replicas health checks.
+	replicaHealthCheckInterval = 5 * time.Second
+	// replicaPingTimeout timeout for single replica ping.
+	replicaPingTimeout = time.Second
+)
+
+// replica provide read only database connection with health state.
+type replica struct {
+	db      *sql.DB
+	name    string
+	healthy atomic.Bool
+}
+
+// replicaSet routes reads between healthy replicas and primary.
+type replicaSet struct {
+	lastWrites map[model.UserID]time.Time
+	replicas   []*replica
+	next       atomic.Uint64
+	rywWindow  time.Duration
+	mu         sync.RWMutex
+}
+
+// newReplicaSet connects to all replicas and returns replicaSet.
+// Replicas are considered healthy until first failed health check.
+func newReplicaSet(replicaConfs []dbconf.DBConfigurer, rywWindow time.Duration) (*replicaSet, error) {
+	rs := &replicaSet{
+		replicas:   make([]*replica, 0, len(replicaConfs)),
+		lastWrites: make(map[model.UserID]time.Time),
+		rywWindow:  rywWindow,
+	}
+
+	for i, replicaConf := range replicaConfs {
+		db, err := openDB(replicaConf.GetConnString())
+		if err != nil {
+			return rs, fmt.Errorf("unable to connect to replica %d: %w", i, err)
+		}
+
+		rs.add(db, fmt.Sprintf("replica-%d", i))
+	}
+
+	return rs, nil
+}
+
+func (rs *replicaSet) add(db *sql.DB, name string) {
+	rep := &replica{db: db, name: name}
+	rep.healthy.Store(true)
+
+	rs.replicas = append(rs.replicas, rep)
+}
+
+// pick returns next healthy replica using round robin.
+func (rs *replicaSet) pick() (*replica, bool) {
+	total := uint64(len(rs.replicas))
+	if total == 0 {
+		return nil, false
+	}
+
+	start := rs.next.Add(1)
+	for i := range total {
+		rep := rs.replicas[(start+i)%total]
+		if rep.healthy.Load() {
+			return rep, true
+		}
+	}
+
+	return nil, false
+}
+
+// reader returns replica for userID reads or false if read must go to primary.
+func (rs *replicaSet) reader(userID model.UserID) (*replica, bool) {
+	if rs.recentlyWrote(userID) {
+		return nil, false
+	}
+
+	return rs.pick()
+}
+
+// markWrite starts read-your-writes window for user.
+func (rs *replicaSet) markWrite(userID model.UserID) {
+	if rs.rywWindow <= 0 || userID == "" {
+		return
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.lastWrites[userID] = time.Now()
+}
+
+func (rs *replicaSet) recentlyWrote(userID model.UserID) bool {
+	if rs.rywWindow <= 0 || userID == "" {
+		return false
+	}
+
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	lastWrite, ok := rs.lastWrites[userID]
+
+	return ok && time.Since(lastWrite) < rs.rywWindow
+}
+
+// markFailed excludes replica from routing until next successful health check.
+func (rs *replicaSet) markFailed(rep *replica, err error) {
+	if rep.healthy.Swap(false) {
+		logger.Sugar.Warnf("Replica %s marked unhealthy: %v", rep.name, err)
+	}
+}
+
+// markReadFailed excludes replica after failed read. Canceled and timed out requests
+// are not replica failures: client hang up must not eject healthy replica.
+func (rs *replicaSet) markReadFailed(rep *replica, err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	rs.markFailed(rep, err)
+}
+
+// runHealthChecks pings replicas until ctx is done.
+func (rs *replicaSet) runHealthChecks(ctx context.Context, interval time.Duration) {
+	if len(rs.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rs.checkReplicas(ctx)
+			rs.cleanWrites()
+		}
+	}
+}
+
+func (rs *replicaSet) checkReplicas(ctx context.Context) {
+	for _, rep := range rs.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := rep.db.PingContext(pingCtx)
+
+		cancel()
+
+		if err != nil {
+			rs.markFailed(rep, err)
+			continue
+		}
+
+		if !rep.healthy.Swap(true) {
+			logger.Sugar.Infof("Replica %s is healthy again", rep.name)
+		}
+	}
+}
+
+// cleanWrites removes expired read-your-writes windows.
+func (rs *replicaSet) cleanWrites() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for userID, lastWrite := range rs.lastWrites {
+		if time.Since(lastWrite) >= rs.rywWindow {
+			delete(rs.lastWrites, userID)
+		}
+	}
+}
+
+// close closes all replicas connections.
+func (rs *replicaSet) close() error {
+	errs := make([]error, 0, len(rs.replicas))
+
+	for _, rep := range rs.replicas {
+		if err := rep.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rep.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReplicaSet(t *testing.T, size int, rywWindow time.Duration) *replicaSet {
+	t.Helper()
+
+	rs, err := newReplicaSet(nil, rywWindow)
+	assert.NoError(t, err)
+
+	for i := range size {
+		db, err := sql.Open("pgx", "user=test dbname=test host=localhost port=1")
+		assert.NoError(t, err)
+		rs.add(db, string(rune('a'+i)))
+	}
+
+	return rs
+}
+
+func Test_replicaSet_pick(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	tests := []struct {
+		name      string
+		unhealthy []int
+		wantNames []string
+		size      int
+		wantOK    bool
+	}{
+		{name: "no replicas", size: 0, wantOK: false},
+		{name: "round robin", size: 3, wantOK: true, wantNames: []string{"b", "c", "a", "b"}},
+		{name: "skip unhealthy", size: 3, unhealthy: []int{1}, wantOK: true, wantNames: []string{"c", "c", "a", "c"}},
+		{name: "all unhealthy", size: 2, unhealthy: []int{0, 1}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := newTestReplicaSet(t, tt.size, 0)
+			for _, i := range tt.unhealthy {
+				rs.markFailed(rs.replicas[i], sql.ErrConnDone)
+			}
+
+			if !tt.wantOK {
+				_, ok := rs.pick()
+				assert.False(t, ok)
+
+				return
+			}
+
+			for _, want := range tt.wantNames {
+				rep, ok := rs.pick()
+				if assert.True(t, ok) {
+					assert.Equal(t, want, rep.name)
+				}
+			}
+		})
+	}
+}
+
+func Test_replicaSet_readYourWrites(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	userID := model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+	rs := newTestReplicaSet(t, 1, time.Minute)
+
+	_, ok := rs.reader(userID)
+	assert.True(t, ok, "reads go to replica before writes")
+
+	rs.markWrite(userID)
+
+	_, ok = rs.reader(userID)
+	assert.False(t, ok, "reads go to primary inside window")
+
+	_, ok = rs.reader("other")
+	assert.True(t, ok, "other users still read from replica")
+
+	rs.lastWrites[userID] = time.Now().Add(-2 * time.Minute)
+	rs.cleanWrites()
+
+	_, ok = rs.reader(userID)
+	assert.True(t, ok, "reads go to replica after window")
+}
+
+func Test_replicaSet_markReadFailed(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	tests := []struct {
+		err         error
+		name        string
+		wantHealthy bool
+	}{
+		{name: "canceled", err: fmt.Errorf("query: %w", context.Canceled), wantHealthy: true},
+		{name: "deadline", err: context.DeadlineExceeded, wantHealthy: true},
+		{name: "conn_done", err: sql.ErrConnDone, wantHealthy: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := newTestReplicaSet(t, 1, 0)
+			rs.markReadFailed(rs.replicas[0], tt.err)
+
+			assert.Equal(t, tt.wantHealthy, rs.replicas[0].healthy.Load())
+		})
+	}
+}
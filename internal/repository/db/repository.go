@@ -6,78 +6,109 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Pklerik/urlshortener/internal/config/dbconf"
-	"github.com/Pklerik/urlshortener/internal/dictionary"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/Pklerik/urlshortener/internal/model"
 	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/tracing"
 	"github.com/Pklerik/urlshortener/migrations"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
+// linkColumns columns of links table scanned by scanLink.
+const linkColumns = "id, short_url, long_url, user_id, workspace_id, is_deleted, created_at, updated_at, deleted_at"
+
 // LinksRepositoryPostgres provide base struct for db implementation.
+// Writes always go to primary db, reads are routed to healthy replicas.
 type LinksRepositoryPostgres struct {
-	db *sql.DB
+	db       *sql.DB
+	dbConf   dbconf.DBConfigurer
+	replicas *replicaSet
 }
 
 // NewDBLinksRepository - provide new instance DBLinksRepository.
-func NewDBLinksRepository(ctx context.Context, dbConf dbconf.DBConfigurer) (*LinksRepositoryPostgres, error) {
+// rywWindow sets period after user writes when user reads go to primary.
+// autoMigrate applies pending migrations on startup, otherwise they are applied by cmd/migrate.
+func NewDBLinksRepository(ctx context.Context, dbConf dbconf.DBConfigurer, replicaConfs []dbconf.DBConfigurer, rywWindow time.Duration, autoMigrate bool) (*LinksRepositoryPostgres, error) {
 	db, err := ConnectDB(dbConf)
 	if err != nil {
-		logger.Sugar.Errorf("Cant connect to db server: %w", err)
+		logger.Sugar.Errorf("Cant connect to db server: %v", err)
+		return nil, fmt.Errorf("NewDBLinksRepository: %w", err)
 	}
 
 	logger.Sugar.Infof("SUCCESS connecting to db: %v", db.Stats())
 
-	err = migrations.MakeMigrations(ctx, db, dbConf)
+	if autoMigrate {
+		err = migrations.MakeMigrations(ctx, db, dbConf)
+		if err != nil {
+			logger.Sugar.Errorf("Cant make migrations: %v", err)
+			return nil, fmt.Errorf("NewDBLinksRepository: %w", err)
+		}
+	} else {
+		logger.Sugar.Info("Auto migrations are disabled")
+	}
+
+	replicas, err := newReplicaSet(replicaConfs, rywWindow)
 	if err != nil {
-		logger.Sugar.Errorf("Cant connect to db server: %w", err)
 		return nil, fmt.Errorf("NewDBLinksRepository: %w", err)
 	}
 
+	logger.Sugar.Infof("Configured <%d> read replicas", len(replicas.replicas))
+
+	go replicas.runHealthChecks(ctx, replicaHealthCheckInterval)
+
 	return &LinksRepositoryPostgres{
-		db: db,
+		db:       db,
+		dbConf:   dbConf,
+		replicas: replicas,
 	}, nil
 }
 
-// ConnectDB connecting to DB.
-func ConnectDB(dbConf dbconf.DBConfigurer) (*sql.DB, error) {
-	if os.Getenv("GOOSE_DRIVER") == "" {
-		if err := os.Setenv("GOOSE_DRIVER", dbconf.DefaultGooseDrier); err != nil {
-			return nil, fmt.Errorf("cant set env variable: %w", err)
-		}
+// Close closes primary and replicas connection pools.
+func (r *LinksRepositoryPostgres) Close() error {
+	if err := errors.Join(r.db.Close(), r.replicas.close()); err != nil {
+		return fmt.Errorf("Close: %w", err)
 	}
 
-	if os.Getenv("GOOSE_DBSTRING") == "" {
-		if err := os.Setenv("GOOSE_DBSTRING", dbConf.GetConnString()); err != nil {
-			return nil, fmt.Errorf("cant set env variable: %w", err)
-		}
-	}
+	return nil
+}
 
-	if os.Getenv("GOOSE_MIGRATION_DIR") == "" {
-		dir := filepath.Join(dictionary.BasePath, "migrations")
-		if err := os.Setenv("GOOSE_MIGRATION_DIR", dir); err != nil {
-			return nil, fmt.Errorf("cant set env variable: %w", err)
-		}
+// Stats returns primary connection pool statistics.
+func (r *LinksRepositoryPostgres) Stats() sql.DBStats {
+	return r.db.Stats()
+}
+
+// openDB opens pool with SQL statements traced.
+func openDB(connString string) (*sql.DB, error) {
+	connConf, err := pgx.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("openDB: %w", err)
 	}
 
-	logger.Sugar.Infof("ConnString: Database: %s, User: %s, Options: %v",
-		dbConf.(*dbconf.Conf).Database,
-		dbConf.(*dbconf.Conf).User,
-		dbConf.(*dbconf.Conf).Options,
-	)
+	connConf.Tracer = tracing.PgxTracer{}
+
+	return stdlib.OpenDB(*connConf), nil
+}
 
+// ConnectDB connecting to DB.
+func ConnectDB(dbConf dbconf.DBConfigurer) (*sql.DB, error) {
 	if dbConf == nil {
 		return nil, repository.ErrCollectingDBConf
 	}
 
+	// опции подключения не логируются: в них могут быть пароли.
+	if conf, ok := dbConf.(*dbconf.Conf); ok {
+		logger.Sugar.Infof("Connecting to database <%s> on <%s:%s> as <%s>", conf.Database, conf.Host, conf.Port, conf.User)
+	}
+
 	ps := dbConf.GetConnString()
 
-	db, err := sql.Open("pgx", ps)
+	db, err := openDB(ps)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to DB: %w", err)
 	}
@@ -96,8 +127,12 @@ func (r *LinksRepositoryPostgres) SetLinks(ctx context.Context, links []model.Li
 		return links, fmt.Errorf("links insertion error: %w", err)
 	}
 
+	for _, link := range links {
+		r.replicas.markWrite(link.UserID)
+	}
+
 	if len(insertedLinks) != len(links) {
-		return links, repository.ErrExistingLink
+		return insertedLinks, repository.ErrExistingLink
 	}
 
 	return insertedLinks, nil
@@ -134,18 +169,19 @@ func prepareInsertionQuery(links []model.LinkData) (string, []any) {
 		return "", nil
 	}
 
-	queryArgs := make([]any, 0, 4*len(links))
+	queryArgs := make([]any, 0, 5*len(links))
 	placeholders := make([]string, 0, len(links))
 
 	for i, link := range links {
-		base := i*4 + 1
-		placeholders = append(placeholders, "($"+strconv.Itoa(base)+", $"+strconv.Itoa(base+1)+", $"+strconv.Itoa(base+2)+", $"+strconv.Itoa(base+3)+")")
-		queryArgs = append(queryArgs, link.UUID, link.ShortURL, link.LongURL, link.UserID)
+		base := i*5 + 1
+		placeholders = append(placeholders, "($"+strconv.Itoa(base)+", $"+strconv.Itoa(base+1)+", $"+strconv.Itoa(base+2)+
+			", $"+strconv.Itoa(base+3)+", $"+strconv.Itoa(base+4)+")")
+		queryArgs = append(queryArgs, link.UUID, link.ShortURL, link.LongURL, link.UserID, nullWorkspaceID(link.WorkspaceID))
 	}
 
-	query := "INSERT INTO links (id, short_url, long_url, user_id) VALUES " +
+	query := "INSERT INTO links (id, short_url, long_url, user_id, workspace_id) VALUES " +
 		strings.Join(placeholders, ", ") +
-		" ON CONFLICT (short_url) DO NOTHING RETURNING id, short_url, long_url, user_id"
+		" ON CONFLICT (short_url) DO NOTHING RETURNING " + linkColumns
 
 	return query, queryArgs
 }
@@ -156,12 +192,10 @@ func prepareInsertionQuery(links []model.LinkData) (string, []any) {
 func collectLinks(rows *sql.Rows) ([]model.LinkData, error) {
 	linksData := make([]model.LinkData, 0, 1)
 	for rows.Next() {
-		linkData := model.LinkData{}
-
-		err := rows.Scan(&linkData.UUID, &linkData.ShortURL, &linkData.LongURL, &linkData.UserID)
+		linkData, err := scanLink(rows)
 		if err != nil {
 			if !errors.Is(err, sql.ErrNoRows) {
-				logger.Sugar.Errorf("error scanning QUERY result: %w", err)
+				logger.Sugar.Errorf("error scanning QUERY result: %v", err)
 				return linksData, fmt.Errorf("error scanning QUERY result: %w", err)
 			}
 
@@ -174,6 +208,36 @@ func collectLinks(rows *sql.Rows) ([]model.LinkData, error) {
 	return linksData, nil
 }
 
+// rowScanner common interface of *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanLink scans linkColumns to model.LinkData.
+func scanLink(row rowScanner) (model.LinkData, error) {
+	var (
+		linkData    model.LinkData
+		workspaceID sql.NullString
+		deletedAt   sql.NullTime
+	)
+
+	err := row.Scan(&linkData.UUID, &linkData.ShortURL, &linkData.LongURL, &linkData.UserID, &workspaceID,
+		&linkData.IsDeleted, &linkData.CreatedAt, &linkData.UpdatedAt, &deletedAt)
+	if err != nil {
+		return linkData, fmt.Errorf("scanLink: %w", err)
+	}
+
+	if workspaceID.Valid {
+		linkData.WorkspaceID = model.WorkspaceID(workspaceID.String)
+	}
+
+	if deletedAt.Valid {
+		linkData.DeletedAt = &deletedAt.Time
+	}
+
+	return linkData, nil
+}
+
 // collectIDs(rows *sql.Rows, data *any, items ...any) (int, error)
 // provide unification for rows scanning
 // return number of inserted rows and error.
@@ -185,7 +249,7 @@ func collectIDs(rows *sql.Rows) ([]model.UUIDv7, error) {
 		err := rows.Scan(&id)
 		if err != nil {
 			if !errors.Is(err, sql.ErrNoRows) {
-				logger.Sugar.Errorf("error scanning QUERY result: %w", err)
+				logger.Sugar.Errorf("error scanning QUERY result: %v", err)
 				return ids, fmt.Errorf("error scanning QUERY result: %w", err)
 			}
 
@@ -199,46 +263,73 @@ func collectIDs(rows *sql.Rows) ([]model.UUIDv7, error) {
 }
 
 // FindShort - provide model.LinkData and error
-// If shortURL is absent returns nil.
+// If shortURL is absent returns repository.ErrNotFoundLink.
+// Lookup goes to replica first and falls back to primary on replica errors
+// or if replica has not received the link yet.
 func (r *LinksRepositoryPostgres) FindShort(ctx context.Context, short string) (model.LinkData, error) {
-	var (
-		ld  = new(model.LinkData)
-		err error
-	)
+	if rep, ok := r.replicas.pick(); ok {
+		ld, err := r.findShort(ctx, rep.db, short)
+		if err == nil {
+			return ld, nil
+		}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+		if !errors.Is(err, repository.ErrNotFoundLink) {
+			r.replicas.markReadFailed(rep, err)
+		}
+	}
+
+	return r.findShort(ctx, r.db, short)
+}
+
+func (r *LinksRepositoryPostgres) findShort(ctx context.Context, db *sql.DB, short string) (model.LinkData, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return *ld, fmt.Errorf("error creating tx error: %w", err)
+		return model.LinkData{}, fmt.Errorf("error creating tx error: %w", err)
 	}
 	defer tx.Rollback()
 
-	ld, err = r.getShort(ctx, tx, short)
+	ld, err := r.getShort(ctx, tx, short)
 	if err != nil {
-		return *ld, fmt.Errorf("crate error: %w", err)
+		return model.LinkData{}, fmt.Errorf("crate error: %w", err)
+	}
+
+	if ld == nil {
+		return model.LinkData{}, repository.ErrNotFoundLink
 	}
 
 	return *ld, nil
 }
 
-// PingDB returns nil every time.
-func (r *LinksRepositoryPostgres) PingDB(_ context.Context) error {
-	err := r.db.Ping()
+// PingDB returns error if primary db is unreachable within ctx.
+func (r *LinksRepositoryPostgres) PingDB(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("PingDB: %w", err)
+	}
+
+	return nil
+}
+
+// CheckMigrations returns repository.ErrMigrationsPending if db schema is older than app migrations.
+func (r *LinksRepositoryPostgres) CheckMigrations(ctx context.Context) error {
+	current, latest, err := migrations.Versions(ctx, r.db, r.dbConf)
 	if err != nil {
-		logger.Sugar.Errorf("Cant connect to db server: %w", err)
+		return fmt.Errorf("CheckMigrations: %w", err)
+	}
+
+	if current < latest {
+		return fmt.Errorf("CheckMigrations: %w: applied %d, expected %d", repository.ErrMigrationsPending, current, latest)
 	}
 
 	return nil
 }
 
 func (r *LinksRepositoryPostgres) getShort(ctx context.Context, tx *sql.Tx, short string) (*model.LinkData, error) {
-	linkData := model.LinkData{}
+	row := tx.QueryRowContext(ctx, "SELECT "+linkColumns+" FROM links WHERE short_url LIKE $1", short)
 
-	row := tx.QueryRowContext(ctx, "SELECT id, short_url, long_url, user_id, is_deleted FROM links WHERE short_url LIKE $1", short)
-
-	err := row.Scan(&linkData.UUID, &linkData.ShortURL, &linkData.LongURL, &linkData.UserID, &linkData.IsDeleted)
+	linkData, err := scanLink(row)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
-			logger.Sugar.Errorf("error selecting db data: %w", err)
+			logger.SugarFromContext(ctx).Errorf("error selecting db data: %v", err)
 
 			return nil, fmt.Errorf("error selecting db data: %w", err)
 		}
@@ -246,20 +337,34 @@ func (r *LinksRepositoryPostgres) getShort(ctx context.Context, tx *sql.Tx, shor
 		return nil, nil
 	}
 
-	logger.Sugar.Infof("LD selected: %s", linkData)
+	logger.SugarFromContext(ctx).Debugf("LD selected: %s", linkData)
 
 	return &linkData, nil
 }
 
 // SelectUserLinks selects user links by userID.
+// Reads go to replica unless user is inside read-your-writes window.
 func (r *LinksRepositoryPostgres) SelectUserLinks(ctx context.Context, userID model.UserID) ([]model.LinkData, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+	if rep, ok := r.replicas.reader(userID); ok {
+		lds, err := r.selectUserLinks(ctx, rep.db, userID)
+		if err == nil {
+			return lds, nil
+		}
+
+		r.replicas.markReadFailed(rep, err)
+	}
+
+	return r.selectUserLinks(ctx, r.db, userID)
+}
+
+func (r *LinksRepositoryPostgres) selectUserLinks(ctx context.Context, db *sql.DB, userID model.UserID) ([]model.LinkData, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return []model.LinkData{}, fmt.Errorf("error creating tx error: %w", err)
 	}
 	defer tx.Rollback()
 
-	rows, err := tx.QueryContext(ctx, `SELECT id, short_url, long_url, user_id FROM links WHERE user_id = $1`, userID)
+	rows, err := tx.QueryContext(ctx, "SELECT "+linkColumns+" FROM links WHERE user_id = $1", userID)
 	if err != nil {
 		return nil, fmt.Errorf("error selecting link data: %w", err)
 	}
@@ -291,7 +396,7 @@ func (r *LinksRepositoryPostgres) CreateUser(ctx context.Context, userID model.U
 		err := rows.Scan(&user.ID)
 		if err != nil {
 			if !errors.Is(err, sql.ErrNoRows) {
-				logger.Sugar.Errorf("error scanning QUERY result: %w", err)
+				logger.SugarFromContext(ctx).Errorf("error scanning QUERY result: %v", err)
 
 				return user, fmt.Errorf("error scanning QUERY result: %w", err)
 			}
@@ -316,34 +421,44 @@ func (r *LinksRepositoryPostgres) BatchMarkAsDeleted(ctx context.Context, linkCh
 	batchSize := 20
 	batch := make([]model.UUIDv7, 0, batchSize)
 
+	var errs []error
+
 	for linkData := range linkCh {
 		select {
 		case <-ctx.Done():
 			goto last
 		default:
 			batch = append(batch, linkData.UUID)
+			r.replicas.markWrite(linkData.UserID)
 		}
 
 		if len(batch) == batchSize {
 			items, err := proceedBatch(ctx, r.db, batch)
 			if err != nil {
-				logger.Sugar.Errorf("Error during batch: %w", err)
+				logger.SugarFromContext(ctx).Errorf("Error during batch: %v", err)
+				errs = append(errs, err)
 			}
 
-			logger.Sugar.Infof("Updated <%d> items", items)
+			logger.SugarFromContext(ctx).Infof("Updated <%d> items", items)
 
 			batch = make([]model.UUIDv7, 0, batchSize)
 		}
 	}
 
 last:
-	items, err := proceedBatch(ctx, r.db, batch)
+	if len(batch) > 0 {
+		items, err := proceedBatch(ctx, r.db, batch)
+		if err != nil {
+			logger.SugarFromContext(ctx).Errorf("Error during batch: %v", err)
+			errs = append(errs, err)
+		}
 
-	if err != nil {
-		logger.Sugar.Errorf("Error during batch: %w", err)
+		logger.SugarFromContext(ctx).Infof("Proceed batch last time: %d", items)
 	}
 
-	logger.Sugar.Infof("Proceed batch last time: %d", items)
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("BatchMarkAsDeleted: %w", err)
+	}
 
 	return nil
 }
@@ -351,7 +466,7 @@ last:
 func proceedBatch(ctx context.Context, db *sql.DB, ids []model.UUIDv7) (int, error) {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		logger.Sugar.Errorf("error creating tx error: %w", err)
+		logger.SugarFromContext(ctx).Errorf("error creating tx error: %v", err)
 
 		return 0, fmt.Errorf("error creating tx error: %w", err)
 	}
@@ -366,7 +481,7 @@ func proceedBatch(ctx context.Context, db *sql.DB, ids []model.UUIDv7) (int, err
 		args[i] = string(id)
 	}
 
-	query := "UPDATE links SET is_deleted = true WHERE id IN (" +
+	query := "UPDATE links SET is_deleted = true, deleted_at = now(), updated_at = now() WHERE id IN (" +
 		strings.Join(placeholders, ", ") +
 		") RETURNING id;"
 
@@ -376,7 +491,7 @@ func proceedBatch(ctx context.Context, db *sql.DB, ids []model.UUIDv7) (int, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		logger.Sugar.Errorf("error committing tx error: %w", err)
+		logger.SugarFromContext(ctx).Errorf("error committing tx error: %v", err)
 
 		return 0, fmt.Errorf("error committing tx error: %w", err)
 	}
@@ -388,3 +503,69 @@ func proceedBatch(ctx context.Context, db *sql.DB, ids []model.UUIDv7) (int, err
 
 	return len(deletedIDs), err
 }
+
+// UpdateLink changes link destination and stores revision in one transaction.
+func (r *LinksRepositoryPostgres) UpdateLink(ctx context.Context, rev model.LinkRevision) (model.LinkData, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.LinkData{}, fmt.Errorf("UpdateLink: %w", err)
+	}
+	defer tx.Rollback()
+
+	ld, err := scanLink(tx.QueryRowContext(ctx,
+		"UPDATE links SET long_url = $1, updated_at = now() WHERE id = $2 AND long_url = $3 AND NOT is_deleted RETURNING "+linkColumns,
+		rev.NewURL, rev.LinkID, rev.OldURL,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.LinkData{}, repository.ErrNotFoundLink
+		}
+
+		return model.LinkData{}, fmt.Errorf("UpdateLink: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO link_revisions (id, link_id, old_url, new_url, changed_by, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		rev.ID, rev.LinkID, rev.OldURL, rev.NewURL, rev.ChangedBy, ld.UpdatedAt,
+	)
+	if err != nil {
+		return model.LinkData{}, fmt.Errorf("UpdateLink revision: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.LinkData{}, fmt.Errorf("UpdateLink commit: %w", err)
+	}
+
+	r.replicas.markWrite(rev.ChangedBy)
+
+	return ld, nil
+}
+
+// SelectLinkRevisions selects link revisions ordered from oldest to newest.
+func (r *LinksRepositoryPostgres) SelectLinkRevisions(ctx context.Context, linkID model.UUIDv7) ([]model.LinkRevision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, link_id, old_url, new_url, changed_by, created_at FROM link_revisions WHERE link_id = $1 ORDER BY created_at, id`,
+		linkID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("SelectLinkRevisions: %w", err)
+	}
+	defer rows.Close()
+
+	revs := make([]model.LinkRevision, 0, 1)
+
+	for rows.Next() {
+		var rev model.LinkRevision
+		if err := rows.Scan(&rev.ID, &rev.LinkID, &rev.OldURL, &rev.NewURL, &rev.ChangedBy, &rev.CreatedAt); err != nil {
+			return revs, fmt.Errorf("SelectLinkRevisions: %w", err)
+		}
+
+		revs = append(revs, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return revs, fmt.Errorf("SelectLinkRevisions: %w", err)
+	}
+
+	return revs, nil
+}
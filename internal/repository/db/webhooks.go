@@ -0,0 +1,204 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+const (
+	// webhookColumns columns of webhooks table scanned by scanWebhook.
+	webhookColumns = "id, user_id, workspace_id, url, secret, events, created_at"
+	// deliveryColumns columns of webhook_deliveries table scanned by scanDelivery.
+	deliveryColumns = "id, webhook_id, event_id, event_type, payload, status, response_code, last_error, attempts, created_at, updated_at"
+)
+
+// CreateWebhook stores webhook.
+func (r *LinksRepositoryPostgres) CreateWebhook(ctx context.Context, wh model.Webhook) (model.Webhook, error) {
+	wh, err := scanWebhook(r.db.QueryRowContext(ctx,
+		`INSERT INTO webhooks (id, user_id, workspace_id, url, secret, events) VALUES ($1, $2, $3, $4, $5, $6) RETURNING `+webhookColumns,
+		wh.ID, wh.UserID, nullWorkspaceID(wh.WorkspaceID), wh.URL, wh.Secret, wh.Events.Join(),
+	))
+	if err != nil {
+		return wh, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	return wh, nil
+}
+
+// SelectWebhook selects webhook by id.
+func (r *LinksRepositoryPostgres) SelectWebhook(ctx context.Context, id model.WebhookID) (model.Webhook, error) {
+	wh, err := scanWebhook(r.db.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return wh, repository.ErrNotFoundWebhook
+	}
+
+	if err != nil {
+		return wh, fmt.Errorf("SelectWebhook: %w", err)
+	}
+
+	return wh, nil
+}
+
+// SelectUserWebhooks selects all webhooks registered by user.
+func (r *LinksRepositoryPostgres) SelectUserWebhooks(ctx context.Context, userID model.UserID) ([]model.Webhook, error) {
+	return r.queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE user_id = $1 ORDER BY created_at", userID)
+}
+
+// SelectEventWebhooks selects webhooks receiving events of workspace or user personal links.
+func (r *LinksRepositoryPostgres) SelectEventWebhooks(ctx context.Context, userID model.UserID, wsID model.WorkspaceID) ([]model.Webhook, error) {
+	if wsID != "" {
+		return r.queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE workspace_id = $1", wsID)
+	}
+
+	return r.queryWebhooks(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE user_id = $1 AND workspace_id IS NULL", userID)
+}
+
+// DeleteWebhook removes webhook, deliveries are removed by cascade.
+func (r *LinksRepositoryPostgres) DeleteWebhook(ctx context.Context, id model.WebhookID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return repository.ErrNotFoundWebhook
+	}
+
+	return nil
+}
+
+// CreateDelivery stores pending delivery.
+func (r *LinksRepositoryPostgres) CreateDelivery(ctx context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	d, err := scanDelivery(r.db.QueryRowContext(ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6) RETURNING `+deliveryColumns,
+		d.ID, d.WebhookID, d.EventID, d.EventType, d.Payload, d.Status,
+	))
+	if err != nil {
+		return d, fmt.Errorf("CreateDelivery: %w", err)
+	}
+
+	return d, nil
+}
+
+// UpdateDelivery saves delivery attempt result.
+func (r *LinksRepositoryPostgres) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = $2, response_code = $3, last_error = $4, attempts = $5, updated_at = now() WHERE id = $1`,
+		d.ID, d.Status, d.ResponseCode, d.LastError, d.Attempts,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateDelivery: %w", err)
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return repository.ErrNotFoundDelivery
+	}
+
+	return nil
+}
+
+// SelectDelivery selects delivery by id.
+func (r *LinksRepositoryPostgres) SelectDelivery(ctx context.Context, id model.UUIDv7) (model.WebhookDelivery, error) {
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, repository.ErrNotFoundDelivery
+	}
+
+	if err != nil {
+		return d, fmt.Errorf("SelectDelivery: %w", err)
+	}
+
+	return d, nil
+}
+
+// SelectDeliveries selects latest deliveries of webhook.
+func (r *LinksRepositoryPostgres) SelectDeliveries(ctx context.Context, webhookID model.WebhookID, limit int) ([]model.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2",
+		webhookID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("SelectDeliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]model.WebhookDelivery, 0, limit)
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return deliveries, fmt.Errorf("SelectDeliveries: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return deliveries, fmt.Errorf("SelectDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *LinksRepositoryPostgres) queryWebhooks(ctx context.Context, query string, arg any) ([]model.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("queryWebhooks: %w", err)
+	}
+	defer rows.Close()
+
+	whs := make([]model.Webhook, 0, 1)
+
+	for rows.Next() {
+		wh, err := scanWebhook(rows)
+		if err != nil {
+			return whs, fmt.Errorf("queryWebhooks: %w", err)
+		}
+
+		whs = append(whs, wh)
+	}
+
+	if err := rows.Err(); err != nil {
+		return whs, fmt.Errorf("queryWebhooks: %w", err)
+	}
+
+	return whs, nil
+}
+
+func scanWebhook(row rowScanner) (model.Webhook, error) {
+	var (
+		wh     model.Webhook
+		wsID   sql.NullString
+		events string
+	)
+
+	if err := row.Scan(&wh.ID, &wh.UserID, &wsID, &wh.URL, &wh.Secret, &events, &wh.CreatedAt); err != nil {
+		return wh, fmt.Errorf("scanWebhook: %w", err)
+	}
+
+	if wsID.Valid {
+		wh.WorkspaceID = model.WorkspaceID(wsID.String)
+	}
+
+	wh.Events = model.ParseEventTypes(events)
+
+	return wh, nil
+}
+
+func scanDelivery(row rowScanner) (model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+		&d.ResponseCode, &d.LastError, &d.Attempts, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return d, fmt.Errorf("scanDelivery: %w", err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,192 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// nullWorkspaceID returns nil for links without workspace.
+func nullWorkspaceID(wsID model.WorkspaceID) any {
+	if wsID == "" {
+		return nil
+	}
+
+	return string(wsID)
+}
+
+// CreateWorkspace creates workspace and adds its owner as member with owner role.
+func (r *LinksRepositoryPostgres) CreateWorkspace(ctx context.Context, ws model.Workspace) (model.Workspace, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ws, fmt.Errorf("CreateWorkspace: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO users (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`, ws.OwnerID); err != nil {
+		return ws, fmt.Errorf("CreateWorkspace owner: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO workspaces (id, name, owner_id) VALUES ($1, $2, $3) RETURNING created_at`,
+		ws.ID, ws.Name, ws.OwnerID,
+	).Scan(&ws.CreatedAt)
+	if err != nil {
+		return ws, fmt.Errorf("CreateWorkspace: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`,
+		ws.ID, ws.OwnerID, model.RoleOwner, ws.CreatedAt,
+	)
+	if err != nil {
+		return ws, fmt.Errorf("CreateWorkspace member: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ws, fmt.Errorf("CreateWorkspace commit: %w", err)
+	}
+
+	r.replicas.markWrite(ws.OwnerID)
+
+	return ws, nil
+}
+
+// SelectWorkspaceMember selects user membership in workspace.
+// Membership is checked on primary so fresh invitations are applied immediately.
+func (r *LinksRepositoryPostgres) SelectWorkspaceMember(ctx context.Context, wsID model.WorkspaceID, userID model.UserID) (model.WorkspaceMember, error) {
+	member := model.WorkspaceMember{WorkspaceID: wsID, UserID: userID}
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT role, created_at FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		wsID, userID,
+	).Scan(&member.Role, &member.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return member, repository.ErrNotFoundMember
+		}
+
+		return member, fmt.Errorf("SelectWorkspaceMember: %w", err)
+	}
+
+	return member, nil
+}
+
+// SelectUserWorkspaces selects all user memberships.
+func (r *LinksRepositoryPostgres) SelectUserWorkspaces(ctx context.Context, userID model.UserID) ([]model.WorkspaceMember, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT workspace_id, user_id, role, created_at FROM workspace_members WHERE user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("SelectUserWorkspaces: %w", err)
+	}
+	defer rows.Close()
+
+	members := make([]model.WorkspaceMember, 0, 1)
+
+	for rows.Next() {
+		var member model.WorkspaceMember
+		if err := rows.Scan(&member.WorkspaceID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			return members, fmt.Errorf("SelectUserWorkspaces: %w", err)
+		}
+
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return members, fmt.Errorf("SelectUserWorkspaces: %w", err)
+	}
+
+	return members, nil
+}
+
+// SelectWorkspaceLinks selects all links owned by workspace.
+func (r *LinksRepositoryPostgres) SelectWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID) ([]model.LinkData, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+linkColumns+" FROM links WHERE workspace_id = $1", wsID)
+	if err != nil {
+		return nil, fmt.Errorf("SelectWorkspaceLinks: %w", err)
+	}
+	defer rows.Close()
+
+	lds, err := collectLinks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("SelectWorkspaceLinks: %w", err)
+	}
+
+	return lds, nil
+}
+
+// CreateInvitation stores workspace invitation.
+func (r *LinksRepositoryPostgres) CreateInvitation(ctx context.Context, inv model.WorkspaceInvitation) (model.WorkspaceInvitation, error) {
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO workspace_invitations (token, workspace_id, role, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING created_at`,
+		inv.Token, inv.WorkspaceID, inv.Role, inv.InvitedBy, inv.ExpiresAt,
+	).Scan(&inv.CreatedAt)
+	if err != nil {
+		return inv, fmt.Errorf("CreateInvitation: %w", err)
+	}
+
+	return inv, nil
+}
+
+// AcceptInvitation consumes invitation and adds user to workspace.
+// Existing membership is kept as is.
+func (r *LinksRepositoryPostgres) AcceptInvitation(ctx context.Context, token string, userID model.UserID, acceptedAt time.Time) (model.WorkspaceMember, error) {
+	member := model.WorkspaceMember{UserID: userID}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return member, fmt.Errorf("AcceptInvitation: %w", err)
+	}
+	defer tx.Rollback()
+
+	var expiresAt time.Time
+
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM workspace_invitations WHERE token = $1 RETURNING workspace_id, role, expires_at`,
+		token,
+	).Scan(&member.WorkspaceID, &member.Role, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return member, repository.ErrNotFoundInvitation
+		}
+
+		return member, fmt.Errorf("AcceptInvitation: %w", err)
+	}
+
+	if !acceptedAt.Before(expiresAt) {
+		// удаление просроченного приглашения сохраняется.
+		if err := tx.Commit(); err != nil {
+			return member, fmt.Errorf("AcceptInvitation commit: %w", err)
+		}
+
+		return member, repository.ErrExpiredInvitation
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO users (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`, userID); err != nil {
+		return member, fmt.Errorf("AcceptInvitation user: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = workspace_members.role
+		RETURNING role, created_at`,
+		member.WorkspaceID, userID, member.Role, acceptedAt,
+	).Scan(&member.Role, &member.CreatedAt)
+	if err != nil {
+		return member, fmt.Errorf("AcceptInvitation member: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return member, fmt.Errorf("AcceptInvitation commit: %w", err)
+	}
+
+	return member, nil
+}
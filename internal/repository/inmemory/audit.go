@@ -0,0 +1,118 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// AuditRepositoryMemory - runtime audit events storage with indexes by user, action and short url.
+type AuditRepositoryMemory struct {
+	byUser   map[model.UserID][]int
+	byAction map[string][]int
+	byShort  map[string][]int
+	events   []model.AuditEvent
+	mu       sync.RWMutex
+}
+
+// NewInMemoryAuditRepository - provide new instance AuditRepositoryMemory.
+func NewInMemoryAuditRepository() *AuditRepositoryMemory {
+	return &AuditRepositoryMemory{
+		byUser:   make(map[model.UserID][]int),
+		byAction: make(map[string][]int),
+		byShort:  make(map[string][]int),
+	}
+}
+
+// InsertAuditEvents stores events assigning increasing ids.
+func (r *AuditRepositoryMemory) InsertAuditEvents(_ context.Context, events []model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ev := range events {
+		ev.ID = int64(len(r.events)) + 1
+		r.add(ev)
+	}
+
+	return nil
+}
+
+// Load adds events with already assigned increasing ids, e.g. restored from file.
+func (r *AuditRepositoryMemory) Load(events ...model.AuditEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ev := range events {
+		r.add(ev)
+	}
+}
+
+func (r *AuditRepositoryMemory) add(ev model.AuditEvent) {
+	pos := len(r.events)
+	r.events = append(r.events, ev)
+
+	r.byUser[ev.UserID] = append(r.byUser[ev.UserID], pos)
+	r.byAction[ev.Action] = append(r.byAction[ev.Action], pos)
+
+	if ev.ShortURL != "" {
+		r.byShort[ev.ShortURL] = append(r.byShort[ev.ShortURL], pos)
+	}
+}
+
+// SelectAuditEvents selects events matching filter, newest first.
+func (r *AuditRepositoryMemory) SelectAuditEvents(_ context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]model.AuditEvent, 0)
+
+	// позиции в индексах возрастают, поэтому обход с конца выдаёт события от новых к старым.
+	positions, indexed := r.candidates(filter)
+
+	n := len(r.events)
+	if indexed {
+		n = len(positions)
+	}
+
+	for i := n - 1; i >= 0 && (filter.Limit <= 0 || len(res) < filter.Limit); i-- {
+		pos := i
+		if indexed {
+			pos = positions[i]
+		}
+
+		ev := r.events[pos]
+		if filter.Cursor > 0 && ev.ID >= filter.Cursor {
+			continue
+		}
+
+		if filter.Match(ev) {
+			res = append(res, ev)
+		}
+	}
+
+	return res, nil
+}
+
+// candidates returns the shortest index matching filter, indexed is false if filter has no indexed fields.
+func (r *AuditRepositoryMemory) candidates(filter model.AuditFilter) (positions []int, indexed bool) {
+	pick := func(index []int) {
+		if !indexed || len(index) < len(positions) {
+			positions, indexed = index, true
+		}
+	}
+
+	if filter.UserID != "" {
+		pick(r.byUser[filter.UserID])
+	}
+
+	if filter.Action != "" {
+		pick(r.byAction[filter.Action])
+	}
+
+	if filter.ShortURL != "" {
+		pick(r.byShort[filter.ShortURL])
+	}
+
+	return positions, indexed
+}
@@ -0,0 +1,128 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// JobsRepositoryMemory - runtime jobs storage, jobs are lost on restart.
+type JobsRepositoryMemory struct {
+	jobs map[model.UUIDv7]model.Job
+	mu   sync.Mutex
+}
+
+// NewInMemoryJobsRepository - provide new instance JobsRepositoryMemory.
+func NewInMemoryJobsRepository() *JobsRepositoryMemory {
+	return &JobsRepositoryMemory{jobs: make(map[model.UUIDv7]model.Job)}
+}
+
+// CreateJob stores new job.
+func (r *JobsRepositoryMemory) CreateJob(_ context.Context, job model.Job) (model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	r.jobs[job.ID] = job
+
+	return job, nil
+}
+
+// ClaimJob marks the oldest ready pending job as running.
+func (r *JobsRepositoryMemory) ClaimJob(_ context.Context, now time.Time) (model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		claimed model.Job
+		found   bool
+	)
+
+	for _, job := range r.jobs {
+		if job.Status != model.JobPending || job.RunAt.After(now) {
+			continue
+		}
+
+		if !found || job.RunAt.Before(claimed.RunAt) {
+			claimed, found = job, true
+		}
+	}
+
+	if !found {
+		return model.Job{}, repository.ErrNoJobs
+	}
+
+	claimed.Status = model.JobRunning
+	claimed.Attempts++
+	claimed.UpdatedAt = time.Now()
+	r.jobs[claimed.ID] = claimed
+
+	return claimed, nil
+}
+
+// UpdateJob saves job state.
+func (r *JobsRepositoryMemory) UpdateJob(_ context.Context, job model.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.jobs[job.ID]; !ok {
+		return repository.ErrNotFoundJob
+	}
+
+	job.UpdatedAt = time.Now()
+	r.jobs[job.ID] = job
+
+	return nil
+}
+
+// SelectJob selects job by id.
+func (r *JobsRepositoryMemory) SelectJob(_ context.Context, id model.UUIDv7) (model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return job, repository.ErrNotFoundJob
+	}
+
+	return job, nil
+}
+
+// TouchJob extends lease of running job.
+func (r *JobsRepositoryMemory) TouchJob(_ context.Context, id model.UUIDv7) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok || job.Status != model.JobRunning {
+		return repository.ErrNotFoundJob
+	}
+
+	job.UpdatedAt = time.Now()
+	r.jobs[id] = job
+
+	return nil
+}
+
+// ResetRunningJobs returns running jobs with expired lease to pending.
+func (r *JobsRepositoryMemory) ResetRunningJobs(_ context.Context, lease time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reset := 0
+	expired := time.Now().Add(-lease)
+
+	for id, job := range r.jobs {
+		if job.Status == model.JobRunning && job.UpdatedAt.Before(expired) {
+			job.Status = model.JobPending
+			r.jobs[id] = job
+			reset++
+		}
+	}
+
+	return reset, nil
+}
@@ -3,7 +3,9 @@ package inmemory
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/Pklerik/urlshortener/internal/dictionary"
 	"github.com/Pklerik/urlshortener/internal/model"
@@ -12,17 +14,29 @@ import (
 
 // LinksRepositoryMemory - simple in memory storage.
 type LinksRepositoryMemory struct {
-	Shorts map[string]*model.LinkData
-	Users  []model.User
-	mu     sync.RWMutex
+	Shorts      map[string]*model.LinkData
+	Workspaces  map[model.WorkspaceID]model.Workspace
+	Members     map[model.WorkspaceID]map[model.UserID]model.WorkspaceMember
+	Invitations map[string]model.WorkspaceInvitation
+	Revisions   map[model.UUIDv7][]model.LinkRevision
+	Webhooks    map[model.WebhookID]model.Webhook
+	Deliveries  map[model.UUIDv7]model.WebhookDelivery
+	Users       []model.User
+	mu          sync.RWMutex
 }
 
 // NewInMemoryLinksRepository - provide new instance InMemoryLinksRepository
 // Creates capacity based on config.
 func NewInMemoryLinksRepository() *LinksRepositoryMemory {
 	return &LinksRepositoryMemory{
-		Shorts: make(map[string]*model.LinkData, dictionary.MapSize),
-		Users:  make([]model.User, dictionary.MapSize),
+		Shorts:      make(map[string]*model.LinkData, dictionary.MapSize),
+		Workspaces:  make(map[model.WorkspaceID]model.Workspace),
+		Members:     make(map[model.WorkspaceID]map[model.UserID]model.WorkspaceMember),
+		Invitations: make(map[string]model.WorkspaceInvitation),
+		Revisions:   make(map[model.UUIDv7][]model.LinkRevision),
+		Webhooks:    make(map[model.WebhookID]model.Webhook),
+		Deliveries:  make(map[model.UUIDv7]model.WebhookDelivery),
+		Users:       make([]model.User, dictionary.MapSize),
 	}
 }
 
@@ -31,15 +45,20 @@ func (r *LinksRepositoryMemory) SetLinks(_ context.Context, links []model.LinkDa
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	now := time.Now()
+	inserted := make([]model.LinkData, 0, len(links))
+
 	for _, linkData := range links {
 		if _, ok := r.Shorts[linkData.ShortURL]; ok {
 			continue
 		}
 
+		linkData.CreatedAt, linkData.UpdatedAt = now, now
+		inserted = append(inserted, linkData)
 		r.Shorts[linkData.ShortURL] = &linkData
 	}
 
-	return links, nil
+	return inserted, nil
 }
 
 // FindShort - provide model.LinkData and error
@@ -88,10 +107,107 @@ func (r *LinksRepositoryMemory) CreateUser(_ context.Context, userID model.UserI
 	return user, nil
 }
 
-// BatchMarkAsDeleted not implemented.
-func (r *LinksRepositoryMemory) BatchMarkAsDeleted(_ context.Context, _ chan model.LinkData) error {
+// BatchMarkAsDeleted marks all links provided by linkCh as deleted.
+// linkCh is read until closed, so producer is never blocked, links received after ctx is done are skipped.
+func (r *LinksRepositoryMemory) BatchMarkAsDeleted(ctx context.Context, linkCh chan model.LinkData) error {
+	for linkData := range linkCh {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		r.mu.Lock()
+		if stored, ok := r.Shorts[linkData.ShortURL]; ok && stored.UUID == linkData.UUID {
+			stored.MarkDeleted(time.Now())
+		}
+		r.mu.Unlock()
+	}
+
+	return nil
+}
+
+// UpdateLink changes link destination and stores revision.
+func (r *LinksRepositoryMemory) UpdateLink(_ context.Context, rev model.LinkRevision) (model.LinkData, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	return nil
+	for _, linkData := range r.Shorts {
+		if linkData.UUID != rev.LinkID {
+			continue
+		}
+
+		if linkData.IsDeleted || linkData.LongURL != rev.OldURL {
+			break
+		}
+
+		rev.CreatedAt = time.Now()
+		linkData.LongURL = rev.NewURL
+		linkData.UpdatedAt = rev.CreatedAt
+		r.Revisions[rev.LinkID] = append(r.Revisions[rev.LinkID], rev)
+
+		return *linkData, nil
+	}
+
+	return model.LinkData{}, repository.ErrNotFoundLink
+}
+
+// SelectLinkRevisions selects link revisions ordered from oldest to newest.
+func (r *LinksRepositoryMemory) SelectLinkRevisions(_ context.Context, linkID model.UUIDv7) ([]model.LinkRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.Revisions[linkID]), nil
+}
+
+// RestoreLinks unmarks links deleted not earlier than deletedAfter.
+func (r *LinksRepositoryMemory) RestoreLinks(_ context.Context, ids []model.UUIDv7, deletedAfter time.Time) ([]model.LinkData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	restored := make([]model.LinkData, 0, len(ids))
+	now := time.Now()
+
+	for _, linkData := range r.Shorts {
+		if !slices.Contains(ids, linkData.UUID) || !linkData.IsDeleted ||
+			linkData.DeletedAt == nil || linkData.DeletedAt.Before(deletedAfter) {
+			continue
+		}
+
+		linkData.IsDeleted = false
+		linkData.DeletedAt = nil
+		linkData.UpdatedAt = now
+		restored = append(restored, *linkData)
+	}
+
+	return restored, nil
+}
+
+// PurgeLinks permanently removes links.
+func (r *LinksRepositoryMemory) PurgeLinks(_ context.Context, ids []model.UUIDv7) ([]model.LinkData, error) {
+	return r.purge(func(ld *model.LinkData) bool { return slices.Contains(ids, ld.UUID) }), nil
+}
+
+// PurgeDeleted permanently removes links deleted before deletedBefore.
+func (r *LinksRepositoryMemory) PurgeDeleted(_ context.Context, deletedBefore time.Time) ([]model.LinkData, error) {
+	return r.purge(func(ld *model.LinkData) bool {
+		return ld.IsDeleted && ld.DeletedAt != nil && ld.DeletedAt.Before(deletedBefore)
+	}), nil
+}
+
+func (r *LinksRepositoryMemory) purge(match func(ld *model.LinkData) bool) []model.LinkData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := make([]model.LinkData, 0)
+
+	for short, linkData := range r.Shorts {
+		if !match(linkData) {
+			continue
+		}
+
+		purged = append(purged, *linkData)
+		delete(r.Shorts, short)
+		delete(r.Revisions, linkData.UUID)
+	}
+
+	return purged
 }
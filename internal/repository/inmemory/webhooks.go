@@ -0,0 +1,150 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// CreateWebhook stores webhook.
+func (r *LinksRepositoryMemory) CreateWebhook(_ context.Context, wh model.Webhook) (model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wh.CreatedAt = time.Now()
+	r.Webhooks[wh.ID] = wh
+
+	return wh, nil
+}
+
+// SelectWebhook selects webhook by id.
+func (r *LinksRepositoryMemory) SelectWebhook(_ context.Context, id model.WebhookID) (model.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wh, ok := r.Webhooks[id]
+	if !ok {
+		return wh, repository.ErrNotFoundWebhook
+	}
+
+	return wh, nil
+}
+
+// SelectUserWebhooks selects all webhooks registered by user.
+func (r *LinksRepositoryMemory) SelectUserWebhooks(_ context.Context, userID model.UserID) ([]model.Webhook, error) {
+	return r.filterWebhooks(func(wh model.Webhook) bool { return wh.UserID == userID }), nil
+}
+
+// SelectEventWebhooks selects webhooks receiving events of workspace or user personal links.
+func (r *LinksRepositoryMemory) SelectEventWebhooks(_ context.Context, userID model.UserID, wsID model.WorkspaceID) ([]model.Webhook, error) {
+	return r.filterWebhooks(func(wh model.Webhook) bool { return wh.Receives(userID, wsID) }), nil
+}
+
+// DeleteWebhook removes webhook with its deliveries.
+func (r *LinksRepositoryMemory) DeleteWebhook(_ context.Context, id model.WebhookID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.Webhooks[id]; !ok {
+		return repository.ErrNotFoundWebhook
+	}
+
+	delete(r.Webhooks, id)
+
+	for deliveryID, d := range r.Deliveries {
+		if d.WebhookID == id {
+			delete(r.Deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+// CreateDelivery stores pending delivery.
+func (r *LinksRepositoryMemory) CreateDelivery(_ context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = d.CreatedAt
+	r.Deliveries[d.ID] = d
+
+	return d, nil
+}
+
+// UpdateDelivery saves delivery attempt result.
+func (r *LinksRepositoryMemory) UpdateDelivery(_ context.Context, d model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.Deliveries[d.ID]
+	if !ok {
+		return repository.ErrNotFoundDelivery
+	}
+
+	stored.Status, stored.ResponseCode, stored.LastError, stored.Attempts = d.Status, d.ResponseCode, d.LastError, d.Attempts
+	stored.UpdatedAt = time.Now()
+	r.Deliveries[d.ID] = stored
+
+	return nil
+}
+
+// SelectDelivery selects delivery by id.
+func (r *LinksRepositoryMemory) SelectDelivery(_ context.Context, id model.UUIDv7) (model.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.Deliveries[id]
+	if !ok {
+		return d, repository.ErrNotFoundDelivery
+	}
+
+	return d, nil
+}
+
+// SelectDeliveries selects latest deliveries of webhook.
+func (r *LinksRepositoryMemory) SelectDeliveries(_ context.Context, webhookID model.WebhookID, limit int) ([]model.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := make([]model.WebhookDelivery, 0)
+
+	for _, d := range r.Deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	return latestDeliveries(deliveries, limit), nil
+}
+
+func (r *LinksRepositoryMemory) filterWebhooks(match func(wh model.Webhook) bool) []model.Webhook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	whs := make([]model.Webhook, 0)
+
+	for _, wh := range r.Webhooks {
+		if match(wh) {
+			whs = append(whs, wh)
+		}
+	}
+
+	slices.SortFunc(whs, func(a, b model.Webhook) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	return whs
+}
+
+// latestDeliveries sorts deliveries newest first and cuts to limit.
+func latestDeliveries(deliveries []model.WebhookDelivery, limit int) []model.WebhookDelivery {
+	slices.SortFunc(deliveries, func(a, b model.WebhookDelivery) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries
+}
@@ -0,0 +1,115 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// CreateWorkspace creates workspace and adds its owner as member with owner role.
+func (r *LinksRepositoryMemory) CreateWorkspace(_ context.Context, ws model.Workspace) (model.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ws.CreatedAt = time.Now()
+	r.Workspaces[ws.ID] = ws
+	r.Members[ws.ID] = map[model.UserID]model.WorkspaceMember{
+		ws.OwnerID: {CreatedAt: ws.CreatedAt, WorkspaceID: ws.ID, UserID: ws.OwnerID, Role: model.RoleOwner},
+	}
+
+	return ws, nil
+}
+
+// SelectWorkspaceMember selects user membership in workspace.
+func (r *LinksRepositoryMemory) SelectWorkspaceMember(_ context.Context, wsID model.WorkspaceID, userID model.UserID) (model.WorkspaceMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	member, ok := r.Members[wsID][userID]
+	if !ok {
+		return model.WorkspaceMember{WorkspaceID: wsID, UserID: userID}, repository.ErrNotFoundMember
+	}
+
+	return member, nil
+}
+
+// SelectUserWorkspaces selects all user memberships.
+func (r *LinksRepositoryMemory) SelectUserWorkspaces(_ context.Context, userID model.UserID) ([]model.WorkspaceMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]model.WorkspaceMember, 0)
+
+	for _, wsMembers := range r.Members {
+		if member, ok := wsMembers[userID]; ok {
+			members = append(members, member)
+		}
+	}
+
+	return members, nil
+}
+
+// SelectWorkspaceLinks selects all links owned by workspace.
+func (r *LinksRepositoryMemory) SelectWorkspaceLinks(_ context.Context, wsID model.WorkspaceID) ([]model.LinkData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lds := make([]model.LinkData, 0)
+
+	for _, linkData := range r.Shorts {
+		if linkData.WorkspaceID == wsID {
+			lds = append(lds, *linkData)
+		}
+	}
+
+	return lds, nil
+}
+
+// CreateInvitation stores workspace invitation.
+func (r *LinksRepositoryMemory) CreateInvitation(_ context.Context, inv model.WorkspaceInvitation) (model.WorkspaceInvitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.Workspaces[inv.WorkspaceID]; !ok {
+		return inv, repository.ErrNotFoundWorkspace
+	}
+
+	inv.CreatedAt = time.Now()
+	r.Invitations[inv.Token] = inv
+
+	return inv, nil
+}
+
+// AcceptInvitation consumes invitation and adds user to workspace.
+// Existing membership is kept as is.
+func (r *LinksRepositoryMemory) AcceptInvitation(_ context.Context, token string, userID model.UserID, acceptedAt time.Time) (model.WorkspaceMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inv, ok := r.Invitations[token]
+	if !ok {
+		return model.WorkspaceMember{UserID: userID}, repository.ErrNotFoundInvitation
+	}
+
+	delete(r.Invitations, token)
+
+	if inv.Expired(acceptedAt) {
+		return model.WorkspaceMember{WorkspaceID: inv.WorkspaceID, UserID: userID}, repository.ErrExpiredInvitation
+	}
+
+	if member, ok := r.Members[inv.WorkspaceID][userID]; ok {
+		return member, nil
+	}
+
+	member := model.WorkspaceMember{CreatedAt: acceptedAt, WorkspaceID: inv.WorkspaceID, UserID: userID, Role: inv.Role}
+
+	if r.Members[inv.WorkspaceID] == nil {
+		r.Members[inv.WorkspaceID] = make(map[model.UserID]model.WorkspaceMember)
+	}
+
+	r.Members[inv.WorkspaceID][userID] = member
+
+	return member, nil
+}
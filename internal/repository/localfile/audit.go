@@ -0,0 +1,120 @@
+package localfile
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/goccy/go-json"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+)
+
+// AuditRepositoryFile - audit events storage appended to local json lines file.
+// Events are indexed in memory, file is read only on start.
+type AuditRepositoryFile struct {
+	index  *inmemory.AuditRepositoryMemory
+	File   string
+	lastID int64
+	mu     sync.Mutex
+}
+
+// NewLocalAuditRepository - provide new instance AuditRepositoryFile with events loaded from file.
+func NewLocalAuditRepository(filePath string) *AuditRepositoryFile {
+	r := &AuditRepositoryFile{
+		index: inmemory.NewInMemoryAuditRepository(),
+		File:  createStorageFile(filePath),
+	}
+
+	if err := r.load(); err != nil {
+		logger.Sugar.Errorf("Error loading audit events from <%s>: %v", r.File, err)
+	}
+
+	return r
+}
+
+// InsertAuditEvents appends events to file and index.
+func (r *AuditRepositoryFile) InsertAuditEvents(_ context.Context, events []model.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]model.AuditEvent, 0, len(events))
+
+	var buf bytes.Buffer
+
+	for _, ev := range events {
+		ev.ID = r.lastID + int64(len(stored)) + 1
+
+		line, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("InsertAuditEvents: %w", err)
+		}
+
+		buf.Write(line)
+		buf.WriteByte('\n')
+
+		stored = append(stored, ev)
+	}
+
+	f, err := os.OpenFile(r.File, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("InsertAuditEvents: %w", err)
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("InsertAuditEvents: %w", errors.Join(err, f.Close()))
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("InsertAuditEvents: %w", err)
+	}
+
+	r.lastID += int64(len(stored))
+	r.index.Load(stored...)
+
+	return nil
+}
+
+// SelectAuditEvents selects events matching filter, newest first.
+func (r *AuditRepositoryFile) SelectAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	events, err := r.index.SelectAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("SelectAuditEvents: %w", err)
+	}
+
+	return events, nil
+}
+
+// load restores index from file. Malformed lines, e.g. torn last write, are skipped.
+func (r *AuditRepositoryFile) load() error {
+	f, err := os.Open(r.File)
+	if err != nil {
+		return fmt.Errorf("(r *AuditRepositoryFile) load: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var ev model.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil || ev.ID <= r.lastID {
+			continue
+		}
+
+		r.lastID = ev.ID
+		r.index.Load(ev)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("(r *AuditRepositoryFile) load: %w", err)
+	}
+
+	return nil
+}
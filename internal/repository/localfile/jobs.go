@@ -0,0 +1,203 @@
+package localfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// JobsRepositoryFile - jobs storage in local json file.
+type JobsRepositoryFile struct {
+	File string
+	mu   sync.Mutex
+}
+
+// NewLocalJobsRepository - provide new instance JobsRepositoryFile.
+func NewLocalJobsRepository(filePath string) *JobsRepositoryFile {
+	return &JobsRepositoryFile{
+		File: createStorageFile(filePath),
+	}
+}
+
+// CreateJob stores new job.
+func (r *JobsRepositoryFile) CreateJob(_ context.Context, job model.Job) (model.Job, error) {
+	err := r.update(func(jobs []model.Job) ([]model.Job, error) {
+		job.CreatedAt = time.Now()
+		job.UpdatedAt = job.CreatedAt
+
+		return append(jobs, job), nil
+	})
+	if err != nil {
+		return job, fmt.Errorf("CreateJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimJob marks the oldest ready pending job as running.
+func (r *JobsRepositoryFile) ClaimJob(_ context.Context, now time.Time) (model.Job, error) {
+	var claimed model.Job
+
+	err := r.update(func(jobs []model.Job) ([]model.Job, error) {
+		idx := -1
+
+		for i, job := range jobs {
+			if job.Status != model.JobPending || job.RunAt.After(now) {
+				continue
+			}
+
+			if idx < 0 || job.RunAt.Before(jobs[idx].RunAt) {
+				idx = i
+			}
+		}
+
+		if idx < 0 {
+			return jobs, repository.ErrNoJobs
+		}
+
+		jobs[idx].Status = model.JobRunning
+		jobs[idx].Attempts++
+		jobs[idx].UpdatedAt = time.Now()
+		claimed = jobs[idx]
+
+		return jobs, nil
+	})
+	if err != nil {
+		return claimed, fmt.Errorf("ClaimJob: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// UpdateJob saves job state.
+func (r *JobsRepositoryFile) UpdateJob(_ context.Context, job model.Job) error {
+	err := r.update(func(jobs []model.Job) ([]model.Job, error) {
+		i := slices.IndexFunc(jobs, func(j model.Job) bool { return j.ID == job.ID })
+		if i < 0 {
+			return jobs, repository.ErrNotFoundJob
+		}
+
+		job.UpdatedAt = time.Now()
+		jobs[i] = job
+
+		return jobs, nil
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateJob: %w", err)
+	}
+
+	return nil
+}
+
+// SelectJob selects job by id.
+func (r *JobsRepositoryFile) SelectJob(_ context.Context, id model.UUIDv7) (model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs, err := r.read()
+	if err != nil {
+		return model.Job{}, fmt.Errorf("SelectJob: %w", err)
+	}
+
+	i := slices.IndexFunc(jobs, func(j model.Job) bool { return j.ID == id })
+	if i < 0 {
+		return model.Job{}, repository.ErrNotFoundJob
+	}
+
+	return jobs[i], nil
+}
+
+// TouchJob extends lease of running job.
+func (r *JobsRepositoryFile) TouchJob(_ context.Context, id model.UUIDv7) error {
+	err := r.update(func(jobs []model.Job) ([]model.Job, error) {
+		i := slices.IndexFunc(jobs, func(j model.Job) bool { return j.ID == id && j.Status == model.JobRunning })
+		if i < 0 {
+			return jobs, repository.ErrNotFoundJob
+		}
+
+		jobs[i].UpdatedAt = time.Now()
+
+		return jobs, nil
+	})
+	if err != nil {
+		return fmt.Errorf("TouchJob: %w", err)
+	}
+
+	return nil
+}
+
+// ResetRunningJobs returns running jobs with expired lease to pending.
+func (r *JobsRepositoryFile) ResetRunningJobs(_ context.Context, lease time.Duration) (int, error) {
+	reset := 0
+	expired := time.Now().Add(-lease)
+
+	err := r.update(func(jobs []model.Job) ([]model.Job, error) {
+		for i := range jobs {
+			if jobs[i].Status == model.JobRunning && jobs[i].UpdatedAt.Before(expired) {
+				jobs[i].Status = model.JobPending
+				reset++
+			}
+		}
+
+		return jobs, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ResetRunningJobs: %w", err)
+	}
+
+	return reset, nil
+}
+
+// update reads jobs, applies fn and writes result back under one lock.
+// Nothing is written if fn returns error.
+func (r *JobsRepositoryFile) update(fn func(jobs []model.Job) ([]model.Job, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs, err := r.read()
+	if err != nil {
+		return err
+	}
+
+	jobs, err = fn(jobs)
+	if err != nil {
+		return err
+	}
+
+	bytesData, err := json.MarshalIndent(jobs, "", "    ")
+	if err != nil {
+		return fmt.Errorf("unable Marshal jobs: %w", err)
+	}
+
+	if err := os.WriteFile(r.File, bytesData, 0600); err != nil {
+		return fmt.Errorf("unable Write jobs to file: %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobsRepositoryFile) read() ([]model.Job, error) {
+	slByte, err := os.ReadFile(r.File)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %w", err)
+	}
+
+	jobs := make([]model.Job, 0)
+	if len(slByte) == 0 {
+		return jobs, nil
+	}
+
+	if err := json.Unmarshal(slByte, &jobs); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal jobs: %w", err)
+	}
+
+	return jobs, nil
+}
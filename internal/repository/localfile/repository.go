@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/goccy/go-json"
 
@@ -25,13 +27,26 @@ type LinksRepositoryFile struct {
 
 // FullData - all service data.
 type FullData struct {
-	Users map[model.UserID]model.User `json:"users"`
-	Links []model.LinkData            `json:"links"`
+	Users       map[model.UserID]model.User `json:"users"`
+	Links       []model.LinkData            `json:"links"`
+	Workspaces  []model.Workspace           `json:"workspaces,omitempty"`
+	Members     []model.WorkspaceMember     `json:"workspace_members,omitempty"`
+	Invitations []model.WorkspaceInvitation `json:"workspace_invitations,omitempty"`
+	Revisions   []model.LinkRevision        `json:"link_revisions,omitempty"`
+	Webhooks    []model.Webhook             `json:"webhooks,omitempty"`
+	Deliveries  []model.WebhookDelivery     `json:"webhook_deliveries,omitempty"`
 }
 
 // NewLocalMemoryLinksRepository - provide new instance LocalMemoryLinksRepository
 // Creates capacity based on config.
 func NewLocalMemoryLinksRepository(filePath string) *LinksRepositoryFile {
+	return &LinksRepositoryFile{
+		File: createStorageFile(filePath),
+	}
+}
+
+// createStorageFile resolves filePath relative to dictionary.BasePath and creates file if absent.
+func createStorageFile(filePath string) string {
 	logger.Sugar.Info("args file path: ", filePath)
 
 	basePath := dictionary.BasePath
@@ -44,16 +59,16 @@ func NewLocalMemoryLinksRepository(filePath string) *LinksRepositoryFile {
 
 	logger.Sugar.Info("Set full path: ", fullPath)
 
-	_, err := os.OpenFile(filepath.Clean(fullPath), os.O_RDONLY|os.O_CREATE, 0600)
+	f, err := os.OpenFile(filepath.Clean(fullPath), os.O_RDONLY|os.O_CREATE, 0600)
 	if err != nil {
-		logger.Sugar.Fatalf("error creating storage file:%v : %w", fullPath, err)
+		logger.Sugar.Fatalf("error creating storage file: %v: %v", fullPath, err)
 	}
 
+	f.Close()
+
 	logger.Sugar.Info("Creating file by path: ", fullPath)
 
-	return &LinksRepositoryFile{
-		File: fullPath,
-	}
+	return fullPath
 }
 
 // SetLinks - writes linkData pointer to internal LocalMemoryLinksRepository map Shorts.
@@ -63,23 +78,25 @@ func (r *LinksRepositoryFile) SetLinks(_ context.Context, links []model.LinkData
 		return []model.LinkData{}, fmt.Errorf("unable to crate link: %w", err)
 	}
 
+	now := time.Now()
+	inserted := make([]model.LinkData, 0, len(links))
+
 	for _, linkData := range links {
 		_, ok := slContains(linkData.ShortURL, fullData.Links)
 		if ok {
 			continue
 		}
 
+		linkData.CreatedAt, linkData.UpdatedAt = now, now
+		inserted = append(inserted, linkData)
 		fullData.Links = append(fullData.Links, linkData)
 		fullData.Users[linkData.UserID] = model.User{ID: linkData.UserID}
 	}
-	// асинхронная запись в фаил.
-	go func() {
-		if err := r.Write(fullData); err != nil {
-			logger.Sugar.Errorf("unable to save file: %w", err)
-		}
-	}()
+	if err := r.Write(fullData); err != nil {
+		return []model.LinkData{}, fmt.Errorf("unable to crate link: %w", err)
+	}
 
-	return links, nil
+	return inserted, nil
 }
 
 // FindShort - provide model.LinkData and error
@@ -150,8 +167,20 @@ func slContains(shortURL string, slLinkData []model.LinkData) (model.LinkData, b
 	return model.LinkData{}, false
 }
 
-// PingDB returns ping info from db.
+// PingDB returns error if storage file is not writable.
 func (r *LinksRepositoryFile) PingDB(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, err := os.OpenFile(filepath.Clean(r.File), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("PingDB: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("PingDB: %w", err)
+	}
+
 	return nil
 }
 
@@ -183,16 +212,179 @@ func (r *LinksRepositoryFile) CreateUser(_ context.Context, userID model.UserID)
 	user := model.User{ID: userID}
 	data.Users[user.ID] = user
 
-	go func() {
-		if err := r.Write(data); err != nil {
-			logger.Sugar.Errorf("unable to save file: %w", err)
-		}
-	}()
+	if err := r.Write(data); err != nil {
+		return model.User{}, fmt.Errorf("CreateUser: %w", err)
+	}
 
 	return user, nil
 }
 
-// BatchMarkAsDeleted not implemented.
-func (r *LinksRepositoryFile) BatchMarkAsDeleted(_ context.Context, _ chan model.LinkData) error {
+// BatchMarkAsDeleted marks all links provided by linkCh as deleted.
+// linkCh is read until closed, so producer is never blocked, links received after ctx is done are skipped.
+func (r *LinksRepositoryFile) BatchMarkAsDeleted(ctx context.Context, linkCh chan model.LinkData) error {
+	ids := make(map[model.UUIDv7]struct{})
+
+	for linkData := range linkCh {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		ids[linkData.UUID] = struct{}{}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	data, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("BatchMarkAsDeleted: %w", err)
+	}
+
+	now := time.Now()
+
+	for i := range data.Links {
+		if _, ok := ids[data.Links[i].UUID]; ok {
+			data.Links[i].MarkDeleted(now)
+		}
+	}
+
+	if err := r.Write(data); err != nil {
+		return fmt.Errorf("BatchMarkAsDeleted: %w", err)
+	}
+
 	return nil
 }
+
+// UpdateLink changes link destination and stores revision.
+func (r *LinksRepositoryFile) UpdateLink(_ context.Context, rev model.LinkRevision) (model.LinkData, error) {
+	data, err := r.Read()
+	if err != nil {
+		return model.LinkData{}, fmt.Errorf("UpdateLink: %w", err)
+	}
+
+	i := slices.IndexFunc(data.Links, func(ld model.LinkData) bool { return ld.UUID == rev.LinkID })
+	if i < 0 || data.Links[i].IsDeleted || data.Links[i].LongURL != rev.OldURL {
+		return model.LinkData{}, repository.ErrNotFoundLink
+	}
+
+	rev.CreatedAt = time.Now()
+	data.Links[i].LongURL = rev.NewURL
+	data.Links[i].UpdatedAt = rev.CreatedAt
+	data.Revisions = append(data.Revisions, rev)
+
+	if err := r.Write(data); err != nil {
+		return model.LinkData{}, fmt.Errorf("UpdateLink: %w", err)
+	}
+
+	return data.Links[i], nil
+}
+
+// SelectLinkRevisions selects link revisions ordered from oldest to newest.
+func (r *LinksRepositoryFile) SelectLinkRevisions(_ context.Context, linkID model.UUIDv7) ([]model.LinkRevision, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("SelectLinkRevisions: %w", err)
+	}
+
+	revs := make([]model.LinkRevision, 0)
+
+	for _, rev := range data.Revisions {
+		if rev.LinkID == linkID {
+			revs = append(revs, rev)
+		}
+	}
+
+	return revs, nil
+}
+
+// RestoreLinks unmarks links deleted not earlier than deletedAfter.
+func (r *LinksRepositoryFile) RestoreLinks(_ context.Context, ids []model.UUIDv7, deletedAfter time.Time) ([]model.LinkData, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("RestoreLinks: %w", err)
+	}
+
+	restored := make([]model.LinkData, 0, len(ids))
+	now := time.Now()
+
+	for i := range data.Links {
+		ld := &data.Links[i]
+		if !slices.Contains(ids, ld.UUID) || !ld.IsDeleted || ld.DeletedAt == nil || ld.DeletedAt.Before(deletedAfter) {
+			continue
+		}
+
+		ld.IsDeleted = false
+		ld.DeletedAt = nil
+		ld.UpdatedAt = now
+		restored = append(restored, *ld)
+	}
+
+	if len(restored) == 0 {
+		return restored, nil
+	}
+
+	if err := r.Write(data); err != nil {
+		return nil, fmt.Errorf("RestoreLinks: %w", err)
+	}
+
+	return restored, nil
+}
+
+// PurgeLinks permanently removes links.
+func (r *LinksRepositoryFile) PurgeLinks(_ context.Context, ids []model.UUIDv7) ([]model.LinkData, error) {
+	purged, err := r.purge(func(ld model.LinkData) bool { return slices.Contains(ids, ld.UUID) })
+	if err != nil {
+		return nil, fmt.Errorf("PurgeLinks: %w", err)
+	}
+
+	return purged, nil
+}
+
+// PurgeDeleted permanently removes links deleted before deletedBefore.
+func (r *LinksRepositoryFile) PurgeDeleted(_ context.Context, deletedBefore time.Time) ([]model.LinkData, error) {
+	purged, err := r.purge(func(ld model.LinkData) bool {
+		return ld.IsDeleted && ld.DeletedAt != nil && ld.DeletedAt.Before(deletedBefore)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PurgeDeleted: %w", err)
+	}
+
+	return purged, nil
+}
+
+func (r *LinksRepositoryFile) purge(match func(ld model.LinkData) bool) ([]model.LinkData, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make([]model.LinkData, 0)
+	purgedIDs := make(map[model.UUIDv7]struct{})
+
+	data.Links = slices.DeleteFunc(data.Links, func(ld model.LinkData) bool {
+		if !match(ld) {
+			return false
+		}
+
+		purged = append(purged, ld)
+		purgedIDs[ld.UUID] = struct{}{}
+
+		return true
+	})
+
+	if len(purged) == 0 {
+		return purged, nil
+	}
+
+	data.Revisions = slices.DeleteFunc(data.Revisions, func(rev model.LinkRevision) bool {
+		_, ok := purgedIDs[rev.LinkID]
+		return ok
+	})
+
+	if err := r.Write(data); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,183 @@
+package localfile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// CreateWebhook stores webhook.
+func (r *LinksRepositoryFile) CreateWebhook(_ context.Context, wh model.Webhook) (model.Webhook, error) {
+	data, err := r.Read()
+	if err != nil {
+		return wh, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	wh.CreatedAt = time.Now()
+	data.Webhooks = append(data.Webhooks, wh)
+
+	if err := r.Write(data); err != nil {
+		return wh, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	return wh, nil
+}
+
+// SelectWebhook selects webhook by id.
+func (r *LinksRepositoryFile) SelectWebhook(_ context.Context, id model.WebhookID) (model.Webhook, error) {
+	data, err := r.Read()
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("SelectWebhook: %w", err)
+	}
+
+	i := slices.IndexFunc(data.Webhooks, func(wh model.Webhook) bool { return wh.ID == id })
+	if i < 0 {
+		return model.Webhook{}, repository.ErrNotFoundWebhook
+	}
+
+	return data.Webhooks[i], nil
+}
+
+// SelectUserWebhooks selects all webhooks registered by user.
+func (r *LinksRepositoryFile) SelectUserWebhooks(_ context.Context, userID model.UserID) ([]model.Webhook, error) {
+	whs, err := r.filterWebhooks(func(wh model.Webhook) bool { return wh.UserID == userID })
+	if err != nil {
+		return whs, fmt.Errorf("SelectUserWebhooks: %w", err)
+	}
+
+	return whs, nil
+}
+
+// SelectEventWebhooks selects webhooks receiving events of workspace or user personal links.
+func (r *LinksRepositoryFile) SelectEventWebhooks(_ context.Context, userID model.UserID, wsID model.WorkspaceID) ([]model.Webhook, error) {
+	whs, err := r.filterWebhooks(func(wh model.Webhook) bool { return wh.Receives(userID, wsID) })
+	if err != nil {
+		return whs, fmt.Errorf("SelectEventWebhooks: %w", err)
+	}
+
+	return whs, nil
+}
+
+// DeleteWebhook removes webhook with its deliveries.
+func (r *LinksRepositoryFile) DeleteWebhook(_ context.Context, id model.WebhookID) error {
+	data, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	before := len(data.Webhooks)
+
+	data.Webhooks = slices.DeleteFunc(data.Webhooks, func(wh model.Webhook) bool { return wh.ID == id })
+	if len(data.Webhooks) == before {
+		return repository.ErrNotFoundWebhook
+	}
+
+	data.Deliveries = slices.DeleteFunc(data.Deliveries, func(d model.WebhookDelivery) bool { return d.WebhookID == id })
+
+	if err := r.Write(data); err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	return nil
+}
+
+// CreateDelivery stores pending delivery.
+func (r *LinksRepositoryFile) CreateDelivery(_ context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	data, err := r.Read()
+	if err != nil {
+		return d, fmt.Errorf("CreateDelivery: %w", err)
+	}
+
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = d.CreatedAt
+	data.Deliveries = append(data.Deliveries, d)
+
+	if err := r.Write(data); err != nil {
+		return d, fmt.Errorf("CreateDelivery: %w", err)
+	}
+
+	return d, nil
+}
+
+// UpdateDelivery saves delivery attempt result.
+func (r *LinksRepositoryFile) UpdateDelivery(_ context.Context, d model.WebhookDelivery) error {
+	data, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("UpdateDelivery: %w", err)
+	}
+
+	i := slices.IndexFunc(data.Deliveries, func(stored model.WebhookDelivery) bool { return stored.ID == d.ID })
+	if i < 0 {
+		return repository.ErrNotFoundDelivery
+	}
+
+	stored := &data.Deliveries[i]
+	stored.Status, stored.ResponseCode, stored.LastError, stored.Attempts = d.Status, d.ResponseCode, d.LastError, d.Attempts
+	stored.UpdatedAt = time.Now()
+
+	if err := r.Write(data); err != nil {
+		return fmt.Errorf("UpdateDelivery: %w", err)
+	}
+
+	return nil
+}
+
+// SelectDelivery selects delivery by id.
+func (r *LinksRepositoryFile) SelectDelivery(_ context.Context, id model.UUIDv7) (model.WebhookDelivery, error) {
+	data, err := r.Read()
+	if err != nil {
+		return model.WebhookDelivery{}, fmt.Errorf("SelectDelivery: %w", err)
+	}
+
+	i := slices.IndexFunc(data.Deliveries, func(d model.WebhookDelivery) bool { return d.ID == id })
+	if i < 0 {
+		return model.WebhookDelivery{}, repository.ErrNotFoundDelivery
+	}
+
+	return data.Deliveries[i], nil
+}
+
+// SelectDeliveries selects latest deliveries of webhook.
+func (r *LinksRepositoryFile) SelectDeliveries(_ context.Context, webhookID model.WebhookID, limit int) ([]model.WebhookDelivery, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("SelectDeliveries: %w", err)
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0)
+
+	for _, d := range data.Deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	slices.SortFunc(deliveries, func(a, b model.WebhookDelivery) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (r *LinksRepositoryFile) filterWebhooks(match func(wh model.Webhook) bool) ([]model.Webhook, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	whs := make([]model.Webhook, 0)
+
+	for _, wh := range data.Webhooks {
+		if match(wh) {
+			whs = append(whs, wh)
+		}
+	}
+
+	return whs, nil
+}
@@ -0,0 +1,145 @@
+package localfile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// CreateWorkspace creates workspace and adds its owner as member with owner role.
+func (r *LinksRepositoryFile) CreateWorkspace(_ context.Context, ws model.Workspace) (model.Workspace, error) {
+	data, err := r.Read()
+	if err != nil {
+		return ws, fmt.Errorf("CreateWorkspace: %w", err)
+	}
+
+	ws.CreatedAt = time.Now()
+	data.Workspaces = append(data.Workspaces, ws)
+	data.Members = append(data.Members, model.WorkspaceMember{
+		CreatedAt:   ws.CreatedAt,
+		WorkspaceID: ws.ID,
+		UserID:      ws.OwnerID,
+		Role:        model.RoleOwner,
+	})
+
+	if err := r.Write(data); err != nil {
+		return ws, fmt.Errorf("CreateWorkspace: %w", err)
+	}
+
+	return ws, nil
+}
+
+// SelectWorkspaceMember selects user membership in workspace.
+func (r *LinksRepositoryFile) SelectWorkspaceMember(_ context.Context, wsID model.WorkspaceID, userID model.UserID) (model.WorkspaceMember, error) {
+	data, err := r.Read()
+	if err != nil {
+		return model.WorkspaceMember{}, fmt.Errorf("SelectWorkspaceMember: %w", err)
+	}
+
+	if i := memberIndex(data.Members, wsID, userID); i >= 0 {
+		return data.Members[i], nil
+	}
+
+	return model.WorkspaceMember{WorkspaceID: wsID, UserID: userID}, repository.ErrNotFoundMember
+}
+
+// SelectUserWorkspaces selects all user memberships.
+func (r *LinksRepositoryFile) SelectUserWorkspaces(_ context.Context, userID model.UserID) ([]model.WorkspaceMember, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("SelectUserWorkspaces: %w", err)
+	}
+
+	members := make([]model.WorkspaceMember, 0)
+
+	for _, member := range data.Members {
+		if member.UserID == userID {
+			members = append(members, member)
+		}
+	}
+
+	return members, nil
+}
+
+// SelectWorkspaceLinks selects all links owned by workspace.
+func (r *LinksRepositoryFile) SelectWorkspaceLinks(_ context.Context, wsID model.WorkspaceID) ([]model.LinkData, error) {
+	data, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("SelectWorkspaceLinks: %w", err)
+	}
+
+	lds := make([]model.LinkData, 0)
+
+	for _, linkData := range data.Links {
+		if linkData.WorkspaceID == wsID {
+			lds = append(lds, linkData)
+		}
+	}
+
+	return lds, nil
+}
+
+// CreateInvitation stores workspace invitation.
+func (r *LinksRepositoryFile) CreateInvitation(_ context.Context, inv model.WorkspaceInvitation) (model.WorkspaceInvitation, error) {
+	data, err := r.Read()
+	if err != nil {
+		return inv, fmt.Errorf("CreateInvitation: %w", err)
+	}
+
+	if !slices.ContainsFunc(data.Workspaces, func(ws model.Workspace) bool { return ws.ID == inv.WorkspaceID }) {
+		return inv, repository.ErrNotFoundWorkspace
+	}
+
+	inv.CreatedAt = time.Now()
+	data.Invitations = append(data.Invitations, inv)
+
+	if err := r.Write(data); err != nil {
+		return inv, fmt.Errorf("CreateInvitation: %w", err)
+	}
+
+	return inv, nil
+}
+
+// AcceptInvitation consumes invitation and adds user to workspace.
+// Existing membership is kept as is.
+func (r *LinksRepositoryFile) AcceptInvitation(_ context.Context, token string, userID model.UserID, acceptedAt time.Time) (model.WorkspaceMember, error) {
+	data, err := r.Read()
+	if err != nil {
+		return model.WorkspaceMember{}, fmt.Errorf("AcceptInvitation: %w", err)
+	}
+
+	i := slices.IndexFunc(data.Invitations, func(inv model.WorkspaceInvitation) bool { return inv.Token == token })
+	if i < 0 {
+		return model.WorkspaceMember{UserID: userID}, repository.ErrNotFoundInvitation
+	}
+
+	inv := data.Invitations[i]
+	data.Invitations = slices.Delete(data.Invitations, i, i+1)
+
+	member := model.WorkspaceMember{CreatedAt: acceptedAt, WorkspaceID: inv.WorkspaceID, UserID: userID, Role: inv.Role}
+
+	switch j := memberIndex(data.Members, inv.WorkspaceID, userID); {
+	case inv.Expired(acceptedAt):
+		err = repository.ErrExpiredInvitation
+	case j >= 0:
+		member = data.Members[j]
+	default:
+		data.Members = append(data.Members, member)
+	}
+
+	if werr := r.Write(data); werr != nil {
+		return member, fmt.Errorf("AcceptInvitation: %w", werr)
+	}
+
+	return member, err
+}
+
+func memberIndex(members []model.WorkspaceMember, wsID model.WorkspaceID, userID model.UserID) int {
+	return slices.IndexFunc(members, func(member model.WorkspaceMember) bool {
+		return member.WorkspaceID == wsID && member.UserID == userID
+	})
+}
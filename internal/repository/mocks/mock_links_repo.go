@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/Pklerik/urlshortener/internal/model"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,21 @@ func (m *MockLinksRepository) EXPECT() *MockLinksRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AcceptInvitation mocks base method.
+func (m *MockLinksRepository) AcceptInvitation(ctx context.Context, token string, userID model.UserID, acceptedAt time.Time) (model.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, token, userID, acceptedAt)
+	ret0, _ := ret[0].(model.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockLinksRepositoryMockRecorder) AcceptInvitation(ctx, token, userID, acceptedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockLinksRepository)(nil).AcceptInvitation), ctx, token, userID, acceptedAt)
+}
+
 // BatchMarkAsDeleted mocks base method.
 func (m *MockLinksRepository) BatchMarkAsDeleted(ctx context.Context, links chan model.LinkData) error {
 	m.ctrl.T.Helper()
@@ -49,6 +65,36 @@ func (mr *MockLinksRepositoryMockRecorder) BatchMarkAsDeleted(ctx, links interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchMarkAsDeleted", reflect.TypeOf((*MockLinksRepository)(nil).BatchMarkAsDeleted), ctx, links)
 }
 
+// CreateDelivery mocks base method.
+func (m *MockLinksRepository) CreateDelivery(ctx context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, d)
+	ret0, _ := ret[0].(model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockLinksRepositoryMockRecorder) CreateDelivery(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockLinksRepository)(nil).CreateDelivery), ctx, d)
+}
+
+// CreateInvitation mocks base method.
+func (m *MockLinksRepository) CreateInvitation(ctx context.Context, inv model.WorkspaceInvitation) (model.WorkspaceInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, inv)
+	ret0, _ := ret[0].(model.WorkspaceInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockLinksRepositoryMockRecorder) CreateInvitation(ctx, inv interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockLinksRepository)(nil).CreateInvitation), ctx, inv)
+}
+
 // CreateUser mocks base method.
 func (m *MockLinksRepository) CreateUser(ctx context.Context, userID model.UserID) (model.User, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +110,50 @@ func (mr *MockLinksRepositoryMockRecorder) CreateUser(ctx, userID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockLinksRepository)(nil).CreateUser), ctx, userID)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockLinksRepository) CreateWebhook(ctx context.Context, wh model.Webhook) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, wh)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockLinksRepositoryMockRecorder) CreateWebhook(ctx, wh interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockLinksRepository)(nil).CreateWebhook), ctx, wh)
+}
+
+// CreateWorkspace mocks base method.
+func (m *MockLinksRepository) CreateWorkspace(ctx context.Context, ws model.Workspace) (model.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", ctx, ws)
+	ret0, _ := ret[0].(model.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockLinksRepositoryMockRecorder) CreateWorkspace(ctx, ws interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockLinksRepository)(nil).CreateWorkspace), ctx, ws)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockLinksRepository) DeleteWebhook(ctx context.Context, id model.WebhookID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockLinksRepositoryMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockLinksRepository)(nil).DeleteWebhook), ctx, id)
+}
+
 // FindShort mocks base method.
 func (m *MockLinksRepository) FindShort(ctx context.Context, short string) (model.LinkData, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +183,111 @@ func (mr *MockLinksRepositoryMockRecorder) PingDB(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingDB", reflect.TypeOf((*MockLinksRepository)(nil).PingDB), ctx)
 }
 
+// PurgeDeleted mocks base method.
+func (m *MockLinksRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, deletedBefore)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockLinksRepositoryMockRecorder) PurgeDeleted(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockLinksRepository)(nil).PurgeDeleted), ctx, deletedBefore)
+}
+
+// PurgeLinks mocks base method.
+func (m *MockLinksRepository) PurgeLinks(ctx context.Context, ids []model.UUIDv7) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeLinks", ctx, ids)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeLinks indicates an expected call of PurgeLinks.
+func (mr *MockLinksRepositoryMockRecorder) PurgeLinks(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeLinks", reflect.TypeOf((*MockLinksRepository)(nil).PurgeLinks), ctx, ids)
+}
+
+// RestoreLinks mocks base method.
+func (m *MockLinksRepository) RestoreLinks(ctx context.Context, ids []model.UUIDv7, deletedAfter time.Time) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLinks", ctx, ids, deletedAfter)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreLinks indicates an expected call of RestoreLinks.
+func (mr *MockLinksRepositoryMockRecorder) RestoreLinks(ctx, ids, deletedAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLinks", reflect.TypeOf((*MockLinksRepository)(nil).RestoreLinks), ctx, ids, deletedAfter)
+}
+
+// SelectDeliveries mocks base method.
+func (m *MockLinksRepository) SelectDeliveries(ctx context.Context, webhookID model.WebhookID, limit int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDeliveries", ctx, webhookID, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDeliveries indicates an expected call of SelectDeliveries.
+func (mr *MockLinksRepositoryMockRecorder) SelectDeliveries(ctx, webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDeliveries", reflect.TypeOf((*MockLinksRepository)(nil).SelectDeliveries), ctx, webhookID, limit)
+}
+
+// SelectDelivery mocks base method.
+func (m *MockLinksRepository) SelectDelivery(ctx context.Context, id model.UUIDv7) (model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDelivery", ctx, id)
+	ret0, _ := ret[0].(model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDelivery indicates an expected call of SelectDelivery.
+func (mr *MockLinksRepositoryMockRecorder) SelectDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDelivery", reflect.TypeOf((*MockLinksRepository)(nil).SelectDelivery), ctx, id)
+}
+
+// SelectEventWebhooks mocks base method.
+func (m *MockLinksRepository) SelectEventWebhooks(ctx context.Context, userID model.UserID, wsID model.WorkspaceID) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectEventWebhooks", ctx, userID, wsID)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectEventWebhooks indicates an expected call of SelectEventWebhooks.
+func (mr *MockLinksRepositoryMockRecorder) SelectEventWebhooks(ctx, userID, wsID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectEventWebhooks", reflect.TypeOf((*MockLinksRepository)(nil).SelectEventWebhooks), ctx, userID, wsID)
+}
+
+// SelectLinkRevisions mocks base method.
+func (m *MockLinksRepository) SelectLinkRevisions(ctx context.Context, linkID model.UUIDv7) ([]model.LinkRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectLinkRevisions", ctx, linkID)
+	ret0, _ := ret[0].([]model.LinkRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectLinkRevisions indicates an expected call of SelectLinkRevisions.
+func (mr *MockLinksRepositoryMockRecorder) SelectLinkRevisions(ctx, linkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectLinkRevisions", reflect.TypeOf((*MockLinksRepository)(nil).SelectLinkRevisions), ctx, linkID)
+}
+
 // SelectUserLinks mocks base method.
 func (m *MockLinksRepository) SelectUserLinks(ctx context.Context, userID model.UserID) ([]model.LinkData, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +303,81 @@ func (mr *MockLinksRepositoryMockRecorder) SelectUserLinks(ctx, userID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserLinks", reflect.TypeOf((*MockLinksRepository)(nil).SelectUserLinks), ctx, userID)
 }
 
+// SelectUserWebhooks mocks base method.
+func (m *MockLinksRepository) SelectUserWebhooks(ctx context.Context, userID model.UserID) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectUserWebhooks indicates an expected call of SelectUserWebhooks.
+func (mr *MockLinksRepositoryMockRecorder) SelectUserWebhooks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserWebhooks", reflect.TypeOf((*MockLinksRepository)(nil).SelectUserWebhooks), ctx, userID)
+}
+
+// SelectUserWorkspaces mocks base method.
+func (m *MockLinksRepository) SelectUserWorkspaces(ctx context.Context, userID model.UserID) ([]model.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserWorkspaces", ctx, userID)
+	ret0, _ := ret[0].([]model.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectUserWorkspaces indicates an expected call of SelectUserWorkspaces.
+func (mr *MockLinksRepositoryMockRecorder) SelectUserWorkspaces(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserWorkspaces", reflect.TypeOf((*MockLinksRepository)(nil).SelectUserWorkspaces), ctx, userID)
+}
+
+// SelectWebhook mocks base method.
+func (m *MockLinksRepository) SelectWebhook(ctx context.Context, id model.WebhookID) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWebhook", ctx, id)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWebhook indicates an expected call of SelectWebhook.
+func (mr *MockLinksRepositoryMockRecorder) SelectWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWebhook", reflect.TypeOf((*MockLinksRepository)(nil).SelectWebhook), ctx, id)
+}
+
+// SelectWorkspaceLinks mocks base method.
+func (m *MockLinksRepository) SelectWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWorkspaceLinks", ctx, wsID)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWorkspaceLinks indicates an expected call of SelectWorkspaceLinks.
+func (mr *MockLinksRepositoryMockRecorder) SelectWorkspaceLinks(ctx, wsID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWorkspaceLinks", reflect.TypeOf((*MockLinksRepository)(nil).SelectWorkspaceLinks), ctx, wsID)
+}
+
+// SelectWorkspaceMember mocks base method.
+func (m *MockLinksRepository) SelectWorkspaceMember(ctx context.Context, wsID model.WorkspaceID, userID model.UserID) (model.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWorkspaceMember", ctx, wsID, userID)
+	ret0, _ := ret[0].(model.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWorkspaceMember indicates an expected call of SelectWorkspaceMember.
+func (mr *MockLinksRepositoryMockRecorder) SelectWorkspaceMember(ctx, wsID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWorkspaceMember", reflect.TypeOf((*MockLinksRepository)(nil).SelectWorkspaceMember), ctx, wsID, userID)
+}
+
 // SetLinks mocks base method.
 func (m *MockLinksRepository) SetLinks(ctx context.Context, links []model.LinkData) ([]model.LinkData, error) {
 	m.ctrl.T.Helper()
@@ -122,3 +392,195 @@ func (mr *MockLinksRepositoryMockRecorder) SetLinks(ctx, links interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinks", reflect.TypeOf((*MockLinksRepository)(nil).SetLinks), ctx, links)
 }
+
+// UpdateDelivery mocks base method.
+func (m *MockLinksRepository) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockLinksRepositoryMockRecorder) UpdateDelivery(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockLinksRepository)(nil).UpdateDelivery), ctx, d)
+}
+
+// UpdateLink mocks base method.
+func (m *MockLinksRepository) UpdateLink(ctx context.Context, rev model.LinkRevision) (model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLink", ctx, rev)
+	ret0, _ := ret[0].(model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLink indicates an expected call of UpdateLink.
+func (mr *MockLinksRepositoryMockRecorder) UpdateLink(ctx, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLink", reflect.TypeOf((*MockLinksRepository)(nil).UpdateLink), ctx, rev)
+}
+
+// MockJobsRepository is a mock of JobsRepository interface.
+type MockJobsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobsRepositoryMockRecorder
+}
+
+// MockJobsRepositoryMockRecorder is the mock recorder for MockJobsRepository.
+type MockJobsRepositoryMockRecorder struct {
+	mock *MockJobsRepository
+}
+
+// NewMockJobsRepository creates a new mock instance.
+func NewMockJobsRepository(ctrl *gomock.Controller) *MockJobsRepository {
+	mock := &MockJobsRepository{ctrl: ctrl}
+	mock.recorder = &MockJobsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobsRepository) EXPECT() *MockJobsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimJob mocks base method.
+func (m *MockJobsRepository) ClaimJob(ctx context.Context, now time.Time) (model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJob", ctx, now)
+	ret0, _ := ret[0].(model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimJob indicates an expected call of ClaimJob.
+func (mr *MockJobsRepositoryMockRecorder) ClaimJob(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJob", reflect.TypeOf((*MockJobsRepository)(nil).ClaimJob), ctx, now)
+}
+
+// CreateJob mocks base method.
+func (m *MockJobsRepository) CreateJob(ctx context.Context, job model.Job) (model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, job)
+	ret0, _ := ret[0].(model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockJobsRepositoryMockRecorder) CreateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobsRepository)(nil).CreateJob), ctx, job)
+}
+
+// ResetRunningJobs mocks base method.
+func (m *MockJobsRepository) ResetRunningJobs(ctx context.Context, lease time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRunningJobs", ctx, lease)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetRunningJobs indicates an expected call of ResetRunningJobs.
+func (mr *MockJobsRepositoryMockRecorder) ResetRunningJobs(ctx, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRunningJobs", reflect.TypeOf((*MockJobsRepository)(nil).ResetRunningJobs), ctx, lease)
+}
+
+// SelectJob mocks base method.
+func (m *MockJobsRepository) SelectJob(ctx context.Context, id model.UUIDv7) (model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectJob", ctx, id)
+	ret0, _ := ret[0].(model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectJob indicates an expected call of SelectJob.
+func (mr *MockJobsRepositoryMockRecorder) SelectJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectJob", reflect.TypeOf((*MockJobsRepository)(nil).SelectJob), ctx, id)
+}
+
+// TouchJob mocks base method.
+func (m *MockJobsRepository) TouchJob(ctx context.Context, id model.UUIDv7) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchJob", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchJob indicates an expected call of TouchJob.
+func (mr *MockJobsRepositoryMockRecorder) TouchJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchJob", reflect.TypeOf((*MockJobsRepository)(nil).TouchJob), ctx, id)
+}
+
+// UpdateJob mocks base method.
+func (m *MockJobsRepository) UpdateJob(ctx context.Context, job model.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockJobsRepositoryMockRecorder) UpdateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockJobsRepository)(nil).UpdateJob), ctx, job)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// InsertAuditEvents mocks base method.
+func (m *MockAuditRepository) InsertAuditEvents(ctx context.Context, events []model.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditEvents", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditEvents indicates an expected call of InsertAuditEvents.
+func (mr *MockAuditRepositoryMockRecorder) InsertAuditEvents(ctx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditEvents", reflect.TypeOf((*MockAuditRepository)(nil).InsertAuditEvents), ctx, events)
+}
+
+// SelectAuditEvents mocks base method.
+func (m *MockAuditRepository) SelectAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAuditEvents", ctx, filter)
+	ret0, _ := ret[0].([]model.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAuditEvents indicates an expected call of SelectAuditEvents.
+func (mr *MockAuditRepositoryMockRecorder) SelectAuditEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAuditEvents", reflect.TypeOf((*MockAuditRepository)(nil).SelectAuditEvents), ctx, filter)
+}
@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"errors"
 
@@ -20,14 +21,89 @@ var (
 	ErrCollectingDBConf = errors.New("unable to collect DB conf")
 	// ErrExistingLink - link already in exist.
 	ErrExistingLink = errors.New("link already in exist")
+	// ErrNotFoundWorkspace - workspace was not found.
+	ErrNotFoundWorkspace = errors.New("workspace was not found")
+	// ErrNotFoundMember - user is not a member of workspace.
+	ErrNotFoundMember = errors.New("workspace member was not found")
+	// ErrNotFoundInvitation - invitation was not found or already accepted.
+	ErrNotFoundInvitation = errors.New("invitation was not found")
+	// ErrExpiredInvitation - invitation is expired.
+	ErrExpiredInvitation = errors.New("invitation is expired")
+	// ErrNoJobs - there is no pending jobs ready to run.
+	ErrNoJobs = errors.New("no pending jobs")
+	// ErrNotFoundJob - job was not found.
+	ErrNotFoundJob = errors.New("job was not found")
+	// ErrNotFoundWebhook - webhook was not found.
+	ErrNotFoundWebhook = errors.New("webhook was not found")
+	// ErrNotFoundDelivery - webhook delivery was not found.
+	ErrNotFoundDelivery = errors.New("webhook delivery was not found")
+	// ErrMigrationsPending - db schema is older than app migrations.
+	ErrMigrationsPending = errors.New("migrations are pending")
 )
 
 // LinksRepository - interface for shortener service.
 type LinksRepository interface {
+	// SetLinks stores links and returns only inserted ones. Links with existing short url are skipped,
+	// db storage returns ErrExistingLink for them.
 	SetLinks(ctx context.Context, links []model.LinkData) ([]model.LinkData, error)
 	FindShort(ctx context.Context, short string) (model.LinkData, error)
 	SelectUserLinks(ctx context.Context, userID model.UserID) ([]model.LinkData, error)
 	BatchMarkAsDeleted(ctx context.Context, links chan model.LinkData) error
 	CreateUser(ctx context.Context, userID model.UserID) (model.User, error)
 	PingDB(ctx context.Context) error
+	// UpdateLink changes link destination from rev.OldURL to rev.NewURL and stores revision.
+	// Returns ErrNotFoundLink if link is absent or destination was changed concurrently.
+	UpdateLink(ctx context.Context, rev model.LinkRevision) (model.LinkData, error)
+	SelectLinkRevisions(ctx context.Context, linkID model.UUIDv7) ([]model.LinkRevision, error)
+	// RestoreLinks unmarks links deleted not earlier than deletedAfter and returns restored links.
+	RestoreLinks(ctx context.Context, ids []model.UUIDv7, deletedAfter time.Time) ([]model.LinkData, error)
+	// PurgeLinks permanently removes links and returns removed links.
+	PurgeLinks(ctx context.Context, ids []model.UUIDv7) ([]model.LinkData, error)
+	// PurgeDeleted permanently removes links deleted before deletedBefore and returns removed links.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]model.LinkData, error)
+
+	CreateWorkspace(ctx context.Context, ws model.Workspace) (model.Workspace, error)
+	SelectWorkspaceMember(ctx context.Context, wsID model.WorkspaceID, userID model.UserID) (model.WorkspaceMember, error)
+	SelectUserWorkspaces(ctx context.Context, userID model.UserID) ([]model.WorkspaceMember, error)
+	SelectWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID) ([]model.LinkData, error)
+	CreateInvitation(ctx context.Context, inv model.WorkspaceInvitation) (model.WorkspaceInvitation, error)
+	// AcceptInvitation consumes not expired at acceptedAt invitation and adds user to workspace with invitation role.
+	AcceptInvitation(ctx context.Context, token string, userID model.UserID, acceptedAt time.Time) (model.WorkspaceMember, error)
+
+	CreateWebhook(ctx context.Context, wh model.Webhook) (model.Webhook, error)
+	SelectWebhook(ctx context.Context, id model.WebhookID) (model.Webhook, error)
+	SelectUserWebhooks(ctx context.Context, userID model.UserID) ([]model.Webhook, error)
+	// SelectEventWebhooks selects webhooks of workspace wsID or, if wsID is empty, personal webhooks of user.
+	SelectEventWebhooks(ctx context.Context, userID model.UserID, wsID model.WorkspaceID) ([]model.Webhook, error)
+	// DeleteWebhook removes webhook with its delivery log.
+	DeleteWebhook(ctx context.Context, id model.WebhookID) error
+	CreateDelivery(ctx context.Context, d model.WebhookDelivery) (model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error
+	SelectDelivery(ctx context.Context, id model.UUIDv7) (model.WebhookDelivery, error)
+	// SelectDeliveries selects latest deliveries of webhook, newest first.
+	SelectDeliveries(ctx context.Context, webhookID model.WebhookID, limit int) ([]model.WebhookDelivery, error)
+}
+
+// JobsRepository - durable storage for background jobs.
+type JobsRepository interface {
+	CreateJob(ctx context.Context, job model.Job) (model.Job, error)
+	// ClaimJob marks the oldest pending job with run_at not after now as running and increments attempts.
+	// Returns ErrNoJobs if there is nothing to run.
+	ClaimJob(ctx context.Context, now time.Time) (model.Job, error)
+	// UpdateJob saves status, attempts, last error and run_at of job.
+	UpdateJob(ctx context.Context, job model.Job) error
+	SelectJob(ctx context.Context, id model.UUIDv7) (model.Job, error)
+	// TouchJob extends lease of running job by updating its updated_at.
+	TouchJob(ctx context.Context, id model.UUIDv7) error
+	// ResetRunningJobs returns running jobs which were not touched for lease to pending.
+	// Jobs of live workers are touched more often and are kept.
+	ResetRunningJobs(ctx context.Context, lease time.Duration) (int, error)
+}
+
+// AuditRepository - searchable storage of audit events.
+type AuditRepository interface {
+	// InsertAuditEvents stores events assigning increasing ids.
+	InsertAuditEvents(ctx context.Context, events []model.AuditEvent) error
+	// SelectAuditEvents selects up to filter.Limit events matching filter, newest first.
+	SelectAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
 }
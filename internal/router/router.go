@@ -4,72 +4,196 @@ package router
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/Pklerik/urlshortener/internal/auditstore"
 	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/events"
 	"github.com/Pklerik/urlshortener/internal/handler"
+	"github.com/Pklerik/urlshortener/internal/health"
+	"github.com/Pklerik/urlshortener/internal/jobqueue"
+	"github.com/Pklerik/urlshortener/internal/lifecycle"
 	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/metrics"
 	"github.com/Pklerik/urlshortener/internal/middleware"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/netguard"
 	"github.com/Pklerik/urlshortener/internal/repository"
 	dbrepo "github.com/Pklerik/urlshortener/internal/repository/db"
 	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
 	"github.com/Pklerik/urlshortener/internal/repository/localfile"
 	"github.com/Pklerik/urlshortener/internal/service/links"
+	"github.com/Pklerik/urlshortener/internal/tracing"
+	"github.com/Pklerik/urlshortener/internal/webhook"
 	"github.com/go-chi/chi"
 	chimiddleware "github.com/go-chi/chi/middleware"
 )
 
+const (
+	// webhookTimeout timeout of single webhook delivery attempt.
+	webhookTimeout = 10 * time.Second
+	// healthCheckTimeout timeout of single readiness check.
+	healthCheckTimeout = 2 * time.Second
+)
+
 // ConfigureRouter starts server with base configuration.
-func ConfigureRouter(ctx context.Context, parsedFlags config.StartupFlagsParser) (http.Handler, error) {
-	var (
-		linksRepo repository.LinksRepository
-		err       error
-	)
+// Background tasks and resources release are registered in lc.
+// With admin address admin API and profiler are served only by returned admin handler, otherwise admin is nil.
+func ConfigureRouter(ctx context.Context, parsedFlags config.StartupFlagsParser, lc *lifecycle.Manager) (app, admin http.Handler, err error) {
+	var linksRepo repository.LinksRepository
 
 	r := chi.NewRouter()
 
-	linksRepo, err = chooseRepoRealization(ctx, parsedFlags)
+	linksRepo, err = chooseRepoRealization(ctx, parsedFlags, lc)
 	if err != nil {
-		return r, fmt.Errorf("ConfigureRouter: %w", err)
+		return r, nil, fmt.Errorf("ConfigureRouter: %w", err)
 	}
 
-	linksService := links.NewLinksService(linksRepo, parsedFlags.GetSecretKey())
+	auditRepo := chooseAuditRealization(linksRepo, parsedFlags)
+
+	bus := events.NewBus()
+	linksService := links.NewLinksService(linksRepo, parsedFlags.GetSecretKey(),
+		links.WithAuditRepository(auditRepo),
+		links.WithRestoreWindow(parsedFlags.GetRestoreWindow()),
+		links.WithPublisher(bus),
+		links.WithClickSampling(parsedFlags.GetClickSampleRate()),
+	)
+
+	jobQueue := jobqueue.New(chooseJobsRealization(linksRepo, parsedFlags),
+		jobqueue.WithWorkers(parsedFlags.GetJobWorkers()),
+		jobqueue.WithMaxAttempts(parsedFlags.GetJobMaxAttempts()),
+	)
+	jobQueue.Register(model.JobTypeDeleteLinks, linksService.HandleDeleteJob)
+
+	dispatcher := webhook.NewDispatcher(linksRepo, jobQueue, netguard.NewClient(webhookTimeout, net.DefaultResolver))
+	bus.Subscribe(dispatcher.HandleEvent)
+	jobQueue.Register(model.JobTypeWebhookDelivery, dispatcher.Deliver)
+
+	if err := jobQueue.Start(ctx); err != nil {
+		return r, nil, fmt.Errorf("ConfigureRouter: %w", err)
+	}
+
+	lc.OnShutdown("audit", logger.CloseAudit)
+	lc.OnShutdown("job queue", jobQueue.Drain)
+
+	tracedService := links.NewTracedLinkService(linksService)
+	authHandler := handler.NewAuthenticationHandler(tracedService)
+	linksHandler := handler.NewLinkHandler(tracedService, authHandler, jobQueue, parsedFlags)
+	workspaceHandler := handler.NewWorkspaceHandler(linksService, authHandler, parsedFlags)
+	webhookHandler := handler.NewWebhookHandler(linksService, authHandler)
+	auditStore := auditstore.NewRecorder(auditRepo)
+	lc.OnShutdown("audit store", auditStore.Close)
+
+	metrics.Default.NewGaugeFunc("audit_queue_depth", "Audit entries waiting for delivery to remote audit sinks.",
+		func() float64 { return float64(logger.AuditQueueDepth()) })
+	metrics.Default.NewGaugeFunc("audit_store_queue_depth", "Audit events waiting for saving to searchable audit store.",
+		func() float64 { return float64(auditStore.QueueLen()) })
+
+	if dbRepo, ok := linksRepo.(*dbrepo.LinksRepositoryPostgres); ok {
+		metrics.RegisterDBStats(dbRepo.Stats)
+	}
 
-	authHandler := handler.NewAuthenticationHandler(linksService)
-	linksHandler := handler.NewLinkHandler(linksService, authHandler, parsedFlags)
-	auditHandler := handler.NewAuditor(parsedFlags, authHandler)
+	auditHandler := handler.NewAuditor(parsedFlags, authHandler, auditStore)
+	adminHandler := handler.NewAdminHandler(linksService, parsedFlags.GetAdminToken())
+
+	if interval := parsedFlags.GetPurgeInterval(); interval > 0 {
+		lc.GoLoop("purge job", func(ctx context.Context) {
+			linksService.RunPurgeJob(ctx, interval, parsedFlags.GetPurgeRetention(), func(lds []model.LinkData) {
+				auditHandler.RecordLinks(model.AuditActionPurge, "system", lds)
+			})
+		})
+	}
+
+	checker := health.New(healthCheckTimeout, lc.ShuttingDown)
+	registerHealthChecks(checker, linksRepo)
+	healthHandler := handler.NewHealthHandler(checker)
+
+	// с mTLS внутренние API доступны только с проверенным клиентским сертификатом.
+	internalAPI := func(next http.Handler) http.Handler { return next }
+	if parsedFlags.GetTLS() && parsedFlags.GetTLSConf().MutualTLS() {
+		internalAPI = middleware.RequireClientCert
+	}
+
+	// HSTS не отправляется с самоподписанным сертификатом, иначе браузер запомнит его для хоста.
+	if parsedFlags.GetTLS() && !parsedFlags.GetTLSConf().SelfSigned() {
+		r.Use(middleware.HSTS(parsedFlags.GetTLSConf().GetHSTSHeader()))
+	}
+
+	adminRoutes := func(guard func(http.Handler) http.Handler) func(r chi.Router) {
+		return func(r chi.Router) {
+			r.With(auditHandler.Audit(model.AuditActionAdminPurge), guard, adminHandler.AdminAuth).Post("/urls/purge", adminHandler.PurgeLinks)
+			r.With(auditHandler.Audit(model.AuditActionAdminAudit), guard, adminHandler.AdminAuth).Get("/audit", adminHandler.GetAuditEvents)
+		}
+	}
+
+	// на публичном адресе профилировщик закрыт токеном администратора, отдельный admin listener доступен только изнутри.
+	publicAdmin := parsedFlags.GetAdminAddress() == ""
+	if publicAdmin {
+		r.With(internalAPI, adminHandler.AdminAuth).Mount("/debug", chimiddleware.Profiler())
+	} else {
+		adminRouter := chi.NewRouter()
+		adminRouter.Use(chimiddleware.RequestID, chimiddleware.RealIP, middleware.AccessLog(parsedFlags.GetRedirectLogSampleRate()), chimiddleware.Recoverer)
+		adminRouter.Mount("/debug", chimiddleware.Profiler())
+		adminRouter.Route("/api/admin", adminRoutes(func(next http.Handler) http.Handler { return next }))
+		admin = adminRouter
+	}
 
-	// Add pprof routes
-	r.Mount("/debug", chimiddleware.Profiler())
+	// пробы не проходят через авторизацию и метрики, чтобы не выдавать куки и не засорять статистику.
+	r.Get("/healthz", healthHandler.Liveness)
+	r.Get("/readyz", healthHandler.Readiness)
 
 	r.Group(func(r chi.Router) {
 		r.Use(
+			middleware.WithMetrics,
+			tracing.Middleware,
 			chimiddleware.RequestID,
 			chimiddleware.RealIP,
-			chimiddleware.Logger,
+			middleware.AccessLog(parsedFlags.GetRedirectLogSampleRate()),
 			chimiddleware.Recoverer,
 			middleware.GZIPMiddleware,
 			authHandler.AuthUser,
-			chimiddleware.Timeout(parsedFlags.GetTimeout()),
+			middleware.Timeout(parsedFlags.GetTimeout),
 		)
 		r.Route("/", func(r chi.Router) {
-			r.Group(func(r chi.Router) {
-				r.Use(auditHandler.AuditMiddleware)
-				r.Post("/", linksHandler.PostText)
-				r.Get("/{shortURL}", linksHandler.Get)
-			})
+			r.With(auditHandler.Audit(model.AuditActionShorten)).Post("/", linksHandler.PostText)
+			r.With(auditHandler.Audit(model.AuditActionFollow)).Get("/{shortURL}", linksHandler.Get)
 			r.Route("/api", func(r chi.Router) {
 				r.Route("/shorten", func(r chi.Router) {
-					r.Group(func(r chi.Router) {
-						r.Use(auditHandler.AuditMiddleware)
-						r.Post("/", linksHandler.PostJSON)
-					})
-					r.Post("/batch", linksHandler.PostBatchJSON)
+					r.With(auditHandler.Audit(model.AuditActionShorten)).Post("/", linksHandler.PostJSON)
+					r.With(auditHandler.Audit(model.AuditActionShortenBatch)).Post("/batch", linksHandler.PostBatchJSON)
 				})
 				r.Route("/user", func(r chi.Router) {
-					r.Get("/urls", linksHandler.GetUserLinks)
-					r.Delete("/urls", linksHandler.DeleteUserLinks)
+					r.With(auditHandler.Audit(model.AuditActionListURLs)).Get("/urls", linksHandler.GetUserLinks)
+					r.With(auditHandler.Audit(model.AuditActionDeleteURLs)).Delete("/urls", linksHandler.DeleteUserLinks)
+					r.With(auditHandler.Audit(model.AuditActionRestoreURLs)).Post("/urls/restore", linksHandler.RestoreUserLinks)
+					r.With(auditHandler.Audit(model.AuditActionEditURL)).Patch("/urls/{shortURL}", linksHandler.PatchUserLink)
+					r.Get("/urls/{shortURL}/revisions", linksHandler.GetLinkRevisions)
+					r.Route("/webhooks", func(r chi.Router) {
+						r.Post("/", webhookHandler.CreateWebhook)
+						r.Get("/", webhookHandler.GetWebhooks)
+						r.Delete("/{webhookID}", webhookHandler.DeleteWebhook)
+						r.Get("/{webhookID}/deliveries", webhookHandler.GetDeliveries)
+					})
+				})
+				r.Get("/jobs/{jobID}", linksHandler.GetJob)
+				if publicAdmin {
+					r.Route("/admin", adminRoutes(internalAPI))
+				}
+				r.Route("/workspaces", func(r chi.Router) {
+					r.Post("/", workspaceHandler.CreateWorkspace)
+					r.Get("/", workspaceHandler.GetWorkspaces)
+					r.Post("/invitations/{token}/accept", workspaceHandler.AcceptInvitation)
+					r.Route("/{workspaceID}", func(r chi.Router) {
+						r.Post("/invitations", workspaceHandler.CreateInvitation)
+						r.With(auditHandler.Audit(model.AuditActionWorkspaceShorten)).Post("/urls", workspaceHandler.PostWorkspaceLinks)
+						r.Get("/urls", workspaceHandler.GetWorkspaceLinks)
+						r.With(auditHandler.Audit(model.AuditActionWorkspaceDelete)).Delete("/urls", workspaceHandler.DeleteWorkspaceLinks)
+						r.With(auditHandler.Audit(model.AuditActionWorkspaceRestore)).Post("/urls/restore", workspaceHandler.RestoreWorkspaceLinks)
+					})
 				})
 			})
 			r.Get("/ping", linksHandler.PingDB)
@@ -78,7 +202,7 @@ func ConfigureRouter(ctx context.Context, parsedFlags config.StartupFlagsParser)
 
 	printRoutes(r)
 
-	return r, nil
+	return r, admin, nil
 }
 
 // Use chi.Walk to print all routes.
@@ -98,18 +222,37 @@ func printRoutes(r *chi.Mux) {
 	}
 }
 
-func chooseRepoRealization(ctx context.Context, parsedFlags config.StartupFlagsParser) (repository.LinksRepository, error) {
+// registerHealthChecks registers readiness checks of used storage and audit sinks.
+func registerHealthChecks(checker *health.Checker, linksRepo repository.LinksRepository) {
+	switch repo := linksRepo.(type) {
+	case *dbrepo.LinksRepositoryPostgres:
+		checker.Register("database", repo.PingDB)
+		checker.Register("migrations", repo.CheckMigrations)
+	case *localfile.LinksRepositoryFile:
+		checker.Register("file_storage", repo.PingDB)
+	}
+
+	checker.Register("audit", func(_ context.Context) error { return logger.AuditHealth() })
+}
+
+func chooseRepoRealization(ctx context.Context, parsedFlags config.StartupFlagsParser, lc *lifecycle.Manager) (repository.LinksRepository, error) {
 	dbConf, err := parsedFlags.GetDatabaseConf()
 	switch {
 	case err == nil:
 		logger.Sugar.Info("Used DB realization")
 
-		repo, err := dbrepo.NewDBLinksRepository(ctx, dbConf)
+		repo, err := dbrepo.NewDBLinksRepository(ctx, dbConf,
+			parsedFlags.GetDatabaseReplicaConfs(),
+			parsedFlags.GetReadYourWritesWindow(),
+			!parsedFlags.GetSkipMigrations(),
+		)
 		if err != nil {
 			logger.Sugar.Error(err)
 			return repo, fmt.Errorf("ConfigureRouter: %w", err)
 		}
 
+		lc.OnShutdown("database", func(_ context.Context) error { return repo.Close() })
+
 		return repo, nil
 	case parsedFlags.GetLocalStorage() != "":
 		logger.Sugar.Info("Used File realization")
@@ -121,3 +264,44 @@ func chooseRepoRealization(ctx context.Context, parsedFlags config.StartupFlagsP
 		return inmemory.NewInMemoryLinksRepository(), nil
 	}
 }
+
+// chooseJobsRealization provide jobs storage matching links storage.
+func chooseJobsRealization(linksRepo repository.LinksRepository, parsedFlags config.StartupFlagsParser) repository.JobsRepository {
+	if dbRepo, ok := linksRepo.(*dbrepo.LinksRepositoryPostgres); ok {
+		return dbRepo.JobsRepository()
+	}
+
+	jobsFile := parsedFlags.GetJobsFile()
+	if jobsFile == "" && parsedFlags.GetLocalStorage() != "" {
+		storage := parsedFlags.GetLocalStorage()
+		jobsFile = strings.TrimSuffix(storage, filepath.Ext(storage)) + "_jobs.json"
+	}
+
+	if jobsFile != "" {
+		logger.Sugar.Info("Used File jobs realization")
+
+		return localfile.NewLocalJobsRepository(jobsFile)
+	}
+
+	logger.Sugar.Info("Used InMemory jobs realization")
+
+	return inmemory.NewInMemoryJobsRepository()
+}
+
+// chooseAuditRealization provide audit events storage matching links storage.
+// Local storage file keeps audit events next to it in *_audit.json.
+func chooseAuditRealization(linksRepo repository.LinksRepository, parsedFlags config.StartupFlagsParser) repository.AuditRepository {
+	if dbRepo, ok := linksRepo.(*dbrepo.LinksRepositoryPostgres); ok {
+		return dbRepo.AuditRepository()
+	}
+
+	if storage := parsedFlags.GetLocalStorage(); storage != "" {
+		logger.Sugar.Info("Used File audit store realization")
+
+		return localfile.NewLocalAuditRepository(strings.TrimSuffix(storage, filepath.Ext(storage)) + "_audit.json")
+	}
+
+	logger.Sugar.Info("Used InMemory audit store realization")
+
+	return inmemory.NewInMemoryAuditRepository()
+}
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/Pklerik/urlshortener/internal/config"
+	"github.com/Pklerik/urlshortener/internal/lifecycle"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
@@ -33,12 +34,12 @@ func TestRegisterLinkHandler(t *testing.T) {
 	testJSONReq := []byte("{\"url\":\"http://ya.ru\"}")
 	testJSONResp := "\"result\""
 
-	r, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
+	r, _, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
 		BaseURL:      redirectHost,
 		LocalStorage: "../../local_storage.json",
 		DBConf:       nil,
 		Timeout:      10000,
-	})
+	}, lifecycle.New())
 	assert.NoError(t, err, "error setup router")
 
 	srv := httptest.NewServer(r)
@@ -217,7 +218,7 @@ func BenchmarkShortenerService(b *testing.B) {
 	// 	}
 	// 	dbc.Set(os.Getenv("DATABASE_DSN"))
 	// 	log.Println(dbc.String())
-	// 	r, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
+	// 	r, _, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
 	// 		ServerAddress: &config.Address{
 	// 			Protocol: "http",
 	// 			Host:     "localhost",
@@ -225,7 +226,7 @@ func BenchmarkShortenerService(b *testing.B) {
 	// 		BaseURL: "http://test_host:2345",
 	// 		DBConf:  dbc,
 	// 		Timeout: 10000,
-	// 	})
+	// 	}, lifecycle.New())
 	// 	assert.NoError(b, err, "error setup router")
 	// 	srv := httptest.NewServer(r)
 	// 	defer srv.Close()
@@ -244,14 +245,14 @@ func BenchmarkShortenerService(b *testing.B) {
 	// })
 
 	// b.Run("Shorten URLs local", func(b *testing.B) {
-	// 	r, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
+	// 	r, _, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
 	// 		ServerAddress: &config.Address{
 	// 			Protocol: "http",
 	// 			Host:     "localhost",
 	// 			Port:     16081},
 	// 		LocalStorage: "/Users/pavelbudkov/dev/urlshortener/local_storage.json",
 	// 		Timeout:      10000,
-	// 	})
+	// 	}, lifecycle.New())
 	// 	assert.NoError(b, err, "error setup router")
 	// 	srv := httptest.NewServer(r)
 	// 	defer srv.Close()
@@ -270,14 +271,14 @@ func BenchmarkShortenerService(b *testing.B) {
 
 	b.Run("Shorten URLs inmem", func(b *testing.B) {
 
-		r, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
+		r, _, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
 			ServerAddress: &config.Address{
 				Protocol: "http",
 				Host:     "localhost",
 				Port:     16082},
 			BaseURL: "http://test_host:2345",
 			Timeout: 10000,
-		})
+		}, lifecycle.New())
 		assert.NoError(b, err, "error setup router")
 		srv := httptest.NewServer(r)
 		defer srv.Close()
@@ -295,3 +296,98 @@ func BenchmarkShortenerService(b *testing.B) {
 	})
 
 }
+
+func TestHealthProbes(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	lc := lifecycle.New()
+	r, _, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
+		BaseURL: "http://test_host:2345",
+		Timeout: 10,
+	}, lc)
+	assert.NoError(t, err, "error setup router")
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	client := resty.New()
+
+	resp, err := client.R().Get(srv.URL + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Empty(t, resp.Cookies(), "probes are not authenticated")
+
+	resp, err = client.R().Get(srv.URL + "/readyz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, string(resp.Body()), `"name":"audit"`)
+
+	lc.BeginShutdown()
+
+	resp, err = client.R().Get(srv.URL + "/readyz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode(), "readiness fails during graceful shutdown")
+	assert.Contains(t, string(resp.Body()), `"name":"shutdown"`)
+
+	resp, err = client.R().Get(srv.URL + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode(), "process is still alive")
+}
+
+func TestAdminRoutes(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const token = "admin-token"
+
+	tests := []struct {
+		name         string
+		adminAddress string
+		// statuses of public router and admin handler by path, admin handler is nil without admin address.
+		wantPublic map[string]int
+		wantAdmin  map[string]int
+	}{
+		{
+			name:       "public_without_admin_listener",
+			wantPublic: map[string]int{"/debug/pprof/": http.StatusUnauthorized, "/api/admin/audit": http.StatusUnauthorized},
+		},
+		{
+			name:         "admin_listener",
+			adminAddress: "localhost:0",
+			wantPublic:   map[string]int{"/debug/pprof/": http.StatusNotFound, "/api/admin/audit": http.StatusNotFound},
+			wantAdmin:    map[string]int{"/debug/pprof/": http.StatusOK, "/api/admin/audit": http.StatusUnauthorized},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, admin, err := ConfigureRouter(context.TODO(), &config.StartupFlags{
+				BaseURL:      "http://test_host:2345",
+				Timeout:      10,
+				AdminToken:   token,
+				AdminAddress: tt.adminAddress,
+			}, lifecycle.New())
+			assert.NoError(t, err, "error setup router")
+			assert.Equal(t, tt.wantAdmin == nil, admin == nil)
+
+			for path, want := range tt.wantPublic {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				assert.Equal(t, want, w.Code, "public %s", path)
+			}
+
+			for path, want := range tt.wantAdmin {
+				w := httptest.NewRecorder()
+				admin.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+				assert.Equal(t, want, w.Code, "admin %s", path)
+			}
+
+			if admin != nil {
+				req := httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+
+				w := httptest.NewRecorder()
+				admin.ServeHTTP(w, req)
+				assert.Equal(t, http.StatusOK, w.Code, "admin API keeps token check on admin listener")
+			}
+		})
+	}
+}
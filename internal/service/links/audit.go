@@ -0,0 +1,56 @@
+package links
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/service"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// WithAuditRepository sets storage searched by SearchAuditEvents.
+func WithAuditRepository(repo repository.AuditRepository) Option {
+	return func(ls *BaseLinkService) {
+		ls.audit = repo
+	}
+}
+
+// SearchAuditEvents selects stored audit events matching filter, newest first,
+// and returns cursor of the next page. Limit defaults to 100 and is capped at 1000.
+func (ls *BaseLinkService) SearchAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, int64, error) {
+	if filter.Limit < 0 || filter.Cursor < 0 || (!filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From)) {
+		return nil, 0, service.ErrInvalidAuditFilter
+	}
+
+	if ls.audit == nil {
+		return []model.AuditEvent{}, 0, nil
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+
+	limit = min(limit, maxAuditLimit)
+	// лишнее событие показывает, что следующая страница не пуста.
+	filter.Limit = limit + 1
+
+	events, err := ls.audit.SelectAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("(ls *BaseLinkService) SearchAuditEvents: %w", err)
+	}
+
+	if len(events) <= limit {
+		return events, 0, nil
+	}
+
+	events = events[:limit]
+
+	return events, events[limit-1].ID, nil
+}
@@ -0,0 +1,96 @@
+package links
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/Pklerik/urlshortener/internal/repository/localfile"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditEvents(now time.Time) []model.AuditEvent {
+	return []model.AuditEvent{
+		{Action: model.AuditActionShorten, UserID: "u1", ShortURL: "aaa", TS: now.Add(-3 * time.Hour).Unix()},
+		{Action: model.AuditActionFollow, UserID: "u2", ShortURL: "aaa", TS: now.Add(-2 * time.Hour).Unix()},
+		{Action: model.AuditActionShorten, UserID: "u1", ShortURL: "bbb", TS: now.Add(-time.Hour).Unix()},
+		{Action: model.AuditActionFollow, UserID: "u1", ShortURL: "bbb", TS: now.Unix()},
+	}
+}
+
+func TestBaseLinkService_SearchAuditEvents(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	ctx := context.Background()
+	now := time.Now()
+
+	repos := map[string]func() repository.AuditRepository{
+		"memory": func() repository.AuditRepository { return inmemory.NewInMemoryAuditRepository() },
+		"file": func() repository.AuditRepository {
+			return localfile.NewLocalAuditRepository(t.TempDir() + "/audit.json")
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter model.AuditFilter
+		want   []int64
+		next   int64
+	}{
+		{name: "all newest first", want: []int64{4, 3, 2, 1}},
+		{name: "by user", filter: model.AuditFilter{UserID: "u1"}, want: []int64{4, 3, 1}},
+		{name: "by user and action", filter: model.AuditFilter{UserID: "u1", Action: model.AuditActionShorten}, want: []int64{3, 1}},
+		{name: "by short url", filter: model.AuditFilter{ShortURL: "aaa"}, want: []int64{2, 1}},
+		{name: "by time range", filter: model.AuditFilter{From: now.Add(-150 * time.Minute), To: now.Add(-30 * time.Minute)}, want: []int64{3, 2}},
+		{name: "first page", filter: model.AuditFilter{Limit: 2}, want: []int64{4, 3}, next: 3},
+		{name: "last page", filter: model.AuditFilter{Limit: 2, Cursor: 3}, want: []int64{2, 1}},
+	}
+
+	for repoName, newRepo := range repos {
+		repo := newRepo()
+		require.NoError(t, repo.InsertAuditEvents(ctx, auditEvents(now)))
+
+		ls := NewLinksService(inmemory.NewInMemoryLinksRepository(), "", WithAuditRepository(repo))
+
+		for _, tt := range tests {
+			t.Run(repoName+"/"+tt.name, func(t *testing.T) {
+				events, next, err := ls.SearchAuditEvents(ctx, tt.filter)
+				require.NoError(t, err)
+
+				ids := make([]int64, 0, len(events))
+				for _, ev := range events {
+					ids = append(ids, ev.ID)
+				}
+
+				assert.Equal(t, tt.want, ids)
+				assert.Equal(t, tt.next, next)
+			})
+		}
+	}
+
+	_, _, err := NewLinksService(nil, "").SearchAuditEvents(ctx, model.AuditFilter{From: now, To: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, service.ErrInvalidAuditFilter)
+}
+
+func TestAuditRepositoryFile_Reload(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	ctx := context.Background()
+	path := t.TempDir() + "/audit.json"
+
+	require.NoError(t, localfile.NewLocalAuditRepository(path).InsertAuditEvents(ctx, auditEvents(time.Now())))
+
+	repo := localfile.NewLocalAuditRepository(path)
+	require.NoError(t, repo.InsertAuditEvents(ctx, []model.AuditEvent{{Action: model.AuditActionEditURL, UserID: "u3"}}))
+
+	events, err := repo.SelectAuditEvents(ctx, model.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 5, "events are restored from file")
+	assert.Equal(t, int64(5), events[0].ID, "ids continue after restart")
+}
@@ -0,0 +1,80 @@
+package links
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/samborkent/uuidv7"
+)
+
+// EditLink changes destination of link keeping its short code.
+// Personal links can be edited by creator, workspace links by editors.
+func (ls *BaseLinkService) EditLink(ctx context.Context, userID model.UserID, shortURL, longURL string) (model.LinkData, error) {
+	if err := validateLongURL(longURL); err != nil {
+		return model.LinkData{}, fmt.Errorf("EditLink: %w", err)
+	}
+
+	ld, err := ls.repo.FindShort(ctx, shortURL)
+	if err != nil {
+		return ld, fmt.Errorf("EditLink: %w", err)
+	}
+
+	if err := ls.checkLinkAccess(ctx, ld, userID, model.RoleEditor); err != nil {
+		return ld, fmt.Errorf("EditLink: %w", err)
+	}
+
+	if ld.IsDeleted {
+		return ld, service.ErrDeletedLink
+	}
+
+	if ld.LongURL == longURL {
+		return ld, nil
+	}
+
+	ld, err = ls.repo.UpdateLink(ctx, model.LinkRevision{
+		ID:        model.UUIDv7(uuidv7.New().String()),
+		LinkID:    ld.UUID,
+		OldURL:    ld.LongURL,
+		NewURL:    longURL,
+		ChangedBy: userID,
+	})
+	if err != nil {
+		return ld, fmt.Errorf("EditLink: %w", err)
+	}
+
+	return ld, nil
+}
+
+// ProvideLinkRevisions provide destination change history of link.
+func (ls *BaseLinkService) ProvideLinkRevisions(ctx context.Context, userID model.UserID, shortURL string) ([]model.LinkRevision, error) {
+	ld, err := ls.repo.FindShort(ctx, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("ProvideLinkRevisions: %w", err)
+	}
+
+	if err := ls.checkLinkAccess(ctx, ld, userID, model.RoleViewer); err != nil {
+		return nil, fmt.Errorf("ProvideLinkRevisions: %w", err)
+	}
+
+	revs, err := ls.repo.SelectLinkRevisions(ctx, ld.UUID)
+	if err != nil {
+		return revs, fmt.Errorf("ProvideLinkRevisions: %w", err)
+	}
+
+	return revs, nil
+}
+
+// checkLinkAccess checks that user is creator of personal link or has required role in link workspace.
+func (ls *BaseLinkService) checkLinkAccess(ctx context.Context, ld model.LinkData, userID model.UserID, required model.Role) error {
+	if ld.WorkspaceID != "" {
+		return ls.checkRole(ctx, ld.WorkspaceID, userID, required)
+	}
+
+	if ld.UserID != userID {
+		return service.ErrPermissionDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package links
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/events"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/samborkent/uuidv7"
+)
+
+// WithPublisher sets publisher of link lifecycle events.
+func WithPublisher(p events.Publisher) Option {
+	return func(ls *BaseLinkService) {
+		ls.events = p
+	}
+}
+
+// WithClickSampling sets share of redirects published as link.clicked events, from 0 to 1.
+func WithClickSampling(rate float64) Option {
+	return func(ls *BaseLinkService) {
+		ls.clickSampleRate = min(max(rate, 0), 1)
+	}
+}
+
+// publish sends event of type et for every link if publisher is set.
+func (ls *BaseLinkService) publish(ctx context.Context, et model.EventType, lds ...model.LinkData) {
+	if ls.events == nil {
+		return
+	}
+
+	now := time.Now()
+
+	for _, ld := range lds {
+		ls.events.Publish(ctx, model.Event{
+			OccurredAt: now,
+			ID:         model.UUIDv7(uuidv7.New().String()),
+			Type:       et,
+			Link: model.EventLink{
+				ShortURL:    ld.ShortURL,
+				LongURL:     ld.LongURL,
+				UserID:      ld.UserID,
+				WorkspaceID: ld.WorkspaceID,
+			},
+		})
+	}
+}
+
+// publishClick publishes link.clicked event for sampled share of redirects.
+func (ls *BaseLinkService) publishClick(ctx context.Context, ld model.LinkData) {
+	if ls.clickSampleRate <= 0 || rand.Float64() >= ls.clickSampleRate { //nolint:gosec // sampling doesn't need crypto rand
+		return
+	}
+
+	ls.publish(ctx, model.EventLinkClicked, ld)
+}
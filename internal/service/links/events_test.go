@@ -0,0 +1,146 @@
+package links
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/Pklerik/urlshortener/internal/repository/mocks"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher []model.Event
+
+func (rp *recordingPublisher) Publish(_ context.Context, ev model.Event) {
+	*rp = append(*rp, ev)
+}
+
+func TestBaseLinkService_PublishEvents(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	ctx := context.Background()
+	published := new(recordingPublisher)
+	ls := NewLinksService(inmemory.NewInMemoryLinksRepository(), "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=",
+		WithPublisher(published), WithClickSampling(1),
+	)
+
+	_, err := ls.RegisterLinks(ctx, []string{"http://ya.ru", "http://yandex.ru"}, userID)
+	require.NoError(t, err)
+
+	_, err = ls.GetShort(ctx, "398f0ca4")
+	require.NoError(t, err)
+
+	require.NoError(t, ls.MarkAsDeleted(ctx, userID, model.ShortUrls{"398f0ca4"}))
+	require.NoError(t, ls.MarkAsDeleted(ctx, userID, model.ShortUrls{"398f0ca4"}))
+
+	_, err = ls.PurgeDeleted(ctx, -time.Minute)
+	require.NoError(t, err)
+
+	types := make([]model.EventType, 0, len(*published))
+	for _, ev := range *published {
+		types = append(types, ev.Type)
+	}
+
+	assert.Equal(t, []model.EventType{
+		model.EventLinkCreated, model.EventLinkCreated,
+		model.EventLinkClicked,
+		model.EventLinkDeleted,
+		model.EventLinkExpired,
+	}, types, "repeated deletion is not published")
+	assert.Equal(t, userID, (*published)[4].Link.UserID)
+}
+
+// staticResolver resolves names from map.
+type staticResolver map[string][]netip.Addr
+
+func (sr staticResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	ips, ok := sr[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return ips, nil
+}
+
+func TestBaseLinkService_CreateWebhook(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	tests := []struct {
+		wantErr error
+		name    string
+		req     model.ReqWebhook
+	}{
+		{name: "valid", req: model.ReqWebhook{URL: "https://crm.example/hook", Events: model.EventTypes{model.EventLinkCreated}}},
+		{name: "relative url", req: model.ReqWebhook{URL: "/hook", Events: model.EventTypes{model.EventLinkCreated}}, wantErr: service.ErrInvalidWebhook},
+		{name: "no events", req: model.ReqWebhook{URL: "https://crm.example/hook"}, wantErr: service.ErrInvalidWebhook},
+		{name: "unknown event", req: model.ReqWebhook{URL: "https://crm.example/hook", Events: model.EventTypes{"link.moved"}}, wantErr: service.ErrInvalidWebhook},
+		{name: "loopback", req: model.ReqWebhook{URL: "http://127.0.0.1:8080/hook", Events: model.EventTypes{model.EventLinkCreated}}, wantErr: service.ErrInvalidWebhook},
+		{name: "cloud metadata", req: model.ReqWebhook{URL: "http://169.254.169.254/latest/meta-data", Events: model.EventTypes{model.EventLinkCreated}}, wantErr: service.ErrInvalidWebhook},
+		{name: "host resolves to private", req: model.ReqWebhook{URL: "https://intranet.example/hook", Events: model.EventTypes{model.EventLinkCreated}}, wantErr: service.ErrInvalidWebhook},
+		{name: "foreign workspace", req: model.ReqWebhook{URL: "https://crm.example/hook", Events: model.EventTypes{model.EventLinkCreated}, WorkspaceID: "0199996a-fd98-780c-b5aa-1aef966fb371"}, wantErr: service.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := NewLinksService(inmemory.NewInMemoryLinksRepository(), "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=",
+				WithResolver(staticResolver{
+					"crm.example":      {netip.MustParseAddr("93.184.216.34")},
+					"intranet.example": {netip.MustParseAddr("10.0.0.5")},
+				}),
+			)
+
+			wh, err := ls.CreateWebhook(context.Background(), userID, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, wh.Secret, 2*webhookSecretSize)
+
+			whs, err := ls.ListWebhooks(context.Background(), userID)
+			require.NoError(t, err)
+			require.Len(t, whs, 1)
+			assert.Empty(t, whs[0].Secret, "secret is shown only on creation")
+		})
+	}
+}
+
+func TestBaseLinkService_PublishCreatedOnConflict(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockLinksRepository(ctrl)
+	repo.EXPECT().CreateUser(gomock.Any(), userID).Return(model.User{ID: userID}, nil)
+	repo.EXPECT().FindShort(gomock.Any(), gomock.Any()).Return(model.LinkData{}, repository.ErrNotFoundLink).AnyTimes()
+	// первая ссылка уже существует, база вставляет только вторую.
+	repo.EXPECT().SetLinks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, lds []model.LinkData) ([]model.LinkData, error) {
+			return lds[1:], repository.ErrExistingLink
+		})
+
+	published := new(recordingPublisher)
+	ls := NewLinksService(repo, "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=", WithPublisher(published))
+
+	lds, err := ls.RegisterLinks(context.Background(), []string{"http://ya.ru", "http://yandex.ru"}, userID)
+	require.ErrorIs(t, err, repository.ErrExistingLink)
+	require.Len(t, lds, 2, "response keeps all requested links")
+
+	require.Len(t, *published, 1)
+	assert.Equal(t, model.EventLinkCreated, (*published)[0].Type)
+	assert.Equal(t, "http://yandex.ru", (*published)[0].Link.LongURL)
+}
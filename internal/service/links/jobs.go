@@ -0,0 +1,24 @@
+package links
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+)
+
+// HandleDeleteJob marks links listed in job payload as deleted for job owner.
+func (ls *BaseLinkService) HandleDeleteJob(ctx context.Context, job model.Job) error {
+	var shortLinks model.ShortUrls
+	if err := json.Unmarshal([]byte(job.Payload), &shortLinks); err != nil {
+		return fmt.Errorf("HandleDeleteJob: %w", err)
+	}
+
+	if err := ls.MarkAsDeleted(ctx, job.UserID, shortLinks); err != nil {
+		return fmt.Errorf("HandleDeleteJob: %w", err)
+	}
+
+	return nil
+}
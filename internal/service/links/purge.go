@@ -0,0 +1,121 @@
+package links
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+)
+
+// RestoreLinks restores user links deleted inside restore window.
+// Workspace links are restored by RestoreWorkspaceLinks with role check.
+func (ls *BaseLinkService) RestoreLinks(ctx context.Context, userID model.UserID, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	userLinks, err := ls.repo.SelectUserLinks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("RestoreLinks: %w", err)
+	}
+
+	userLinks = slices.DeleteFunc(userLinks, func(ld model.LinkData) bool { return ld.WorkspaceID != "" })
+
+	return ls.restoreLinks(ctx, userLinks, shortLinks)
+}
+
+// RestoreWorkspaceLinks restores workspace links deleted inside restore window. Requires editor role.
+func (ls *BaseLinkService) RestoreWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	if err := ls.checkRole(ctx, wsID, userID, model.RoleEditor); err != nil {
+		return nil, fmt.Errorf("RestoreWorkspaceLinks: %w", err)
+	}
+
+	wsLinks, err := ls.repo.SelectWorkspaceLinks(ctx, wsID)
+	if err != nil {
+		return nil, fmt.Errorf("RestoreWorkspaceLinks: %w", err)
+	}
+
+	return ls.restoreLinks(ctx, wsLinks, shortLinks)
+}
+
+// restoreLinks restores deleted links from candidates listed in shortLinks.
+func (ls *BaseLinkService) restoreLinks(ctx context.Context, candidates []model.LinkData, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	ids := make([]model.UUIDv7, 0, len(shortLinks))
+
+	for _, ld := range candidates {
+		if ld.IsDeleted && slices.Contains(shortLinks, ld.ShortURL) {
+			ids = append(ids, ld.UUID)
+		}
+	}
+
+	restored, err := ls.repo.RestoreLinks(ctx, ids, time.Now().Add(-ls.restoreWindow))
+	if err != nil {
+		return nil, fmt.Errorf("restoreLinks: %w", err)
+	}
+
+	return restored, nil
+}
+
+// PurgeLinks permanently removes links regardless of owner and deletion state.
+func (ls *BaseLinkService) PurgeLinks(ctx context.Context, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	ids := make([]model.UUIDv7, 0, len(shortLinks))
+
+	for _, short := range shortLinks {
+		ld, err := ls.repo.FindShort(ctx, short)
+		if errors.Is(err, repository.ErrNotFoundLink) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("PurgeLinks: %w", err)
+		}
+
+		ids = append(ids, ld.UUID)
+	}
+
+	purged, err := ls.repo.PurgeLinks(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("PurgeLinks: %w", err)
+	}
+
+	return purged, nil
+}
+
+// PurgeDeleted permanently removes links deleted more than retention ago.
+func (ls *BaseLinkService) PurgeDeleted(ctx context.Context, retention time.Duration) ([]model.LinkData, error) {
+	purged, err := ls.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return nil, fmt.Errorf("PurgeDeleted: %w", err)
+	}
+
+	ls.publish(ctx, model.EventLinkExpired, purged...)
+
+	return purged, nil
+}
+
+// RunPurgeJob calls PurgeDeleted every interval until ctx is done.
+// onPurged receives links removed by each run.
+func (ls *BaseLinkService) RunPurgeJob(ctx context.Context, interval, retention time.Duration, onPurged func([]model.LinkData)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := ls.PurgeDeleted(ctx, retention)
+			if err != nil {
+				logger.Sugar.Errorf("Purge job failed: %v", err)
+				continue
+			}
+
+			logger.Sugar.Infof("Purge job removed <%d> links", len(purged))
+
+			if len(purged) > 0 && onPurged != nil {
+				onPurged(purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package links
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseLinkService_RestoreAndPurge(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	ctx := context.Background()
+	repo := inmemory.NewInMemoryLinksRepository()
+	ls := NewLinksService(repo, "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=", WithRestoreWindow(time.Hour))
+
+	lds, err := ls.RegisterLinks(ctx, []string{"http://ya.ru", "http://yandex.ru"}, userID)
+	require.NoError(t, err)
+
+	shorts := model.ShortUrls{lds[0].ShortURL, lds[1].ShortURL}
+	require.NoError(t, ls.MarkAsDeleted(ctx, userID, shorts))
+
+	// вторая ссылка удалена за пределами окна восстановления.
+	longAgo := time.Now().Add(-2 * time.Hour)
+	repo.Shorts[lds[1].ShortURL].DeletedAt = &longAgo
+
+	restored, err := ls.RestoreLinks(ctx, "0199996a-fd98-780c-b5aa-1aef966fb36f", shorts)
+	require.NoError(t, err)
+	assert.Empty(t, restored, "other user can't restore links")
+
+	restored, err = ls.RestoreLinks(ctx, userID, shorts)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	assert.Equal(t, lds[0].ShortURL, restored[0].ShortURL)
+	assert.False(t, restored[0].IsDeleted)
+
+	purged, err := ls.PurgeDeleted(ctx, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, lds[1].ShortURL, purged[0].ShortURL)
+
+	purged, err = ls.PurgeLinks(ctx, model.ShortUrls{lds[0].ShortURL, "absent"})
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+
+	for _, short := range shorts {
+		_, err = ls.GetShort(ctx, short)
+		assert.ErrorIs(t, err, repository.ErrNotFoundLink)
+	}
+}
@@ -5,63 +5,127 @@ import (
 	"context"
 	"runtime"
 	"slices"
+	"strconv"
 	"sync"
+	"time"
 
 	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/url"
+	"strings"
 
+	"github.com/Pklerik/urlshortener/internal/events"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/netguard"
 	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/service"
 	"github.com/samborkent/uuidv7"
 )
 
+const (
+	// defaultRestoreWindow period after deletion when owner can restore link.
+	defaultRestoreWindow = 24 * time.Hour
+	// maxCodeCandidates hash based codes tried for one long url.
+	maxCodeCandidates = 16
+)
+
 // BaseLinkService - structure for service repository realization.
 type BaseLinkService struct {
-	repo      repository.LinksRepository
-	secretKey string
+	repo            repository.LinksRepository
+	audit           repository.AuditRepository
+	events          events.Publisher
+	resolver        netguard.Resolver
+	secretKey       string
+	restoreWindow   time.Duration
+	clickSampleRate float64
+}
+
+// Option provide optional BaseLinkService settings.
+type Option func(ls *BaseLinkService)
+
+// WithRestoreWindow sets period after deletion when owner can restore link.
+func WithRestoreWindow(window time.Duration) Option {
+	return func(ls *BaseLinkService) {
+		if window > 0 {
+			ls.restoreWindow = window
+		}
+	}
 }
 
 // NewLinksService - provide instance of service.
-func NewLinksService(repo repository.LinksRepository, secretKey string) *BaseLinkService {
-	return &BaseLinkService{repo: repo, secretKey: secretKey}
+func NewLinksService(repo repository.LinksRepository, secretKey string, opts ...Option) *BaseLinkService {
+	ls := &BaseLinkService{repo: repo, secretKey: secretKey, restoreWindow: defaultRestoreWindow, resolver: net.DefaultResolver}
+	for _, opt := range opts {
+		opt(ls)
+	}
+
+	return ls
 }
 
 // RegisterLinks - register the Link with provided longURL.
 func (ls *BaseLinkService) RegisterLinks(ctx context.Context, longURLs []string, userID model.UserID) ([]model.LinkData, error) {
+	return ls.registerLinks(ctx, longURLs, userID, "")
+}
+
+// registerLinks - register links owned by user and optionally by workspace wsID.
+func (ls *BaseLinkService) registerLinks(ctx context.Context, longURLs []string, userID model.UserID, wsID model.WorkspaceID) ([]model.LinkData, error) {
 	if ctx.Err() != nil {
 		return nil, fmt.Errorf("RegisterLink context error: %w", ctx.Err())
 	}
 
+	for _, longURL := range longURLs {
+		if err := validateLongURL(longURL); err != nil {
+			return nil, fmt.Errorf("(ls *LinkService) RegisterLink: %w", err)
+		}
+	}
+
 	user, err := ls.repo.CreateUser(ctx, userID)
 	if err != nil {
 		return []model.LinkData{}, fmt.Errorf("(ls *LinkService) RegisterLink: %w", err)
 	}
 
-	logger.Sugar.Infof("Long urls to shorten: %v", longURLs)
+	logger.FromContext(ctx).Sugar().Infof("Long urls to shorten: %v", longURLs)
 	lds := make([]model.LinkData, 0, len(longURLs))
 
 	for _, longURL := range longURLs {
-		shortURL, err := ls.cutURL(ctx, longURL)
+		shortURL, err := ls.shortCode(ctx, longURL)
 		if err != nil {
 			return lds, fmt.Errorf("(ls *LinkService) RegisterLink: %w", err)
 		}
 
 		lds = append(lds, model.LinkData{
-			UUID:     model.UUIDv7(uuidv7.New().String()),
-			ShortURL: shortURL,
-			LongURL:  longURL,
-			UserID:   user.ID,
+			UUID:        model.UUIDv7(uuidv7.New().String()),
+			ShortURL:    shortURL,
+			LongURL:     longURL,
+			UserID:      user.ID,
+			WorkspaceID: wsID,
 		})
 	}
 
-	lds, err = ls.repo.SetLinks(ctx, lds)
+	inserted, err := ls.repo.SetLinks(ctx, lds)
 	if err != nil && !errors.Is(err, repository.ErrExistingLink) {
 		return lds, fmt.Errorf("(ls *LinkService) RegisterLink: %w", err)
 	}
 
+	// ответ сохраняет порядок запроса, вставленные ссылки заменяются сохранёнными значениями.
+	byID := make(map[model.UUIDv7]model.LinkData, len(inserted))
+	for _, ld := range inserted {
+		byID[ld.UUID] = ld
+	}
+
+	for i := range lds {
+		if ld, ok := byID[lds[i].UUID]; ok {
+			lds[i] = ld
+		}
+	}
+
+	// link.created отправляется только для действительно созданных ссылок, в том числе при частичном конфликте.
+	ls.publish(ctx, model.EventLinkCreated, inserted...)
+
 	if errors.Is(err, repository.ErrExistingLink) {
 		return lds, repository.ErrExistingLink
 	}
@@ -69,8 +133,34 @@ func (ls *BaseLinkService) RegisterLinks(ctx context.Context, longURLs []string,
 	return lds, nil
 }
 
-// cutURL - provide shortURl based on Long.
-func (ls *BaseLinkService) cutURL(_ context.Context, longURL string) (string, error) {
+// shortCode returns first candidate code of longURL which is free or already points to longURL.
+// Edited links keep their codes but point to other urls, such candidates are skipped,
+// so new link never aliases destination which caller didn't ask for.
+func (ls *BaseLinkService) shortCode(ctx context.Context, longURL string) (string, error) {
+	for attempt := range maxCodeCandidates {
+		code, err := ls.cutURL(ctx, longURL, attempt)
+		if err != nil {
+			return "", fmt.Errorf("(ls *BaseLinkService) shortCode: %w", err)
+		}
+
+		ld, err := ls.repo.FindShort(ctx, code)
+
+		switch {
+		case errors.Is(err, repository.ErrNotFoundLink):
+			return code, nil
+		case err != nil:
+			return "", fmt.Errorf("(ls *BaseLinkService) shortCode: %w", err)
+		case ld.LongURL == longURL:
+			return code, nil
+		}
+	}
+
+	return "", fmt.Errorf("(ls *BaseLinkService) shortCode %s: %w", longURL, service.ErrCollision)
+}
+
+// cutURL - provide shortURl based on Long. First attempt gives plain hash of longURL,
+// next attempts hash it with attempt number.
+func (ls *BaseLinkService) cutURL(_ context.Context, longURL string, attempt int) (string, error) {
 	h := sha256.New()
 
 	_, err := io.WriteString(h, longURL)
@@ -78,6 +168,12 @@ func (ls *BaseLinkService) cutURL(_ context.Context, longURL string) (string, er
 		return "", fmt.Errorf("(ls *BaseLinkService) cutURL: %w", err)
 	}
 
+	if attempt > 0 {
+		if _, err := io.WriteString(h, "#"+strconv.Itoa(attempt)); err != nil {
+			return "", fmt.Errorf("(ls *BaseLinkService) cutURL: %w", err)
+		}
+	}
+
 	shortURL := fmt.Sprintf("%x", h.Sum(nil))[:8]
 
 	return shortURL, nil
@@ -91,6 +187,10 @@ func (ls *BaseLinkService) GetShort(ctx context.Context, shortURL string) (model
 		return ld, fmt.Errorf("(ls *LinkService) GetShort: %w", err)
 	}
 
+	if !ld.IsDeleted {
+		ls.publishClick(ctx, ld)
+	}
+
 	return ld, nil
 }
 
@@ -124,6 +224,14 @@ func (ls *BaseLinkService) MarkAsDeleted(ctx context.Context, userID model.UserI
 		return fmt.Errorf("MarkAsDeleted: %w", err)
 	}
 
+	// ссылки рабочих пространств удаляются только через MarkWorkspaceLinksDeleted с проверкой роли.
+	userLinks = slices.DeleteFunc(userLinks, func(ld model.LinkData) bool { return ld.WorkspaceID != "" })
+
+	return ls.markAsDeleted(ctx, userLinks, shortLinks)
+}
+
+// markAsDeleted - mark links from candidates listed in shortLinks as is_deleted.
+func (ls *BaseLinkService) markAsDeleted(ctx context.Context, userLinks []model.LinkData, shortLinks model.ShortUrls) error {
 	// chan with input data
 	inputCh := deletionLinksGenerator(ctx, userLinks)
 
@@ -133,11 +241,15 @@ func (ls *BaseLinkService) MarkAsDeleted(ctx context.Context, userID model.UserI
 	// collect all channels to 1.
 	addResultCh := funInDeletionLinks(ctx, channels...)
 
-	err = ls.repo.BatchMarkAsDeleted(ctx, addResultCh)
+	err := ls.repo.BatchMarkAsDeleted(ctx, addResultCh)
 	if err != nil {
 		return fmt.Errorf("MarkAsDeleted: %w", err)
 	}
 
+	ls.publish(ctx, model.EventLinkDeleted, slices.DeleteFunc(slices.Clone(userLinks), func(ld model.LinkData) bool {
+		return ld.IsDeleted || !slices.Contains(shortLinks, ld.ShortURL)
+	})...)
+
 	return nil
 }
 
@@ -253,3 +365,22 @@ func (ls *BaseLinkService) GetSecret(name string) (any, bool) {
 		return "", false
 	}
 }
+
+// validateLongURL checks that longURL is absolute http(s) url.
+// It is the only check of destination, shared by creation, editing and webhooks.
+func validateLongURL(longURL string) error {
+	if strings.TrimSpace(longURL) == "" {
+		return service.ErrEmptyLongURL
+	}
+
+	u, err := url.ParseRequestURI(longURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", service.ErrInvalidLongURL, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %s", service.ErrInvalidLongURL, longURL)
+	}
+
+	return nil
+}
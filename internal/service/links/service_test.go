@@ -12,6 +12,8 @@ import (
 	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
 	"github.com/Pklerik/urlshortener/internal/repository/mocks"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBaseLinkService_RegisterLinks(t *testing.T) {
@@ -31,6 +33,9 @@ func TestBaseLinkService_RegisterLinks(t *testing.T) {
 		wantErr bool
 	}{
 		{name: "Base", fields: fields{linksRepo: inmemory.NewInMemoryLinksRepository()}, args: args{ctx: context.Background(), longURLs: []string{"http://ya.ru"}}, want: "398f0ca4", wantErr: false},
+		{name: "no_scheme", fields: fields{linksRepo: inmemory.NewInMemoryLinksRepository()}, args: args{ctx: context.Background(), longURLs: []string{"http://ya.ru", "example.com"}}, wantErr: true},
+		{name: "not_http", fields: fields{linksRepo: inmemory.NewInMemoryLinksRepository()}, args: args{ctx: context.Background(), longURLs: []string{"ftp://ya.ru"}}, wantErr: true},
+		{name: "empty", fields: fields{linksRepo: inmemory.NewInMemoryLinksRepository()}, args: args{ctx: context.Background(), longURLs: []string{" "}}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -105,3 +110,55 @@ func TestNewLinksService(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseLinkService_RegisterLinks_editedLink(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	ctx := context.Background()
+	ls := NewLinksService(inmemory.NewInMemoryLinksRepository(), "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=")
+
+	lds, err := ls.RegisterLinks(ctx, []string{"http://ya.ru"}, userID)
+	require.NoError(t, err)
+	require.Equal(t, "398f0ca4", lds[0].ShortURL)
+
+	_, err = ls.EditLink(ctx, userID, "398f0ca4", "http://yandex.ru")
+	require.NoError(t, err)
+
+	// код отредактированной ссылки больше не выдаётся для исходного адреса.
+	lds, err = ls.RegisterLinks(ctx, []string{"http://ya.ru"}, userID)
+	require.NoError(t, err)
+	assert.NotEqual(t, "398f0ca4", lds[0].ShortURL)
+
+	again, err := ls.RegisterLinks(ctx, []string{"http://ya.ru"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, lds[0].ShortURL, again[0].ShortURL, "same url gets same code")
+
+	ld, err := ls.GetShort(ctx, lds[0].ShortURL)
+	require.NoError(t, err)
+	assert.Equal(t, "http://ya.ru", ld.LongURL)
+
+	ld, err = ls.GetShort(ctx, "398f0ca4")
+	require.NoError(t, err)
+	assert.Equal(t, "http://yandex.ru", ld.LongURL)
+}
+
+func TestBaseLinkService_sameValidation(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	ctx := context.Background()
+	ls := NewLinksService(inmemory.NewInMemoryLinksRepository(), "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=")
+
+	_, err := ls.RegisterLinks(ctx, []string{"http://ya.ru"}, userID)
+	require.NoError(t, err)
+
+	for _, longURL := range []string{"https://go.dev/doc", "example.com", "ftp://ya.ru", "http://", ""} {
+		_, createErr := ls.RegisterLinks(ctx, []string{longURL}, userID)
+		_, editErr := ls.EditLink(ctx, userID, "398f0ca4", longURL)
+
+		assert.Equal(t, createErr == nil, editErr == nil, "creation and edit agree on %q", longURL)
+	}
+}
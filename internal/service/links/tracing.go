@@ -0,0 +1,87 @@
+package links
+
+import (
+	"context"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/Pklerik/urlshortener/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedLinkService records span for every LinkServicer call of wrapped service.
+type TracedLinkService struct {
+	*BaseLinkService
+}
+
+var _ service.LinkServicer = (*TracedLinkService)(nil)
+
+// NewTracedLinkService - provide instance of TracedLinkService.
+func NewTracedLinkService(ls *BaseLinkService) *TracedLinkService {
+	return &TracedLinkService{BaseLinkService: ls}
+}
+
+// RegisterLinks implement service.LinkServicer interface.
+func (ts *TracedLinkService) RegisterLinks(ctx context.Context, longURLs []string, userID model.UserID) (_ []model.LinkData, err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.RegisterLinks", trace.WithAttributes(attribute.Int("links.count", len(longURLs))))
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.RegisterLinks(ctx, longURLs, userID)
+}
+
+// GetShort implement service.LinkServicer interface.
+func (ts *TracedLinkService) GetShort(ctx context.Context, shortURL string) (_ model.LinkData, err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.GetShort", trace.WithAttributes(attribute.String("link.short_url", shortURL)))
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.GetShort(ctx, shortURL)
+}
+
+// ProvideUserLinks implement service.LinkServicer interface.
+func (ts *TracedLinkService) ProvideUserLinks(ctx context.Context, userID model.UserID) (_ []model.LinkData, err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.ProvideUserLinks")
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.ProvideUserLinks(ctx, userID)
+}
+
+// MarkAsDeleted implement service.LinkServicer interface.
+func (ts *TracedLinkService) MarkAsDeleted(ctx context.Context, userID model.UserID, shortLinks model.ShortUrls) (err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.MarkAsDeleted", trace.WithAttributes(attribute.Int("links.count", len(shortLinks))))
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.MarkAsDeleted(ctx, userID, shortLinks)
+}
+
+// EditLink implement service.LinkServicer interface.
+func (ts *TracedLinkService) EditLink(ctx context.Context, userID model.UserID, shortURL, longURL string) (_ model.LinkData, err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.EditLink", trace.WithAttributes(attribute.String("link.short_url", shortURL)))
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.EditLink(ctx, userID, shortURL, longURL)
+}
+
+// ProvideLinkRevisions implement service.LinkServicer interface.
+func (ts *TracedLinkService) ProvideLinkRevisions(ctx context.Context, userID model.UserID, shortURL string) (_ []model.LinkRevision, err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.ProvideLinkRevisions", trace.WithAttributes(attribute.String("link.short_url", shortURL)))
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.ProvideLinkRevisions(ctx, userID, shortURL)
+}
+
+// RestoreLinks implement service.LinkServicer interface.
+func (ts *TracedLinkService) RestoreLinks(ctx context.Context, userID model.UserID, shortLinks model.ShortUrls) (_ []model.LinkData, err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.RestoreLinks", trace.WithAttributes(attribute.Int("links.count", len(shortLinks))))
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.RestoreLinks(ctx, userID, shortLinks)
+}
+
+// PingDB implement service.LinkServicer interface.
+func (ts *TracedLinkService) PingDB(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "LinkService.PingDB")
+	defer tracing.End(span, &err)
+
+	return ts.BaseLinkService.PingDB(ctx)
+}
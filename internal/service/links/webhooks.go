@@ -0,0 +1,138 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/netguard"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/samborkent/uuidv7"
+)
+
+const (
+	// webhookSecretSize size of webhook signing secret in bytes.
+	webhookSecretSize = 32
+	// deliveriesLimit number of latest deliveries returned in webhook log.
+	deliveriesLimit = 100
+)
+
+// WithResolver sets resolver used to check webhook hosts.
+func WithResolver(r netguard.Resolver) Option {
+	return func(ls *BaseLinkService) {
+		ls.resolver = r
+	}
+}
+
+// CreateWebhook registers endpoint for link events. Workspace webhooks require editor role.
+// Endpoints resolving to internal addresses are rejected. Signing secret is returned only once on creation.
+func (ls *BaseLinkService) CreateWebhook(ctx context.Context, userID model.UserID, req model.ReqWebhook) (model.Webhook, error) {
+	if err := validateLongURL(req.URL); err != nil {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w: %w", service.ErrInvalidWebhook, err)
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w: %w", service.ErrInvalidWebhook, err)
+	}
+
+	if err := netguard.CheckHost(ctx, ls.resolver, u.Hostname()); err != nil {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w: %w", service.ErrInvalidWebhook, err)
+	}
+
+	if len(req.Events) == 0 {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w: no events", service.ErrInvalidWebhook)
+	}
+
+	for _, et := range req.Events {
+		if !et.Valid() {
+			return model.Webhook{}, fmt.Errorf("CreateWebhook: %w: unknown event %q", service.ErrInvalidWebhook, et)
+		}
+	}
+
+	if req.WorkspaceID != "" {
+		if err := ls.checkRole(ctx, req.WorkspaceID, userID, model.RoleEditor); err != nil {
+			return model.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+		}
+	}
+
+	if _, err := ls.repo.CreateUser(ctx, userID); err != nil {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	secret, err := newToken(webhookSecretSize)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	wh, err := ls.repo.CreateWebhook(ctx, model.Webhook{
+		ID:          model.WebhookID(uuidv7.New().String()),
+		UserID:      userID,
+		WorkspaceID: req.WorkspaceID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+	})
+	if err != nil {
+		return wh, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	return wh, nil
+}
+
+// ListWebhooks provide user webhooks without secrets.
+func (ls *BaseLinkService) ListWebhooks(ctx context.Context, userID model.UserID) ([]model.Webhook, error) {
+	whs, err := ls.repo.SelectUserWebhooks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhooks: %w", err)
+	}
+
+	for i := range whs {
+		whs[i].Secret = ""
+	}
+
+	return whs, nil
+}
+
+// DeleteWebhook removes user webhook with its delivery log.
+func (ls *BaseLinkService) DeleteWebhook(ctx context.Context, userID model.UserID, id model.WebhookID) error {
+	if _, err := ls.userWebhook(ctx, userID, id); err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	if err := ls.repo.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries provide latest deliveries of user webhook.
+func (ls *BaseLinkService) ListDeliveries(ctx context.Context, userID model.UserID, id model.WebhookID) ([]model.WebhookDelivery, error) {
+	if _, err := ls.userWebhook(ctx, userID, id); err != nil {
+		return nil, fmt.Errorf("ListDeliveries: %w", err)
+	}
+
+	deliveries, err := ls.repo.SelectDeliveries(ctx, id, deliveriesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("ListDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// userWebhook selects webhook registered by user. Webhooks of other users are reported as absent.
+func (ls *BaseLinkService) userWebhook(ctx context.Context, userID model.UserID, id model.WebhookID) (model.Webhook, error) {
+	wh, err := ls.repo.SelectWebhook(ctx, id)
+	if err != nil {
+		return wh, fmt.Errorf("userWebhook: %w", err)
+	}
+
+	if wh.UserID != userID {
+		return model.Webhook{}, repository.ErrNotFoundWebhook
+	}
+
+	return wh, nil
+}
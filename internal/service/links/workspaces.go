@@ -0,0 +1,168 @@
+package links
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/samborkent/uuidv7"
+)
+
+const (
+	// invitationTTL period while invitation can be accepted.
+	invitationTTL = 7 * 24 * time.Hour
+	// invitationTokenSize size of invitation token in bytes.
+	invitationTokenSize = 24
+)
+
+// CreateWorkspace creates workspace owned by userID.
+func (ls *BaseLinkService) CreateWorkspace(ctx context.Context, name string, userID model.UserID) (model.Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return model.Workspace{}, service.ErrEmptyWorkspaceName
+	}
+
+	if _, err := ls.repo.CreateUser(ctx, userID); err != nil {
+		return model.Workspace{}, fmt.Errorf("CreateWorkspace: %w", err)
+	}
+
+	ws, err := ls.repo.CreateWorkspace(ctx, model.Workspace{
+		ID:      model.WorkspaceID(uuidv7.New().String()),
+		Name:    name,
+		OwnerID: userID,
+	})
+	if err != nil {
+		return ws, fmt.Errorf("CreateWorkspace: %w", err)
+	}
+
+	return ws, nil
+}
+
+// ListWorkspaces provide all user memberships.
+func (ls *BaseLinkService) ListWorkspaces(ctx context.Context, userID model.UserID) ([]model.WorkspaceMember, error) {
+	members, err := ls.repo.SelectUserWorkspaces(ctx, userID)
+	if err != nil {
+		return members, fmt.Errorf("ListWorkspaces: %w", err)
+	}
+
+	return members, nil
+}
+
+// InviteToWorkspace creates invitation with role. Only owners can invite.
+func (ls *BaseLinkService) InviteToWorkspace(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, role model.Role) (model.WorkspaceInvitation, error) {
+	if !role.Valid() {
+		return model.WorkspaceInvitation{}, service.ErrInvalidRole
+	}
+
+	if err := ls.checkRole(ctx, wsID, userID, model.RoleOwner); err != nil {
+		return model.WorkspaceInvitation{}, fmt.Errorf("InviteToWorkspace: %w", err)
+	}
+
+	token, err := newToken(invitationTokenSize)
+	if err != nil {
+		return model.WorkspaceInvitation{}, fmt.Errorf("InviteToWorkspace: %w", err)
+	}
+
+	inv, err := ls.repo.CreateInvitation(ctx, model.WorkspaceInvitation{
+		ExpiresAt:   time.Now().Add(invitationTTL),
+		Token:       token,
+		WorkspaceID: wsID,
+		Role:        role,
+		InvitedBy:   userID,
+	})
+	if err != nil {
+		return inv, fmt.Errorf("InviteToWorkspace: %w", err)
+	}
+
+	return inv, nil
+}
+
+// AcceptInvitation adds user to workspace by invitation token.
+func (ls *BaseLinkService) AcceptInvitation(ctx context.Context, token string, userID model.UserID) (model.WorkspaceMember, error) {
+	if _, err := ls.repo.CreateUser(ctx, userID); err != nil {
+		return model.WorkspaceMember{}, fmt.Errorf("AcceptInvitation: %w", err)
+	}
+
+	member, err := ls.repo.AcceptInvitation(ctx, token, userID, time.Now())
+	if err != nil {
+		return member, fmt.Errorf("AcceptInvitation: %w", err)
+	}
+
+	return member, nil
+}
+
+// RegisterWorkspaceLinks registers links owned by workspace. Requires editor role.
+func (ls *BaseLinkService) RegisterWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, longURLs []string, userID model.UserID) ([]model.LinkData, error) {
+	if err := ls.checkRole(ctx, wsID, userID, model.RoleEditor); err != nil {
+		return nil, fmt.Errorf("RegisterWorkspaceLinks: %w", err)
+	}
+
+	return ls.registerLinks(ctx, longURLs, userID, wsID)
+}
+
+// ProvideWorkspaceLinks provide all workspace links. Requires viewer role.
+func (ls *BaseLinkService) ProvideWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, userID model.UserID) ([]model.LinkData, error) {
+	if err := ls.checkRole(ctx, wsID, userID, model.RoleViewer); err != nil {
+		return nil, fmt.Errorf("ProvideWorkspaceLinks: %w", err)
+	}
+
+	lds, err := ls.repo.SelectWorkspaceLinks(ctx, wsID)
+	if err != nil {
+		return lds, fmt.Errorf("ProvideWorkspaceLinks: %w", err)
+	}
+
+	if len(lds) == 0 {
+		return lds, repository.ErrNotFoundLink
+	}
+
+	return lds, nil
+}
+
+// MarkWorkspaceLinksDeleted marks workspace links as is_deleted. Requires editor role.
+func (ls *BaseLinkService) MarkWorkspaceLinksDeleted(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, shortLinks model.ShortUrls) error {
+	if err := ls.checkRole(ctx, wsID, userID, model.RoleEditor); err != nil {
+		return fmt.Errorf("MarkWorkspaceLinksDeleted: %w", err)
+	}
+
+	wsLinks, err := ls.repo.SelectWorkspaceLinks(ctx, wsID)
+	if err != nil {
+		return fmt.Errorf("MarkWorkspaceLinksDeleted: %w", err)
+	}
+
+	return ls.markAsDeleted(ctx, wsLinks, shortLinks)
+}
+
+// checkRole returns service.ErrPermissionDenied if user role in workspace is lower than required.
+func (ls *BaseLinkService) checkRole(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, required model.Role) error {
+	member, err := ls.repo.SelectWorkspaceMember(ctx, wsID, userID)
+	if errors.Is(err, repository.ErrNotFoundMember) {
+		return service.ErrPermissionDenied
+	}
+
+	if err != nil {
+		return fmt.Errorf("checkRole: %w", err)
+	}
+
+	if !member.Role.Allows(required) {
+		return service.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// newToken returns hex encoded random token of size bytes.
+func newToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("newToken: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
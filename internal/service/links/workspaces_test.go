@@ -0,0 +1,87 @@
+package links
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/Pklerik/urlshortener/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseLinkService_WorkspacePermissions(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const (
+		owner    = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+		editor   = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36f")
+		viewer   = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb370")
+		stranger = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb371")
+	)
+
+	ctx := context.Background()
+	ls := NewLinksService(inmemory.NewInMemoryLinksRepository(), "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=")
+
+	ws, err := ls.CreateWorkspace(ctx, "campaigns", owner)
+	require.NoError(t, err)
+
+	for userID, role := range map[model.UserID]model.Role{editor: model.RoleEditor, viewer: model.RoleViewer} {
+		inv, err := ls.InviteToWorkspace(ctx, ws.ID, owner, role)
+		require.NoError(t, err)
+
+		member, err := ls.AcceptInvitation(ctx, inv.Token, userID)
+		require.NoError(t, err)
+		assert.Equal(t, role, member.Role)
+
+		_, err = ls.AcceptInvitation(ctx, inv.Token, stranger)
+		assert.ErrorIs(t, err, repository.ErrNotFoundInvitation, "invitation is one time")
+	}
+
+	_, err = ls.InviteToWorkspace(ctx, ws.ID, editor, model.RoleViewer)
+	assert.ErrorIs(t, err, service.ErrPermissionDenied, "only owner invites")
+
+	_, err = ls.RegisterWorkspaceLinks(ctx, ws.ID, []string{"http://ya.ru"}, viewer)
+	assert.ErrorIs(t, err, service.ErrPermissionDenied, "viewer can't create links")
+
+	_, err = ls.RegisterWorkspaceLinks(ctx, ws.ID, []string{"http://ya.ru"}, editor)
+	require.NoError(t, err)
+
+	lds, err := ls.ProvideWorkspaceLinks(ctx, ws.ID, viewer)
+	require.NoError(t, err)
+	require.Len(t, lds, 1)
+	assert.Equal(t, ws.ID, lds[0].WorkspaceID)
+
+	_, err = ls.ProvideWorkspaceLinks(ctx, ws.ID, stranger)
+	assert.ErrorIs(t, err, service.ErrPermissionDenied, "stranger can't list links")
+
+	err = ls.MarkWorkspaceLinksDeleted(ctx, ws.ID, viewer, model.ShortUrls{lds[0].ShortURL})
+	assert.ErrorIs(t, err, service.ErrPermissionDenied, "viewer can't delete links")
+
+	require.NoError(t, ls.MarkAsDeleted(ctx, editor, model.ShortUrls{lds[0].ShortURL}))
+
+	lds, err = ls.ProvideWorkspaceLinks(ctx, ws.ID, owner)
+	require.NoError(t, err)
+	assert.False(t, lds[0].IsDeleted, "personal deletion skips workspace links")
+
+	require.NoError(t, ls.MarkWorkspaceLinksDeleted(ctx, ws.ID, owner, model.ShortUrls{lds[0].ShortURL}))
+
+	lds, err = ls.ProvideWorkspaceLinks(ctx, ws.ID, viewer)
+	require.NoError(t, err)
+	assert.True(t, lds[0].IsDeleted)
+
+	_, err = ls.RestoreWorkspaceLinks(ctx, ws.ID, viewer, model.ShortUrls{lds[0].ShortURL})
+	assert.ErrorIs(t, err, service.ErrPermissionDenied, "viewer can't restore links")
+
+	restored, err := ls.RestoreLinks(ctx, editor, model.ShortUrls{lds[0].ShortURL})
+	require.NoError(t, err)
+	assert.Empty(t, restored, "personal restore skips workspace links")
+
+	restored, err = ls.RestoreWorkspaceLinks(ctx, ws.ID, editor, model.ShortUrls{lds[0].ShortURL})
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	assert.False(t, restored[0].IsDeleted)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/Pklerik/urlshortener/internal/model"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,21 @@ func (m *MockLinkServicer) EXPECT() *MockLinkServicerMockRecorder {
 	return m.recorder
 }
 
+// EditLink mocks base method.
+func (m *MockLinkServicer) EditLink(ctx context.Context, userID model.UserID, shortURL, longURL string) (model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditLink", ctx, userID, shortURL, longURL)
+	ret0, _ := ret[0].(model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditLink indicates an expected call of EditLink.
+func (mr *MockLinkServicerMockRecorder) EditLink(ctx, userID, shortURL, longURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditLink", reflect.TypeOf((*MockLinkServicer)(nil).EditLink), ctx, userID, shortURL, longURL)
+}
+
 // GetSecret mocks base method.
 func (m *MockLinkServicer) GetSecret(name string) (any, bool) {
 	m.ctrl.T.Helper()
@@ -93,6 +109,21 @@ func (mr *MockLinkServicerMockRecorder) PingDB(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingDB", reflect.TypeOf((*MockLinkServicer)(nil).PingDB), ctx)
 }
 
+// ProvideLinkRevisions mocks base method.
+func (m *MockLinkServicer) ProvideLinkRevisions(ctx context.Context, userID model.UserID, shortURL string) ([]model.LinkRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProvideLinkRevisions", ctx, userID, shortURL)
+	ret0, _ := ret[0].([]model.LinkRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProvideLinkRevisions indicates an expected call of ProvideLinkRevisions.
+func (mr *MockLinkServicerMockRecorder) ProvideLinkRevisions(ctx, userID, shortURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvideLinkRevisions", reflect.TypeOf((*MockLinkServicer)(nil).ProvideLinkRevisions), ctx, userID, shortURL)
+}
+
 // ProvideUserLinks mocks base method.
 func (m *MockLinkServicer) ProvideUserLinks(ctx context.Context, userID model.UserID) ([]model.LinkData, error) {
 	m.ctrl.T.Helper()
@@ -122,3 +153,311 @@ func (mr *MockLinkServicerMockRecorder) RegisterLinks(ctx, longURLs, userID inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterLinks", reflect.TypeOf((*MockLinkServicer)(nil).RegisterLinks), ctx, longURLs, userID)
 }
+
+// RestoreLinks mocks base method.
+func (m *MockLinkServicer) RestoreLinks(ctx context.Context, userID model.UserID, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLinks", ctx, userID, shortLinks)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreLinks indicates an expected call of RestoreLinks.
+func (mr *MockLinkServicerMockRecorder) RestoreLinks(ctx, userID, shortLinks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLinks", reflect.TypeOf((*MockLinkServicer)(nil).RestoreLinks), ctx, userID, shortLinks)
+}
+
+// MockWorkspaceServicer is a mock of WorkspaceServicer interface.
+type MockWorkspaceServicer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkspaceServicerMockRecorder
+}
+
+// MockWorkspaceServicerMockRecorder is the mock recorder for MockWorkspaceServicer.
+type MockWorkspaceServicerMockRecorder struct {
+	mock *MockWorkspaceServicer
+}
+
+// NewMockWorkspaceServicer creates a new mock instance.
+func NewMockWorkspaceServicer(ctrl *gomock.Controller) *MockWorkspaceServicer {
+	mock := &MockWorkspaceServicer{ctrl: ctrl}
+	mock.recorder = &MockWorkspaceServicerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkspaceServicer) EXPECT() *MockWorkspaceServicerMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockWorkspaceServicer) AcceptInvitation(ctx context.Context, token string, userID model.UserID) (model.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, token, userID)
+	ret0, _ := ret[0].(model.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockWorkspaceServicerMockRecorder) AcceptInvitation(ctx, token, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockWorkspaceServicer)(nil).AcceptInvitation), ctx, token, userID)
+}
+
+// CreateWorkspace mocks base method.
+func (m *MockWorkspaceServicer) CreateWorkspace(ctx context.Context, name string, userID model.UserID) (model.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", ctx, name, userID)
+	ret0, _ := ret[0].(model.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockWorkspaceServicerMockRecorder) CreateWorkspace(ctx, name, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockWorkspaceServicer)(nil).CreateWorkspace), ctx, name, userID)
+}
+
+// InviteToWorkspace mocks base method.
+func (m *MockWorkspaceServicer) InviteToWorkspace(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, role model.Role) (model.WorkspaceInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteToWorkspace", ctx, wsID, userID, role)
+	ret0, _ := ret[0].(model.WorkspaceInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InviteToWorkspace indicates an expected call of InviteToWorkspace.
+func (mr *MockWorkspaceServicerMockRecorder) InviteToWorkspace(ctx, wsID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteToWorkspace", reflect.TypeOf((*MockWorkspaceServicer)(nil).InviteToWorkspace), ctx, wsID, userID, role)
+}
+
+// ListWorkspaces mocks base method.
+func (m *MockWorkspaceServicer) ListWorkspaces(ctx context.Context, userID model.UserID) ([]model.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaces", ctx, userID)
+	ret0, _ := ret[0].([]model.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaces indicates an expected call of ListWorkspaces.
+func (mr *MockWorkspaceServicerMockRecorder) ListWorkspaces(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaces", reflect.TypeOf((*MockWorkspaceServicer)(nil).ListWorkspaces), ctx, userID)
+}
+
+// MarkWorkspaceLinksDeleted mocks base method.
+func (m *MockWorkspaceServicer) MarkWorkspaceLinksDeleted(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, shortLinks model.ShortUrls) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWorkspaceLinksDeleted", ctx, wsID, userID, shortLinks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWorkspaceLinksDeleted indicates an expected call of MarkWorkspaceLinksDeleted.
+func (mr *MockWorkspaceServicerMockRecorder) MarkWorkspaceLinksDeleted(ctx, wsID, userID, shortLinks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWorkspaceLinksDeleted", reflect.TypeOf((*MockWorkspaceServicer)(nil).MarkWorkspaceLinksDeleted), ctx, wsID, userID, shortLinks)
+}
+
+// ProvideWorkspaceLinks mocks base method.
+func (m *MockWorkspaceServicer) ProvideWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, userID model.UserID) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProvideWorkspaceLinks", ctx, wsID, userID)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProvideWorkspaceLinks indicates an expected call of ProvideWorkspaceLinks.
+func (mr *MockWorkspaceServicerMockRecorder) ProvideWorkspaceLinks(ctx, wsID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvideWorkspaceLinks", reflect.TypeOf((*MockWorkspaceServicer)(nil).ProvideWorkspaceLinks), ctx, wsID, userID)
+}
+
+// RegisterWorkspaceLinks mocks base method.
+func (m *MockWorkspaceServicer) RegisterWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, longURLs []string, userID model.UserID) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWorkspaceLinks", ctx, wsID, longURLs, userID)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterWorkspaceLinks indicates an expected call of RegisterWorkspaceLinks.
+func (mr *MockWorkspaceServicerMockRecorder) RegisterWorkspaceLinks(ctx, wsID, longURLs, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWorkspaceLinks", reflect.TypeOf((*MockWorkspaceServicer)(nil).RegisterWorkspaceLinks), ctx, wsID, longURLs, userID)
+}
+
+// RestoreWorkspaceLinks mocks base method.
+func (m *MockWorkspaceServicer) RestoreWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreWorkspaceLinks", ctx, wsID, userID, shortLinks)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreWorkspaceLinks indicates an expected call of RestoreWorkspaceLinks.
+func (mr *MockWorkspaceServicerMockRecorder) RestoreWorkspaceLinks(ctx, wsID, userID, shortLinks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreWorkspaceLinks", reflect.TypeOf((*MockWorkspaceServicer)(nil).RestoreWorkspaceLinks), ctx, wsID, userID, shortLinks)
+}
+
+// MockWebhookServicer is a mock of WebhookServicer interface.
+type MockWebhookServicer struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServicerMockRecorder
+}
+
+// MockWebhookServicerMockRecorder is the mock recorder for MockWebhookServicer.
+type MockWebhookServicerMockRecorder struct {
+	mock *MockWebhookServicer
+}
+
+// NewMockWebhookServicer creates a new mock instance.
+func NewMockWebhookServicer(ctrl *gomock.Controller) *MockWebhookServicer {
+	mock := &MockWebhookServicer{ctrl: ctrl}
+	mock.recorder = &MockWebhookServicerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookServicer) EXPECT() *MockWebhookServicerMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookServicer) CreateWebhook(ctx context.Context, userID model.UserID, req model.ReqWebhook) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, userID, req)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookServicerMockRecorder) CreateWebhook(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookServicer)(nil).CreateWebhook), ctx, userID, req)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookServicer) DeleteWebhook(ctx context.Context, userID model.UserID, id model.WebhookID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookServicerMockRecorder) DeleteWebhook(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookServicer)(nil).DeleteWebhook), ctx, userID, id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookServicer) ListDeliveries(ctx context.Context, userID model.UserID, id model.WebhookID) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, userID, id)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookServicerMockRecorder) ListDeliveries(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookServicer)(nil).ListDeliveries), ctx, userID, id)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookServicer) ListWebhooks(ctx context.Context, userID model.UserID) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookServicerMockRecorder) ListWebhooks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookServicer)(nil).ListWebhooks), ctx, userID)
+}
+
+// MockAdminServicer is a mock of AdminServicer interface.
+type MockAdminServicer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminServicerMockRecorder
+}
+
+// MockAdminServicerMockRecorder is the mock recorder for MockAdminServicer.
+type MockAdminServicerMockRecorder struct {
+	mock *MockAdminServicer
+}
+
+// NewMockAdminServicer creates a new mock instance.
+func NewMockAdminServicer(ctrl *gomock.Controller) *MockAdminServicer {
+	mock := &MockAdminServicer{ctrl: ctrl}
+	mock.recorder = &MockAdminServicerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminServicer) EXPECT() *MockAdminServicerMockRecorder {
+	return m.recorder
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockAdminServicer) PurgeDeleted(ctx context.Context, retention time.Duration) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, retention)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockAdminServicerMockRecorder) PurgeDeleted(ctx, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockAdminServicer)(nil).PurgeDeleted), ctx, retention)
+}
+
+// PurgeLinks mocks base method.
+func (m *MockAdminServicer) PurgeLinks(ctx context.Context, shortLinks model.ShortUrls) ([]model.LinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeLinks", ctx, shortLinks)
+	ret0, _ := ret[0].([]model.LinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeLinks indicates an expected call of PurgeLinks.
+func (mr *MockAdminServicerMockRecorder) PurgeLinks(ctx, shortLinks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeLinks", reflect.TypeOf((*MockAdminServicer)(nil).PurgeLinks), ctx, shortLinks)
+}
+
+// SearchAuditEvents mocks base method.
+func (m *MockAdminServicer) SearchAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAuditEvents", ctx, filter)
+	ret0, _ := ret[0].([]model.AuditEvent)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchAuditEvents indicates an expected call of SearchAuditEvents.
+func (mr *MockAdminServicerMockRecorder) SearchAuditEvents(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAuditEvents", reflect.TypeOf((*MockAdminServicer)(nil).SearchAuditEvents), ctx, filter)
+}
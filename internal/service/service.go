@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"errors"
 
@@ -12,8 +13,22 @@ import (
 var (
 	// ErrEmptyLongURL - error for empty short url.
 	ErrEmptyLongURL = errors.New("ShortURL is empty")
+	// ErrInvalidLongURL - long url is not absolute http(s) url.
+	ErrInvalidLongURL = errors.New("invalid long url")
+	// ErrDeletedLink - link is deleted and can't be changed.
+	ErrDeletedLink = errors.New("link is deleted")
 	// ErrCollision - sets error if shortURL existed for different long.
 	ErrCollision = errors.New("collision for url in db")
+	// ErrPermissionDenied - user role in workspace doesn't allow action.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrInvalidRole - unknown workspace role.
+	ErrInvalidRole = errors.New("invalid workspace role")
+	// ErrEmptyWorkspaceName - workspace name is empty.
+	ErrEmptyWorkspaceName = errors.New("workspace name is empty")
+	// ErrInvalidWebhook - webhook url or events are invalid.
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	// ErrInvalidAuditFilter - audit search conditions are invalid.
+	ErrInvalidAuditFilter = errors.New("invalid audit filter")
 )
 
 // LinkServicer provide service contract for link handling.
@@ -22,6 +37,37 @@ type LinkServicer interface {
 	GetShort(ctx context.Context, shortURL string) (model.LinkData, error)
 	ProvideUserLinks(ctx context.Context, userID model.UserID) ([]model.LinkData, error)
 	MarkAsDeleted(ctx context.Context, userID model.UserID, shortLinks model.ShortUrls) error
+	EditLink(ctx context.Context, userID model.UserID, shortURL, longURL string) (model.LinkData, error)
+	ProvideLinkRevisions(ctx context.Context, userID model.UserID, shortURL string) ([]model.LinkRevision, error)
+	RestoreLinks(ctx context.Context, userID model.UserID, shortLinks model.ShortUrls) ([]model.LinkData, error)
 	PingDB(ctx context.Context) error
 	GetSecret(name string) (any, bool)
 }
+
+// WorkspaceServicer provide service contract for workspaces and shared links.
+type WorkspaceServicer interface {
+	CreateWorkspace(ctx context.Context, name string, userID model.UserID) (model.Workspace, error)
+	ListWorkspaces(ctx context.Context, userID model.UserID) ([]model.WorkspaceMember, error)
+	InviteToWorkspace(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, role model.Role) (model.WorkspaceInvitation, error)
+	AcceptInvitation(ctx context.Context, token string, userID model.UserID) (model.WorkspaceMember, error)
+	RegisterWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, longURLs []string, userID model.UserID) ([]model.LinkData, error)
+	ProvideWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, userID model.UserID) ([]model.LinkData, error)
+	MarkWorkspaceLinksDeleted(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, shortLinks model.ShortUrls) error
+	RestoreWorkspaceLinks(ctx context.Context, wsID model.WorkspaceID, userID model.UserID, shortLinks model.ShortUrls) ([]model.LinkData, error)
+}
+
+// WebhookServicer provide service contract for webhooks management.
+type WebhookServicer interface {
+	CreateWebhook(ctx context.Context, userID model.UserID, req model.ReqWebhook) (model.Webhook, error)
+	ListWebhooks(ctx context.Context, userID model.UserID) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID model.UserID, id model.WebhookID) error
+	ListDeliveries(ctx context.Context, userID model.UserID, id model.WebhookID) ([]model.WebhookDelivery, error)
+}
+
+// AdminServicer provide service contract for administrative operations.
+type AdminServicer interface {
+	PurgeLinks(ctx context.Context, shortLinks model.ShortUrls) ([]model.LinkData, error)
+	PurgeDeleted(ctx context.Context, retention time.Duration) ([]model.LinkData, error)
+	// SearchAuditEvents returns page of audit events and cursor of the next page, 0 if page is the last.
+	SearchAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, int64, error)
+}
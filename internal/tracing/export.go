@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter kinds.
+const (
+	ExporterNone   = ""
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// DefaultOTLPEndpoint OTLP/HTTP traces endpoint of local collector.
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+
+// retryInterval first pause before retry of failed OTLP request.
+const retryInterval = 500 * time.Millisecond
+
+// ErrUnknownExporter exporter kind is not supported.
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// NewExporter creates exporter of kind. Endpoint is OTLP/HTTP url for otlp and path for file exporter.
+// Every OTLP request and all retries of one batch are limited by exportTimeout.
+func NewExporter(ctx context.Context, kind, endpoint string) (sdktrace.SpanExporter, error) {
+	switch kind {
+	case ExporterOTLP:
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+
+		exp, err := otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(endpoint),
+			otlptracehttp.WithTimeout(exportTimeout),
+			otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+				Enabled:         true,
+				InitialInterval: retryInterval,
+				MaxInterval:     exportTimeout,
+				MaxElapsedTime:  exportTimeout,
+			}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("NewExporter: %w", err)
+		}
+
+		return exp, nil
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("NewExporter: %w", err)
+		}
+
+		return exp, nil
+	case ExporterFile:
+		f, err := os.OpenFile(filepath.Clean(endpoint), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("NewExporter: %w", err)
+		}
+
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("NewExporter: %w", err)
+		}
+
+		return &fileExporter{Exporter: exp, f: f}, nil
+	default:
+		return nil, fmt.Errorf("NewExporter: %w: %q", ErrUnknownExporter, kind)
+	}
+}
+
+// fileExporter writes spans as JSON to file and closes it on Shutdown.
+type fileExporter struct {
+	*stdouttrace.Exporter
+	f *os.File
+}
+
+// Shutdown stops exporter and closes file.
+func (fe *fileExporter) Shutdown(ctx context.Context) error {
+	if err := fe.Exporter.Shutdown(ctx); err != nil {
+		return fmt.Errorf("(fe *fileExporter) Shutdown: %w", err)
+	}
+
+	if err := fe.f.Close(); err != nil {
+		return fmt.Errorf("(fe *fileExporter) Shutdown: %w", err)
+	}
+
+	return nil
+}
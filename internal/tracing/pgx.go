@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PgxTracer records span for every SQL statement executed by pgx connection.
+// Statements outside of traced operation, e.g. background polling, are skipped.
+type PgxTracer struct{}
+
+var _ pgx.QueryTracer = PgxTracer{}
+
+type querySpanKey struct{}
+
+// TraceQueryStart implement pgx.QueryTracer interface.
+func (PgxTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	ctx, span := Start(ctx, "SQL "+sqlOperation(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBQueryText(data.SQL)),
+	)
+
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+// TraceQueryEnd implement pgx.QueryTracer interface.
+func (PgxTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	// без span запроса в ctx лежит span вызывающего, его не завершаем.
+	span, ok := ctx.Value(querySpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	SetError(span, data.Err)
+	span.End()
+}
+
+// sqlOperation returns upper cased first word of statement, e.g. SELECT.
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderTraceparent W3C trace context header.
+const HeaderTraceparent = "traceparent"
+
+var propagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+// Inject sets traceparent header of current span in ctx. Does nothing without span.
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns ctx with remote parent from traceparent header. Invalid header is ignored.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Traceparent returns traceparent value of current span in ctx, empty without span.
+// Used to continue trace in background jobs.
+func Traceparent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	return carrier.Get(HeaderTraceparent)
+}
+
+// ContextWithTraceparent returns ctx with remote parent from traceparent value. Invalid value is ignored.
+func ContextWithTraceparent(ctx context.Context, value string) context.Context {
+	return propagator.Extract(ctx, propagation.MapCarrier{HeaderTraceparent: value})
+}
+
+// Middleware starts server span for every request, continuing trace from traceparent header.
+// Span is named by chi route pattern after request is served.
+func Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := Start(Extract(r.Context(), r.Header), "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(r.Method), semconv.URLPath(r.URL.Path)),
+		)
+		defer span.End()
+
+		if !span.IsRecording() {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+		if status >= http.StatusInternalServerError {
+			SetError(span, fmt.Errorf("%w: %d", errServerStatus, status))
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+var errServerStatus = errors.New("server error status")
@@ -0,0 +1,79 @@
+// Package tracing provide request tracing with OpenTelemetry SDK: W3C trace context propagation and OTLP export.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scopeName instrumentation scope of spans started by Start.
+const scopeName = "github.com/Pklerik/urlshortener/internal/tracing"
+
+// serviceName resource service.name of exported spans.
+const serviceName = "urlshortener"
+
+const (
+	defaultQueueSize  = 2048
+	defaultBatchSize  = 512
+	defaultFlushEvery = time.Second
+)
+
+// exportTimeout limits one export of batch, OTLP request with retries included.
+var exportTimeout = 10 * time.Second
+
+// NewProvider creates tracer provider exporting share sampleRatio of root traces with exporter in batches.
+// Child spans follow decision of their parent, remote one too.
+func NewProvider(exporter sdktrace.SpanExporter, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithMaxQueueSize(defaultQueueSize),
+			sdktrace.WithMaxExportBatchSize(defaultBatchSize),
+			sdktrace.WithBatchTimeout(defaultFlushEvery),
+			sdktrace.WithExportTimeout(exportTimeout),
+		),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+}
+
+// SetGlobal sets provider used by Start.
+func SetGlobal(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+}
+
+// SetErrorHandler sets receiver of export errors, e.g. application logger.
+func SetErrorHandler(fn func(error)) {
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(fn))
+}
+
+// Start starts span as child of span or remote parent in ctx with global provider.
+// Without provider span is not recording, but remote parent is still propagated.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(scopeName).Start(ctx, name, opts...)
+}
+
+// End finishes span recording *errp as error, used as `defer tracing.End(span, &err)`.
+func End(span trace.Span, errp *error) {
+	if errp != nil {
+		SetError(span, *errp)
+	}
+
+	span.End()
+}
+
+// SetError marks span as failed with err. Nil err is ignored.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,183 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func byName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	res := make(map[string]tracetest.SpanStub, len(spans))
+	for _, s := range spans {
+		res[s.Name] = s
+	}
+
+	return res
+}
+
+func TestMiddleware_ContinuesRemoteTrace(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := NewProvider(exp, 0)
+	SetGlobal(tp)
+	t.Cleanup(func() { SetGlobal(noop.NewTracerProvider()) })
+
+	var outgoing http.Header
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := Start(r.Context(), "service.Get")
+		defer span.End()
+
+		outgoing = http.Header{}
+		Inject(ctx, outgoing)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	req := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	req.Header.Set(HeaderTraceparent, parent)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	spans := byName(exp.GetSpans())
+	require.Len(t, spans, 2, "remote sampled flag wins over local ratio")
+
+	server, ok := spans["GET /{id}"]
+	require.True(t, ok, "server span is named by route pattern")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, codes.Error, server.Status.Code)
+
+	child := spans["service.Get"]
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+child.SpanContext.SpanID().String()+"-01", outgoing.Get(HeaderTraceparent))
+}
+
+func TestNewProvider_Sampling(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio float64
+		want  int
+	}{
+		{name: "all", ratio: 1, want: 100},
+		{name: "none", ratio: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := tracetest.NewInMemoryExporter()
+			tp := NewProvider(exp, tt.ratio)
+
+			for range 100 {
+				_, span := tp.Tracer("test").Start(context.Background(), "root")
+				span.End()
+			}
+
+			require.NoError(t, tp.ForceFlush(context.Background()))
+			assert.Len(t, exp.GetSpans(), tt.want)
+		})
+	}
+}
+
+func TestStart_WithoutProvider(t *testing.T) {
+	SetGlobal(noop.NewTracerProvider())
+
+	ctx, span := Start(context.Background(), "noop")
+	assert.False(t, span.IsRecording())
+	End(span, new(error))
+
+	assert.Empty(t, Traceparent(ctx))
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	ctx, _ = Start(ContextWithTraceparent(context.Background(), parent), "noop")
+	assert.Equal(t, parent, Traceparent(ctx), "remote parent is propagated without provider")
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	err := errors.New("boom")
+	End(span, &err)
+
+	ended := rec.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, "boom", ended[0].Status().Description)
+}
+
+func TestNewExporter_OTLP(t *testing.T) {
+	got := make(chan string, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got <- r.URL.Path
+	}))
+	defer srv.Close()
+
+	exp, err := NewExporter(context.Background(), ExporterOTLP, srv.URL+"/v1/traces")
+	require.NoError(t, err)
+
+	tp := NewProvider(exp, 1)
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	select {
+	case path := <-got:
+		assert.Equal(t, "/v1/traces", path)
+	case <-time.After(time.Second):
+		t.Fatal("spans are not exported")
+	}
+
+	_, err = NewExporter(context.Background(), "zipkin", "")
+	assert.ErrorIs(t, err, ErrUnknownExporter)
+}
+
+func TestNewExporter_OTLPTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	timeout := exportTimeout
+	exportTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { exportTimeout = timeout })
+
+	exp, err := NewExporter(context.Background(), ExporterOTLP, srv.URL+"/v1/traces")
+	require.NoError(t, err)
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	done := make(chan error, 1)
+	go func() { done <- exp.ExportSpans(context.Background(), rec.Ended()) }()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err, "request to hung collector is cut by timeout")
+	case <-time.After(5 * time.Second):
+		t.Fatal("export has no timeout")
+	}
+}
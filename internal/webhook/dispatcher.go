@@ -0,0 +1,233 @@
+// Package webhook delivers link lifecycle events to user endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/samborkent/uuidv7"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/netguard"
+	"github.com/Pklerik/urlshortener/internal/repository"
+	"github.com/Pklerik/urlshortener/internal/tracing"
+)
+
+const (
+	// HeaderEvent header with event type.
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderDelivery header with delivery id, the same for all attempts.
+	HeaderDelivery = "X-Webhook-Delivery"
+	// HeaderTimestamp header with unix time of attempt.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature header with "sha256=" prefixed signature of attempt.
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	// ErrUnexpectedStatus - endpoint responded with non 2xx status.
+	ErrUnexpectedStatus = errors.New("unexpected webhook response status")
+	// ErrDeliveryTimeout - endpoint didn't respond in time.
+	ErrDeliveryTimeout = errors.New("webhook request timed out")
+	// ErrDeliveryFailed - request wasn't sent or response wasn't received.
+	ErrDeliveryFailed = errors.New("webhook request failed")
+)
+
+// Enqueuer provide contract for durable background jobs.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, jobType string, userID model.UserID, payload any) (model.Job, error)
+}
+
+// deliveryJob payload of JobTypeWebhookDelivery job.
+type deliveryJob struct {
+	DeliveryID model.UUIDv7 `json:"delivery_id"`
+	// Traceparent of request which caused event, delivery continues its trace.
+	Traceparent string `json:"traceparent,omitempty"`
+}
+
+// Dispatcher turns events into deliveries and sends them from job queue workers.
+type Dispatcher struct {
+	repo   repository.LinksRepository
+	jobs   Enqueuer
+	client *http.Client
+}
+
+// NewDispatcher - provide instance of Dispatcher.
+func NewDispatcher(repo repository.LinksRepository, jobs Enqueuer, client *http.Client) *Dispatcher {
+	return &Dispatcher{repo: repo, jobs: jobs, client: client}
+}
+
+// HandleEvent stores delivery and enqueues job for every webhook subscribed to ev.
+func (d *Dispatcher) HandleEvent(ctx context.Context, ev model.Event) {
+	whs, err := d.repo.SelectEventWebhooks(ctx, ev.Link.UserID, ev.Link.WorkspaceID)
+	if err != nil {
+		logger.Sugar.Errorf("Unable to select webhooks for %s: %v", ev.String(), err)
+		return
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		logger.Sugar.Errorf("Unable to encode %s: %v", ev.String(), err)
+		return
+	}
+
+	for _, wh := range whs {
+		if !wh.Events.Contains(ev.Type) {
+			continue
+		}
+
+		delivery, err := d.repo.CreateDelivery(ctx, model.WebhookDelivery{
+			ID:        model.UUIDv7(uuidv7.New().String()),
+			WebhookID: wh.ID,
+			EventID:   ev.ID,
+			EventType: ev.Type,
+			Payload:   string(payload),
+			Status:    model.DeliveryPending,
+		})
+		if err != nil {
+			logger.Sugar.Errorf("Unable to create delivery of %s to %s: %v", ev.String(), wh.String(), err)
+			continue
+		}
+
+		job := deliveryJob{DeliveryID: delivery.ID, Traceparent: tracing.Traceparent(ctx)}
+
+		if _, err := d.jobs.Enqueue(ctx, model.JobTypeWebhookDelivery, wh.UserID, job); err != nil {
+			logger.Sugar.Errorf("Unable to enqueue %s: %v", delivery.String(), err)
+		}
+	}
+}
+
+// Deliver sends delivery from job and records attempt. Returned error makes job queue retry with backoff.
+func (d *Dispatcher) Deliver(ctx context.Context, job model.Job) (err error) {
+	var payload deliveryJob
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("Deliver: %w", err)
+	}
+
+	ctx, span := tracing.Start(tracing.ContextWithTraceparent(ctx, payload.Traceparent), "webhook.Deliver", trace.WithAttributes(
+		attribute.String("webhook.delivery_id", string(payload.DeliveryID)),
+		attribute.Int("job.attempt", job.Attempts),
+	))
+	defer tracing.End(span, &err)
+
+	delivery, err := d.repo.SelectDelivery(ctx, payload.DeliveryID)
+	if errors.Is(err, repository.ErrNotFoundDelivery) {
+		// вебхук удалён вместе с журналом доставок.
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("Deliver: %w", err)
+	}
+
+	wh, err := d.repo.SelectWebhook(ctx, delivery.WebhookID)
+	if errors.Is(err, repository.ErrNotFoundWebhook) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("Deliver: %w", err)
+	}
+
+	delivery.ResponseCode, err = d.send(ctx, wh, delivery)
+	delivery.Attempts = job.Attempts
+
+	if err != nil {
+		logger.Sugar.Warnf("Unable to send %s to %s: %v", delivery.String(), wh.String(), err)
+		err = publicError(err)
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliverySucceeded
+		delivery.LastError = ""
+	case job.Attempts >= job.MaxAttempts:
+		delivery.Status = model.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.Status = model.DeliveryPending
+		delivery.LastError = err.Error()
+	}
+
+	if uerr := d.repo.UpdateDelivery(context.WithoutCancel(ctx), delivery); uerr != nil {
+		logger.Sugar.Errorf("Unable to update %s: %v", delivery.String(), uerr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Deliver: %w", err)
+	}
+
+	return nil
+}
+
+// send posts delivery payload to webhook url and returns response status code.
+func (d *Dispatcher) send(ctx context.Context, wh model.Webhook, delivery model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("send: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, string(delivery.ID))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(wh.Secret, timestamp, body))
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("send: %w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// publicError hides transport details shown to webhook owner in delivery log and job status:
+// dial errors would let user probe which internal addresses and ports are open.
+func publicError(err error) error {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, ErrUnexpectedStatus), errors.Is(err, netguard.ErrForbiddenAddress):
+		return err
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrDeliveryTimeout
+	default:
+		return ErrDeliveryFailed
+	}
+}
+
+// Sign returns hex encoded HMAC-SHA256 of "<timestamp>.<body>" with webhook secret.
+// Receivers recompute it to verify sender and reject replayed requests by timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
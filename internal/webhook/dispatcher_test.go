@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pklerik/urlshortener/internal/events"
+	"github.com/Pklerik/urlshortener/internal/jobqueue"
+	"github.com/Pklerik/urlshortener/internal/logger"
+	"github.com/Pklerik/urlshortener/internal/model"
+	"github.com/Pklerik/urlshortener/internal/netguard"
+	"github.com/Pklerik/urlshortener/internal/repository/inmemory"
+	"github.com/Pklerik/urlshortener/internal/service/links"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver resolves names from map.
+type staticResolver map[string][]netip.Addr
+
+func (sr staticResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	ips, ok := sr[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return ips, nil
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	logger.Initialize("ERROR")
+
+	const userID = model.UserID("0199996a-fd98-780c-b5aa-1aef966fb36e")
+
+	var (
+		calls  atomic.Int32
+		secret string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		sig := "sha256=" + Sign(secret, r.Header.Get(HeaderTimestamp), body)
+		assert.Equal(t, sig, r.Header.Get(HeaderSignature))
+		assert.Equal(t, string(model.EventLinkCreated), r.Header.Get(HeaderEvent))
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	repo := inmemory.NewInMemoryLinksRepository()
+	bus := events.NewBus()
+	// вебхук регистрируется на публичное имя, соединения уходят на тестовый сервер.
+	ls := links.NewLinksService(repo, "fH72anZI1e6YFLN+Psh6Dv308js8Ul+q3mfPe8E36Qs=", links.WithPublisher(bus),
+		links.WithResolver(staticResolver{"hooks.example": {netip.MustParseAddr("93.184.216.34")}}),
+	)
+	queue := jobqueue.New(inmemory.NewInMemoryJobsRepository(),
+		jobqueue.WithBackoff(time.Millisecond, time.Millisecond), jobqueue.WithPollInterval(time.Millisecond),
+	)
+	client := srv.Client()
+	client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) { // nolint:errcheck // httptest client
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	dispatcher := NewDispatcher(repo, queue, client)
+
+	bus.Subscribe(dispatcher.HandleEvent)
+	queue.Register(model.JobTypeWebhookDelivery, dispatcher.Deliver)
+
+	wh, err := ls.CreateWebhook(ctx, userID, model.ReqWebhook{URL: "http://hooks.example/hook", Events: model.EventTypes{model.EventLinkCreated}})
+	require.NoError(t, err)
+
+	secret = wh.Secret
+
+	_, err = ls.RegisterLinks(ctx, []string{"http://ya.ru"}, userID)
+	require.NoError(t, err)
+	require.NoError(t, ls.MarkAsDeleted(ctx, userID, model.ShortUrls{"398f0ca4"}))
+
+	require.NoError(t, queue.Start(ctx))
+	defer queue.Drain(ctx)
+
+	var deliveries []model.WebhookDelivery
+
+	require.Eventually(t, func() bool {
+		deliveries, err = ls.ListDeliveries(ctx, userID, wh.ID)
+		return err == nil && len(deliveries) == 1 && deliveries[0].Status == model.DeliverySucceeded
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, 2, deliveries[0].Attempts, "first attempt is retried")
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseCode)
+	assert.True(t, strings.Contains(deliveries[0].Payload, `"short_url":"398f0ca4"`))
+	assert.EqualValues(t, 2, calls.Load(), "link.deleted is not subscribed")
+}
+
+func Test_publicError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+		name string
+	}{
+		{name: "status", err: fmt.Errorf("send: %w: %d", ErrUnexpectedStatus, http.StatusBadGateway), want: ErrUnexpectedStatus},
+		{name: "forbidden", err: fmt.Errorf("send: %w", netguard.ErrForbiddenAddress), want: netguard.ErrForbiddenAddress},
+		{name: "timeout", err: fmt.Errorf("send: %w", context.DeadlineExceeded), want: ErrDeliveryTimeout},
+		{name: "refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}, want: ErrDeliveryFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := publicError(tt.err)
+			require.ErrorIs(t, got, tt.want)
+			assert.NotContains(t, got.Error(), "connection refused")
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upLinksTimestampsTextURL, downLinksTimestampsTextURL)
+}
+
+func upLinksTimestampsTextURL(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links ALTER COLUMN long_url TYPE text;`)
+	if err != nil {
+		return fmt.Errorf("up alter column long_url error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links
+		ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT now(),
+		ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT now(),
+		ADD COLUMN IF NOT EXISTS deleted_at timestamptz;`)
+	if err != nil {
+		return fmt.Errorf("up add timestamp columns error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE links SET deleted_at = now() WHERE is_deleted AND deleted_at IS NULL;`)
+	if err != nil {
+		return fmt.Errorf("up fill deleted_at error: %w", err)
+	}
+
+	// Удаляем тестовую запись из первой миграции.
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM links WHERE id = '019906ca-14b2-7589-b77d-32e3fe12402a';`)
+	if err != nil {
+		return fmt.Errorf("up delete seeded link error: %w", err)
+	}
+
+	return nil
+}
+
+func downLinksTimestampsTextURL(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links
+		DROP COLUMN IF EXISTS created_at,
+		DROP COLUMN IF EXISTS updated_at,
+		DROP COLUMN IF EXISTS deleted_at;`)
+	if err != nil {
+		return fmt.Errorf("down drop timestamp columns error: %w", err)
+	}
+
+	// Длинные ссылки обрезаются, иначе откат невозможен.
+	_, err = tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links
+		ALTER COLUMN long_url TYPE VARCHAR(255) USING left(long_url, 255);`)
+	if err != nil {
+		return fmt.Errorf("down alter column long_url error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upLinksUserForeignKey, downLinksUserForeignKey)
+}
+
+func upLinksUserForeignKey(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO users (id)
+		SELECT DISTINCT user_id FROM links WHERE user_id IS NOT NULL
+		ON CONFLICT (id) DO NOTHING;`)
+	if err != nil {
+		return fmt.Errorf("up transfer missing users error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`ALTER TABLE links
+			ADD CONSTRAINT fk_links_user_id
+			FOREIGN KEY (user_id)
+			REFERENCES users (id)
+			ON DELETE CASCADE
+			ON UPDATE NO ACTION;`)
+	if err != nil {
+		return fmt.Errorf("up add fk_links_user_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_links_user_id ON links (user_id);`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_links_user_id error: %w", err)
+	}
+
+	return nil
+}
+
+func downLinksUserForeignKey(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`DROP INDEX IF EXISTS idx_links_user_id;`)
+	if err != nil {
+		return fmt.Errorf("down drop index idx_links_user_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links DROP CONSTRAINT IF EXISTS fk_links_user_id;`)
+	if err != nil {
+		return fmt.Errorf("down drop fk_links_user_id error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upWorkspaces, downWorkspaces)
+}
+
+func upWorkspaces(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS workspaces (
+			id UUID PRIMARY KEY,
+			name TEXT NOT NULL,
+			owner_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT now());`)
+	if err != nil {
+		return fmt.Errorf("up create table workspaces error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS workspace_members (
+			workspace_id UUID NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (workspace_id, user_id));`)
+	if err != nil {
+		return fmt.Errorf("up create table workspace_members error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members (user_id);`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_workspace_members_user_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS workspace_invitations (
+			token VARCHAR(64) PRIMARY KEY,
+			workspace_id UUID NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+			role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+			invited_by UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			expires_at timestamptz NOT NULL);`)
+	if err != nil {
+		return fmt.Errorf("up create table workspace_invitations error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links
+		ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces (id) ON DELETE CASCADE;`)
+	if err != nil {
+		return fmt.Errorf("up add column workspace_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_links_workspace_id ON links (workspace_id);`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_links_workspace_id error: %w", err)
+	}
+
+	return nil
+}
+
+func downWorkspaces(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`ALTER TABLE IF EXISTS links DROP COLUMN IF EXISTS workspace_id;`)
+	if err != nil {
+		return fmt.Errorf("down drop column workspace_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DROP TABLE IF EXISTS workspace_invitations, workspace_members, workspaces CASCADE;`)
+	if err != nil {
+		return fmt.Errorf("down drop workspaces tables error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upLinkRevisions, downLinkRevisions)
+}
+
+func upLinkRevisions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS link_revisions (
+			id UUID PRIMARY KEY,
+			link_id UUID NOT NULL REFERENCES links (id) ON DELETE CASCADE,
+			old_url TEXT NOT NULL,
+			new_url TEXT NOT NULL,
+			changed_by UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			created_at timestamptz NOT NULL DEFAULT now());`)
+	if err != nil {
+		return fmt.Errorf("up create table link_revisions error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_link_revisions_link_id ON link_revisions (link_id, created_at);`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_link_revisions_link_id error: %w", err)
+	}
+
+	return nil
+}
+
+func downLinkRevisions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS link_revisions;`)
+	if err != nil {
+		return fmt.Errorf("down drop table link_revisions error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upJobs, downJobs)
+}
+
+func upJobs(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS jobs (
+			id UUID PRIMARY KEY,
+			type VARCHAR(64) NOT NULL,
+			user_id UUID,
+			payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+			status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'running', 'done', 'failed')),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			run_at timestamptz NOT NULL DEFAULT now(),
+			created_at timestamptz NOT NULL DEFAULT now(),
+			updated_at timestamptz NOT NULL DEFAULT now());`)
+	if err != nil {
+		return fmt.Errorf("up create table jobs error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_pending_run_at ON jobs (run_at) WHERE status = 'pending';`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_jobs_pending_run_at error: %w", err)
+	}
+
+	return nil
+}
+
+func downJobs(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS jobs;`)
+	if err != nil {
+		return fmt.Errorf("down drop table jobs error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upWebhooks, downWebhooks)
+}
+
+func upWebhooks(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			workspace_id UUID REFERENCES workspaces (id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			secret VARCHAR(128) NOT NULL,
+			events TEXT NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now());`)
+	if err != nil {
+		return fmt.Errorf("up create table webhooks error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_webhooks_user_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_webhooks_workspace_id ON webhooks (workspace_id) WHERE workspace_id IS NOT NULL;`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_webhooks_workspace_id error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id UUID PRIMARY KEY,
+			webhook_id UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+			event_id UUID NOT NULL,
+			event_type VARCHAR(32) NOT NULL,
+			payload JSONB NOT NULL,
+			status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed')),
+			response_code INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			updated_at timestamptz NOT NULL DEFAULT now());`)
+	if err != nil {
+		return fmt.Errorf("up create table webhook_deliveries error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at DESC);`)
+	if err != nil {
+		return fmt.Errorf("up create index idx_webhook_deliveries_webhook_id error: %w", err)
+	}
+
+	return nil
+}
+
+func downWebhooks(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS webhook_deliveries;`)
+	if err != nil {
+		return fmt.Errorf("down drop table webhook_deliveries error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DROP TABLE IF EXISTS webhooks;`)
+	if err != nil {
+		return fmt.Errorf("down drop table webhooks error: %w", err)
+	}
+
+	return nil
+}
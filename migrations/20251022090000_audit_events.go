@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAuditEvents, downAuditEvents)
+}
+
+func upAuditEvents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS audit_events (
+			id BIGSERIAL PRIMARY KEY,
+			action VARCHAR(64) NOT NULL,
+			user_id TEXT NOT NULL DEFAULT '',
+			status INTEGER NOT NULL DEFAULT 0,
+			request_id TEXT NOT NULL DEFAULT '',
+			client_ip TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL DEFAULT '',
+			short_url TEXT NOT NULL DEFAULT '',
+			version INTEGER NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now());`)
+	if err != nil {
+		return fmt.Errorf("up create table audit_events error: %w", err)
+	}
+
+	for _, idx := range []struct{ name, columns string }{
+		{"idx_audit_events_user_id", "user_id, id"},
+		{"idx_audit_events_action", "action, id"},
+		{"idx_audit_events_short_url", "short_url, id"},
+		{"idx_audit_events_created_at", "created_at"},
+	} {
+		_, err = tx.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS `+idx.name+` ON audit_events (`+idx.columns+`);`)
+		if err != nil {
+			return fmt.Errorf("up create index %s error: %w", idx.name, err)
+		}
+	}
+
+	return nil
+}
+
+func downAuditEvents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS audit_events;`)
+	if err != nil {
+		return fmt.Errorf("down drop table audit_events error: %w", err)
+	}
+
+	return nil
+}
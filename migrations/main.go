@@ -10,26 +10,191 @@ import (
 	"github.com/Pklerik/urlshortener/internal/config/dbconf"
 	"github.com/Pklerik/urlshortener/internal/logger"
 	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
 )
 
-// ErrEmptyDB db is nil.
-var ErrEmptyDB = errors.New("db is nil: %w")
+var (
+	// ErrEmptyDB db is nil.
+	ErrEmptyDB = errors.New("db is nil")
+	// ErrNoAppliedMigrations there is no applied migrations to roll back.
+	ErrNoAppliedMigrations = errors.New("no applied migrations")
+)
+
+// migrationsLockID advisory lock id shared by all app instances running migrations.
+const migrationsLockID int64 = 6_512_437_190
 
 // MakeMigrations makes migrations in current dir.
 func MakeMigrations(ctx context.Context, db *sql.DB, dbConf dbconf.DBConfigurer) error {
+	err := Migrate(ctx, db, dbConf, func(ctx context.Context, provider *goose.Provider) error {
+		results, err := provider.Up(ctx)
+		if err != nil {
+			logger.Sugar.Errorf("Can't make migrations to db server: %v", err)
+			return fmt.Errorf("can't make migrations to db server: %w", err)
+		}
+
+		for _, result := range results {
+			logger.Sugar.Infof("Migration applied: %s", result)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("MakeMigrations: %w", err)
+	}
+
+	logger.Sugar.Infof("SUCCESS making migration to db: %v", db.Stats())
+
+	return nil
+}
+
+// Migrate runs fn holding postgres advisory lock, so only one instance migrates at a time.
+// Scheme is created under the same lock, and all operations of fn, like down and up of Redo,
+// are done without other migrator in between.
+func Migrate(ctx context.Context, db *sql.DB, dbConf dbconf.DBConfigurer, fn func(context.Context, *goose.Provider) error) error {
 	if db == nil {
-		logger.Sugar.Error("db is nil: %w")
+		logger.Sugar.Error(ErrEmptyDB)
 		return ErrEmptyDB
 	}
 
+	// сессионная блокировка живёт на отдельном соединении, goose работает через пул.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("Migrate: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID); err != nil {
+		return fmt.Errorf("Migrate lock: %w", err)
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationsLockID); err != nil {
+			logger.Sugar.Errorf("Can't release migrations lock: %v", err)
+		}
+	}()
+
+	scheme := fmt.Sprintf(`"%s"`, dbConf.GetOptions()["search_path"])
+	if err := createScheme(ctx, db, scheme, dbConf.GetUser()); err != nil {
+		return fmt.Errorf("Migrate: %w", err)
+	}
+
+	provider, err := NewProvider(db, dbConf)
+	if err != nil {
+		return fmt.Errorf("Migrate: %w", err)
+	}
+
+	return fn(ctx, provider)
+}
+
+// NewProvider returns goose.Provider for registered Go migrations.
+// Provider doesn't lock and doesn't create scheme, changing operations must run inside Migrate.
+func NewProvider(db *sql.DB, dbConf dbconf.DBConfigurer) (*goose.Provider, error) {
+	if db == nil {
+		logger.Sugar.Error(ErrEmptyDB)
+		return nil, ErrEmptyDB
+	}
+
+	store, err := newStore(dbConf)
+	if err != nil {
+		return nil, fmt.Errorf("NewProvider: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectCustom, db, nil, goose.WithStore(store))
+	if err != nil {
+		return nil, fmt.Errorf("NewProvider: %w", err)
+	}
+
+	return provider, nil
+}
+
+// Versions returns applied db version and version of the latest registered migration.
+// It doesn't create scheme and doesn't take migrations lock, so it is cheap enough for health checks.
+func Versions(ctx context.Context, db *sql.DB, dbConf dbconf.DBConfigurer) (current, latest int64, err error) {
+	if db == nil {
+		return 0, 0, ErrEmptyDB
+	}
+
+	store, err := newStore(dbConf)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Versions: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectCustom, db, nil, goose.WithStore(store))
+	if err != nil {
+		return 0, 0, fmt.Errorf("Versions: %w", err)
+	}
+
+	if sources := provider.ListSources(); len(sources) > 0 {
+		latest = sources[len(sources)-1].Version
+	}
+
+	current, err = store.GetLatestVersion(ctx, db)
+	if err != nil && !errors.Is(err, database.ErrVersionNotFound) {
+		return 0, latest, fmt.Errorf("Versions: %w", err)
+	}
+
+	return current, latest, nil
+}
+
+// Status returns state of every registered migration.
+// Like Versions it only reads versions table: no lock, no scheme or table creation.
+func Status(ctx context.Context, db *sql.DB, dbConf dbconf.DBConfigurer) ([]*goose.MigrationStatus, error) {
+	if db == nil {
+		return nil, ErrEmptyDB
+	}
+
+	store, err := newStore(dbConf)
+	if err != nil {
+		return nil, fmt.Errorf("Status: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectCustom, db, nil, goose.WithStore(store))
+	if err != nil {
+		return nil, fmt.Errorf("Status: %w", err)
+	}
+
+	sources := provider.ListSources()
+	statuses := make([]*goose.MigrationStatus, 0, len(sources))
+
+	for _, source := range sources {
+		status := &goose.MigrationStatus{Source: source, State: goose.StatePending}
+
+		applied, err := store.GetMigration(ctx, db, source.Version)
+		if err != nil && !errors.Is(err, database.ErrVersionNotFound) {
+			return nil, fmt.Errorf("Status: %w", err)
+		}
+
+		if applied != nil {
+			status.State = goose.StateApplied
+			status.AppliedAt = applied.Timestamp
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// newStore provide goose versions table in app scheme.
+func newStore(dbConf dbconf.DBConfigurer) (database.Store, error) {
+	scheme := fmt.Sprintf(`"%s"`, dbConf.GetOptions()["search_path"])
+
+	store, err := database.NewStore(goose.DialectPostgres, fmt.Sprintf("%s.goose_db_version", scheme))
+	if err != nil {
+		return nil, fmt.Errorf("newStore: %w", err)
+	}
+
+	return store, nil
+}
+
+func createScheme(ctx context.Context, db *sql.DB, scheme, user string) error {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("creating tx error: %w", err)
 	}
+	defer tx.Rollback()
 
-	scheme := fmt.Sprintf(`"%s"`, dbConf.GetOptions()["search_path"])
-	user := fmt.Sprintf(`"%s"`, dbConf.GetUser())
-	query := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s AUTHORIZATION %s;", scheme, user)
+	query := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s AUTHORIZATION \"%s\";", scheme, user)
 
 	logger.Sugar.Infof("scheme: %s", scheme)
 
@@ -38,24 +203,29 @@ func MakeMigrations(ctx context.Context, db *sql.DB, dbConf dbconf.DBConfigurer)
 		return fmt.Errorf("up create schema error: %w", err)
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		if err = tx.Rollback(); err != nil {
-			return fmt.Errorf("up create schema error on rollback transaction: %w", err)
-		}
-
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("up create schema error committing transaction: %w", err)
 	}
 
-	goose.SetTableName(fmt.Sprintf("%s.goose_db_version", scheme))
+	return nil
+}
 
-	err = goose.Up(db, ".")
+// Redo rolls back the latest applied migration and applies it again.
+// Run it inside Migrate, so no other migrator runs between down and up.
+func Redo(ctx context.Context, provider *goose.Provider) ([]*goose.MigrationResult, error) {
+	down, err := provider.Down(ctx)
 	if err != nil {
-		logger.Sugar.Errorf("Can't make migrations to db server: %w", err)
-		return fmt.Errorf("can't make migrations to db server: %w", err)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			return nil, ErrNoAppliedMigrations
+		}
+
+		return nil, fmt.Errorf("redo down: %w", err)
 	}
 
-	logger.Sugar.Infof("SUCCESS making migration to db: %v", db.Stats())
+	up, err := provider.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down}, fmt.Errorf("redo up: %w", err)
+	}
 
-	return nil
+	return []*goose.MigrationResult{down, up}, nil
 }